@@ -0,0 +1,31 @@
+package mysqldriver
+
+import (
+	"strings"
+)
+
+// FlushTables closes all open tables of the server by sending
+// "FLUSH TABLES" statement. When table names are given,
+// only these tables are flushed. It requires RELOAD privilege.
+//
+//	conn.FlushTables()                      // FLUSH TABLES
+//	conn.FlushTables("people", "test.dogs") // FLUSH TABLES `people`, `test`.`dogs`
+func (c *Conn) FlushTables(tables ...string) error {
+	sql := "FLUSH TABLES"
+	if len(tables) > 0 {
+		names := make([]string, len(tables))
+		for i, table := range tables {
+			names[i] = quoteIdentifier(table)
+		}
+		sql += " " + strings.Join(names, ", ")
+	}
+	_, err := c.Exec(sql)
+	return err
+}
+
+// FlushPrivileges reloads the grant tables by sending
+// "FLUSH PRIVILEGES" statement. It requires RELOAD privilege.
+func (c *Conn) FlushPrivileges() error {
+	_, err := c.Exec("FLUSH PRIVILEGES")
+	return err
+}
@@ -0,0 +1,36 @@
+package mysqldriver
+
+import (
+	"testing"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnFlushTables(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		assert.NoError(t, conn.FlushTables())
+		assert.NoError(t, conn.FlushTables("people", "test.categories"))
+		assert.True(t, conn.valid)
+
+		err := conn.FlushTables("unknown_table")
+		_, ok := err.(mysqlproto.ERRPacket)
+		assert.True(t, ok)
+		assert.True(t, conn.valid)
+
+		rows, err := conn.Query("SELECT COUNT(*) FROM people")
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.Int(), 0)
+		assert.False(t, rows.Next())
+	})
+}
+
+func TestConnFlushPrivileges(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		assert.NoError(t, conn.FlushPrivileges())
+		assert.True(t, conn.valid)
+		_, err := conn.Exec("SET @flushed = 1")
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,137 @@
+package mysqldriver
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrTooManyRows is returned by QueryRows and MapScanAll when
+// the result set exceeds MaxRows of the connection
+var ErrTooManyRows = errors.New("mysqldriver: result set exceeds MaxRows")
+
+// Any returns value of the column converted into Go type
+// which is natural for the column type:
+//
+//	NULL                              nil
+//	TINYINT ... BIGINT, YEAR          int64 (uint64 for UNSIGNED columns)
+//	FLOAT, DOUBLE                     float64
+//	DATE, DATETIME, TIMESTAMP         time.Time (see func (Rows) Time)
+//	BINARY, VARBINARY, BLOB, BIT      []byte
+//	GEOMETRY                          []byte
+//	all other types including DECIMAL string
+//
+// DECIMAL values are returned as strings to keep their precision.
+// When value can't be converted, it's returned as a string
+// and parse error is returned by LastError.
+func (r *Rows) Any() interface{} {
+	column, ok := r.NextColumn()
+	if !ok {
+		return nil
+	}
+
+	value, null := r.NullBytes()
+	if null {
+		return nil
+	}
+
+	converted, err := r.conn.convertValue(column, value)
+	if err != nil {
+		r.errParse = err
+		return string(value)
+	}
+	return converted
+}
+
+func (c *Conn) convertValue(column ColumnInfo, value []byte) (interface{}, error) {
+	switch column.Type {
+	case fieldTypeTiny, fieldTypeShort, fieldTypeInt24, fieldTypeLong, fieldTypeLongLong, fieldTypeYear:
+		if column.Unsigned() {
+			return strconv.ParseUint(string(value), 10, 64)
+		}
+		return strconv.ParseInt(string(value), 10, 64)
+	case fieldTypeFloat, fieldTypeDouble:
+		return strconv.ParseFloat(string(value), 64)
+	case fieldTypeDate, fieldTypeNewDate, fieldTypeDateTime, fieldTypeTimestamp:
+		return parseDateTime(value, c.location())
+	}
+
+	if isBinaryColumn(column) {
+		// value references the packet which is reused for the next row
+		return append([]byte(nil), value...), nil
+	}
+	return string(value), nil
+}
+
+// QueryRows performs the query and reads all rows of the result set
+// into memory. Values are converted the same way as Any does.
+// It's convenient to display result of any query, e.g. by REPL:
+//
+//	columns, rows, err := conn.QueryRows("SELECT * FROM dogs")
+//	for _, row := range rows {
+//		for i, value := range row {
+//			fmt.Println(columns[i].Name, value)
+//		}
+//	}
+//
+// When MaxRows of the connection is set and the result set is bigger,
+// the first MaxRows rows are returned with ErrTooManyRows
+// and the rest of the rows are discarded.
+func (c *Conn) QueryRows(sql string) ([]ColumnInfo, [][]interface{}, error) {
+	rows, err := c.Query(sql)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	columns := rows.Columns()
+	var result [][]interface{}
+	for rows.Next() {
+		if c.MaxRows > 0 && len(result) >= c.MaxRows {
+			rows.discard()
+			if rows.errRead != nil {
+				return columns, result, rows.errRead
+			}
+			return columns, result, ErrTooManyRows
+		}
+
+		row := make([]interface{}, len(columns))
+		for i := range row {
+			row[i] = rows.Any()
+		}
+		result = append(result, row)
+	}
+
+	return columns, result, rows.LastError()
+}
+
+// MapScanAll reads the rest of the rows into maps of the column names
+// to the values converted the same way as Any does, so DECIMAL values
+// are strings to keep their precision. When several columns have
+// the same name, the last one is kept. Parse errors don't stop reading,
+// the error is returned after all rows are read (see func (Rows) LastError).
+// MaxRows of the connection limits the number of rows the same way
+// as it does for QueryRows.
+//
+//	rows, _ := conn.Query("SELECT name, age FROM dogs")
+//	dogs, err := rows.MapScanAll()
+//	dogs[0]["name"] // "Max"
+//	dogs[0]["age"]  // int64(3)
+func (r *Rows) MapScanAll() ([]map[string]interface{}, error) {
+	var result []map[string]interface{}
+	for r.Next() {
+		if r.conn.MaxRows > 0 && len(result) >= r.conn.MaxRows {
+			r.discard()
+			if r.errRead != nil {
+				return result, r.errRead
+			}
+			return result, ErrTooManyRows
+		}
+
+		row := make(map[string]interface{}, len(r.definitions))
+		for _, column := range r.definitions[r.readColumns:] {
+			row[column.Name] = r.Any()
+		}
+		result = append(result, row)
+	}
+
+	return result, r.LastError()
+}
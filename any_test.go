@@ -0,0 +1,150 @@
+package mysqldriver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func typedColumnDefinition(name string, fieldType byte, flags uint16, collation uint16) []byte {
+	payload := columnDefinition(name)
+	payload[len(payload)-12] = byte(collation)
+	payload[len(payload)-11] = byte(collation >> 8)
+	payload[len(payload)-6] = fieldType
+	payload[len(payload)-5] = byte(flags)
+	payload[len(payload)-4] = byte(flags >> 8)
+	return payload
+}
+
+func TestRowsAnyConvertsValuesByColumnType(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x08},
+		typedColumnDefinition("id", fieldTypeLongLong, flagUnsigned, binaryCharacterSet),
+		typedColumnDefinition("age", fieldTypeLong, 0, binaryCharacterSet),
+		typedColumnDefinition("weight", fieldTypeDouble, 0, binaryCharacterSet),
+		typedColumnDefinition("price", fieldTypeNewDecimal, 0, binaryCharacterSet),
+		typedColumnDefinition("born", fieldTypeDateTime, 0, binaryCharacterSet),
+		typedColumnDefinition("name", fieldTypeVarString, 0, 45),
+		typedColumnDefinition("photo", fieldTypeBLOB, 0, binaryCharacterSet),
+		typedColumnDefinition("note", fieldTypeVarString, 0, 45),
+		eofPacket,
+		[]byte{
+			0x01, '7',
+			0x02, '-', '3',
+			0x03, '2', '.', '5',
+			0x04, '9', '.', '9', '0',
+			0x13, '2', '0', '2', '0', '-', '0', '1', '-', '0', '2', ' ', '0', '3', ':', '0', '4', ':', '0', '5',
+			0x03, 'M', 'a', 'x',
+			0x02, 0x01, 0x02,
+			0xfb,
+		},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT * FROM dogs")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Any(), uint64(7))
+	assert.Equal(t, rows.Any(), int64(-3))
+	assert.Equal(t, rows.Any(), 2.5)
+	assert.Equal(t, rows.Any(), "9.90")
+	assert.Equal(t, rows.Any(), time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))
+	assert.Equal(t, rows.Any(), "Max")
+	assert.Equal(t, rows.Any(), []byte{0x01, 0x02})
+	assert.Nil(t, rows.Any())
+	assert.Nil(t, rows.Any()) // all columns are read
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.LastError())
+}
+
+func TestRowsAnyReturnsStringWhenValueCanNotBeParsed(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01},
+		typedColumnDefinition("age", fieldTypeLong, 0, binaryCharacterSet),
+		eofPacket,
+		[]byte{0x03, 'a', 'b', 'c'},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT age FROM dogs")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Any(), "abc")
+	assert.False(t, rows.Next())
+	assert.NotNil(t, rows.LastError())
+}
+
+func TestConnQueryRowsStopsAtMaxRows(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01},
+		typedColumnDefinition("name", fieldTypeVarString, 0, 45),
+		eofPacket,
+		[]byte{0x03, 'M', 'a', 'x'},
+		[]byte{0x04, 'B', 'e', 'l', 'l'},
+		[]byte{0x03, 'R', 'e', 'x'},
+		eofPacket,
+	)
+	conn.MaxRows = 2
+
+	columns, rows, err := conn.QueryRows("SELECT name FROM dogs")
+	assert.Equal(t, err, ErrTooManyRows)
+	assert.Equal(t, len(columns), 1)
+	assert.Equal(t, columns[0].Name, "name")
+	assert.Equal(t, rows, [][]interface{}{{"Max"}, {"Bell"}})
+	assert.NoError(t, conn.acquire())
+}
+
+func TestConnQueryRows(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec(`INSERT INTO people(firstname, age, grade) VALUES ("bob", 30, 4.5)`)
+		assert.NoError(t, err)
+
+		columns, rows, err := conn.QueryRows("SELECT firstname, age, grade FROM people ORDER BY id LIMIT 1")
+		assert.NoError(t, err)
+		assert.Equal(t, len(columns), 3)
+		assert.Equal(t, rows, [][]interface{}{{"bob", int64(30), "4.50"}})
+	})
+}
+
+func TestRowsMapScanAll(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x03},
+		typedColumnDefinition("name", fieldTypeVarString, 0, 45),
+		typedColumnDefinition("age", fieldTypeLong, 0, binaryCharacterSet),
+		typedColumnDefinition("price", fieldTypeNewDecimal, 0, binaryCharacterSet),
+		eofPacket,
+		[]byte{0x03, 'M', 'a', 'x', 0x01, '3', 0x04, '9', '.', '9', '0'},
+		[]byte{0x03, 'R', 'e', 'x', 0x03, 'o', 'l', 'd', 0xfb},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT name, age, price FROM dogs")
+	assert.NoError(t, err)
+	result, err := rows.MapScanAll()
+	assert.EqualError(t, err, `strconv.ParseInt: parsing "old": invalid syntax`)
+	assert.Equal(t, result, []map[string]interface{}{
+		{"name": "Max", "age": int64(3), "price": "9.90"},
+		{"name": "Rex", "age": "old", "price": nil},
+	})
+	assert.NoError(t, conn.acquire())
+}
+
+func TestRowsMapScanAllStopsAtMaxRows(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01},
+		typedColumnDefinition("name", fieldTypeVarString, 0, 45),
+		eofPacket,
+		[]byte{0x03, 'M', 'a', 'x'},
+		[]byte{0x03, 'R', 'e', 'x'},
+		eofPacket,
+	)
+	conn.MaxRows = 1
+
+	rows, err := conn.Query("SELECT name FROM dogs")
+	assert.NoError(t, err)
+	result, err := rows.MapScanAll()
+	assert.Equal(t, err, ErrTooManyRows)
+	assert.Equal(t, result, []map[string]interface{}{{"name": "Max"}})
+	assert.NoError(t, conn.acquire())
+}
@@ -0,0 +1,165 @@
+package mysqldriver
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/pubnative/mysqlproto-go"
+)
+
+// ArgCountError is returned by QueryArgs and ExecArgs when the number
+// of arguments doesn't match the number of placeholders.
+// The statement isn't sent to the server in this case.
+type ArgCountError struct {
+	Placeholders int // number of ? placeholders in the statement
+	Args         int // number of given arguments
+}
+
+func (e *ArgCountError) Error() string {
+	return fmt.Sprintf("mysqldriver: statement has %d placeholders but %d arguments are given", e.Placeholders, e.Args)
+}
+
+// QueryArgs performs the query the same way as Query does after
+// replacing ? placeholders with the arguments as SQL literals.
+// Placeholders inside of string literals, quoted identifiers
+// and comments are ignored.
+//
+// Supported arguments are nil (NULL), string, []byte (hex literal),
+// bool, integer and float types and time.Time. Strings are escaped
+// the same way as EscapeString does. Time is converted
+// into DATETIME literal in the location of the connection
+// (see Conn.Location), zero time is written as "0000-00-00 00:00:00".
+//
+//	rows, err := conn.QueryArgs("SELECT name FROM dogs WHERE age > ? AND owner = ?", 2, "Bob")
+func (c *Conn) QueryArgs(sql string, args ...interface{}) (*Rows, error) {
+	sql, err := c.interpolate(sql, args)
+	if err != nil {
+		return nil, err
+	}
+	return c.Query(sql)
+}
+
+// ExecArgs executes the query the same way as Exec does after
+// replacing ? placeholders with the arguments (see func (Conn) QueryArgs)
+//
+//	_, err := conn.ExecArgs("INSERT INTO dogs(name, age) VALUES (?, ?)", "Max", 3)
+func (c *Conn) ExecArgs(sql string, args ...interface{}) (mysqlproto.OKPacket, error) {
+	sql, err := c.interpolate(sql, args)
+	if err != nil {
+		return mysqlproto.OKPacket{}, err
+	}
+	return c.Exec(sql)
+}
+
+// interpolate replaces placeholders of the statement with the arguments
+func (c *Conn) interpolate(sql string, args []interface{}) (string, error) {
+	query := []byte(sql)
+	buf := make([]byte, 0, len(query))
+	count := 0
+
+	for i := 0; i < len(query); {
+		ch := query[i]
+		end := i + 1
+		switch {
+		case ch == '\'' || ch == '"' || ch == '`':
+			if end = skipQuoted(query, i); end < 0 {
+				end = len(query)
+			}
+		case ch == '#' || isLineComment(query[i:]):
+			if end = bytes.IndexByte(query[i:], '\n'); end < 0 {
+				end = len(query)
+			} else {
+				end += i
+			}
+		case bytes.HasPrefix(query[i:], []byte("/*")):
+			if end = bytes.Index(query[i+2:], []byte("*/")); end < 0 {
+				end = len(query)
+			} else {
+				end += i + 4
+			}
+		case ch == '?':
+			if count < len(args) {
+				var err error
+				if buf, err = c.appendLiteral(buf, args[count]); err != nil {
+					return "", fmt.Errorf("mysqldriver: argument %d: %v", count, err)
+				}
+			}
+			count++
+			i++
+			continue
+		}
+
+		buf = append(buf, query[i:end]...)
+		i = end
+	}
+
+	if count != len(args) {
+		return "", &ArgCountError{Placeholders: count, Args: len(args)}
+	}
+	return string(buf), nil
+}
+
+// appendLiteral appends SQL literal of the value
+func (c *Conn) appendLiteral(buf []byte, arg interface{}) ([]byte, error) {
+	switch v := arg.(type) {
+	case nil:
+		return append(buf, "NULL"...), nil
+	case string:
+		return append(buf, quoteString(v, c.noBackslashEscapes())...), nil
+	case []byte:
+		if v == nil {
+			return append(buf, "NULL"...), nil
+		}
+		buf = append(buf, "X'"...)
+		buf = append(buf, hex.EncodeToString(v)...)
+		return append(buf, '\''), nil
+	case bool:
+		if v {
+			return append(buf, '1'), nil
+		}
+		return append(buf, '0'), nil
+	case int:
+		return strconv.AppendInt(buf, int64(v), 10), nil
+	case int8:
+		return strconv.AppendInt(buf, int64(v), 10), nil
+	case int16:
+		return strconv.AppendInt(buf, int64(v), 10), nil
+	case int32:
+		return strconv.AppendInt(buf, int64(v), 10), nil
+	case int64:
+		return strconv.AppendInt(buf, v, 10), nil
+	case uint:
+		return strconv.AppendUint(buf, uint64(v), 10), nil
+	case uint8:
+		return strconv.AppendUint(buf, uint64(v), 10), nil
+	case uint16:
+		return strconv.AppendUint(buf, uint64(v), 10), nil
+	case uint32:
+		return strconv.AppendUint(buf, uint64(v), 10), nil
+	case uint64:
+		return strconv.AppendUint(buf, v, 10), nil
+	case float32:
+		return appendFloat(buf, float64(v), 32)
+	case float64:
+		return appendFloat(buf, v, 64)
+	case time.Time:
+		if v.IsZero() {
+			return append(buf, "'0000-00-00 00:00:00'"...), nil
+		}
+		buf = append(buf, '\'')
+		buf = v.In(c.location()).AppendFormat(buf, "2006-01-02 15:04:05.999999")
+		return append(buf, '\''), nil
+	}
+	return nil, fmt.Errorf("unsupported type %T", arg)
+}
+
+func appendFloat(buf []byte, num float64, bitSize int) ([]byte, error) {
+	if math.IsNaN(num) || math.IsInf(num, 0) {
+		return nil, fmt.Errorf("%v can't be represented in SQL", num)
+	}
+	return strconv.AppendFloat(buf, num, 'g', -1, bitSize), nil
+}
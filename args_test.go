@@ -0,0 +1,70 @@
+package mysqldriver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterpolate(t *testing.T) {
+	conn := &Conn{}
+	sql, err := conn.interpolate("SELECT ?, ?, ?, ?, ?, ?, ?, ?", []interface{}{
+		nil, "it's", []byte{0x00, 0xff}, true, -5, uint64(1 << 63), 1.5, time.Date(2017, 3, 4, 5, 6, 7, 8000, time.UTC),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `SELECT NULL, 'it\'s', X'00ff', 1, -5, 9223372036854775808, 1.5, '2017-03-04 05:06:07.000008'`)
+
+	sql, err = conn.interpolate("SELECT ?", []interface{}{time.Time{}})
+	assert.NoError(t, err)
+	assert.Equal(t, sql, "SELECT '0000-00-00 00:00:00'")
+
+	conn.status = serverStatusNoBackslashEscapes
+	sql, err = conn.interpolate("SELECT ?", []interface{}{`it's \`})
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `SELECT 'it''s \'`)
+}
+
+func TestInterpolateIgnoresQuotedPlaceholders(t *testing.T) {
+	conn := &Conn{}
+	sql, err := conn.interpolate("SELECT '?', \"it\\\"s ?\", `?` /* ? */, ? -- ?\n# ?\nFROM dogs", []interface{}{1})
+	assert.NoError(t, err)
+	assert.Equal(t, sql, "SELECT '?', \"it\\\"s ?\", `?` /* ? */, 1 -- ?\n# ?\nFROM dogs")
+
+	sql, err = conn.interpolate("SELECT ? --\r?\nFROM dogs", []interface{}{1})
+	assert.NoError(t, err)
+	assert.Equal(t, sql, "SELECT 1 --\r?\nFROM dogs")
+}
+
+func TestInterpolateReturnsError(t *testing.T) {
+	conn := &Conn{}
+	_, err := conn.interpolate("SELECT ?, ?", []interface{}{1})
+	assert.Equal(t, err, &ArgCountError{Placeholders: 2, Args: 1})
+	assert.EqualError(t, err, "mysqldriver: statement has 2 placeholders but 1 arguments are given")
+
+	_, err = conn.interpolate("SELECT 1", []interface{}{1})
+	assert.Equal(t, err, &ArgCountError{Placeholders: 0, Args: 1})
+
+	_, err = conn.interpolate("SELECT ?", []interface{}{struct{}{}})
+	assert.EqualError(t, err, "mysqldriver: argument 0: unsupported type struct {}")
+}
+
+func TestConnQueryArgs(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.ExecArgs("INSERT INTO people(firstname, lastname, age) VALUES (?, ?, ?)", `Bob "The" O'Neil\`, nil, 30)
+		assert.NoError(t, err)
+
+		rows, err := conn.QueryArgs("SELECT firstname, lastname FROM people WHERE age = ?", 30)
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.String(), `Bob "The" O'Neil\`)
+		_, null := rows.NullString()
+		assert.True(t, null)
+		assert.False(t, rows.Next())
+		assert.NoError(t, rows.LastError())
+
+		_, err = conn.QueryArgs("SELECT ?", 1, 2)
+		assert.Equal(t, err, &ArgCountError{Placeholders: 1, Args: 2})
+		assert.True(t, conn.valid)
+	})
+}
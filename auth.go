@@ -0,0 +1,362 @@
+package mysqldriver
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pubnative/mysqlproto-go"
+)
+
+// Authentication plugins supported by the driver
+// (see https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_connection_phase_authentication_methods.html)
+const (
+	authNativePassword      = "mysql_native_password"
+	authCachingSHA2Password = "caching_sha2_password"
+)
+
+const (
+	authMoreData      byte = 0x01
+	authSwitchRequest byte = 0xfe
+
+	// statuses and requests of caching_sha2_password exchange
+	cachingSHA2RequestPublicKey byte = 0x02
+	cachingSHA2FastAuthSuccess  byte = 0x03
+	cachingSHA2PerformFullAuth  byte = 0x04
+)
+
+const (
+	maxPacketSize   = 1<<24 - 1
+	charsetUTF8Code = 0x21 // utf8_general_ci
+)
+
+var errMalformedPublicKey = errors.New("mysqldriver: malformed public key of the server")
+
+// handshaker performs the connection phase of the protocol
+// (see https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_connection_phase.html)
+type handshaker struct {
+	stream   *mysqlproto.Stream
+	flags    uint32
+	seq      byte // sequence ID of the next packet sent by the client
+	password string
+
+	// MariaDB extended capabilities negotiated with the server
+	mariaDBFlags uint32
+
+	// password can be sent in plaintext
+	// over the encrypted or local connection
+	secure bool
+}
+
+// connectHandshake reads the initial handshake of the server,
+// upgrades the connection with TLS when config is set
+// and authenticates the user by the plugin requested by the server
+func connectHandshake(conn *netConn, flags uint32, collation byte, attrs []byte, username, password, database string,
+	config *tls.Config, local bool, readTimeout time.Duration) (mysqlproto.Conn, error) {
+
+	h := &handshaker{
+		stream:   mysqlproto.NewStream(conn, readTimeout),
+		password: password,
+		secure:   config != nil || local,
+	}
+
+	payload, err := h.read()
+	if err != nil {
+		return mysqlproto.Conn{Stream: h.stream, CapabilityFlags: flags}, err
+	}
+	if payload[0] == mysqlproto.ERR_PACKET { // e.g. too many connections
+		return mysqlproto.Conn{Stream: h.stream, CapabilityFlags: flags}, handleOK(payload, 0)
+	}
+	handshake, err := parseHandshake(payload)
+	if err != nil {
+		return mysqlproto.Conn{Stream: h.stream, CapabilityFlags: flags}, err
+	}
+
+	h.flags = flags & uint32(handshake.Capabilities)
+	if h.flags&clientZstdCompression != 0 {
+		// only one algorithm is negotiated, zlib is a fallback
+		h.flags &^= mysqlproto.CLIENT_COMPRESS
+	}
+	h.mariaDBFlags = handshake.MariaDBCapabilities & mariaDBClientCapabilities
+	if database == "" {
+		h.flags &^= mysqlproto.CLIENT_CONNECT_WITH_DB
+	}
+	result := mysqlproto.Conn{Stream: h.stream, CapabilityFlags: h.flags}
+
+	if config != nil {
+		if !handshake.Capabilities.Has(CapabilitySSL) {
+			return result, ErrTLSNotSupported
+		}
+		h.flags |= mysqlproto.CLIENT_SSL
+		result.CapabilityFlags = h.flags
+		if err := h.write(handshakeResponsePrefix(h.flags, collation, h.mariaDBFlags)); err != nil {
+			return result, err
+		}
+		if err := conn.startTLS(config); err != nil {
+			return result, err
+		}
+	}
+
+	// unknown default plugin is replaced by the server
+	// with the plugin of the account using auth switch request
+	plugin := handshake.AuthPlugin
+	if plugin != authCachingSHA2Password {
+		plugin = authNativePassword
+	}
+	auth, err := authResponse(plugin, password, handshake.Scramble)
+	if err != nil {
+		return result, err
+	}
+
+	response := handshakeResponsePrefix(h.flags, collation, h.mariaDBFlags)
+	response = append(append(response, username...), 0)
+	if h.flags&mysqlproto.CLIENT_PLUGIN_AUTH_LENENC_CLIENT_DATA != 0 {
+		response = appendLengthEncodedInteger(response, uint64(len(auth)))
+	} else {
+		response = append(response, byte(len(auth)))
+	}
+	response = append(response, auth...)
+	if h.flags&mysqlproto.CLIENT_CONNECT_WITH_DB != 0 {
+		response = append(append(response, database...), 0)
+	}
+	if h.flags&mysqlproto.CLIENT_PLUGIN_AUTH != 0 {
+		response = append(append(response, plugin...), 0)
+	}
+	if h.flags&mysqlproto.CLIENT_CONNECT_ATTRS != 0 {
+		response = appendConnectAttrs(response, attrs)
+	}
+	if h.flags&clientZstdCompression != 0 {
+		response = append(response, byte(conn.zstdLevel()))
+	}
+	if err := h.write(response); err != nil {
+		return result, err
+	}
+
+	if err := h.authenticate(plugin, handshake.Scramble); err != nil {
+		return result, err
+	}
+	switch {
+	case h.flags&clientZstdCompression != 0:
+		conn.startZstdCompression()
+	case h.flags&mysqlproto.CLIENT_COMPRESS != 0:
+		conn.startCompression()
+	}
+	return result, nil
+}
+
+// handshakeResponsePrefix returns the beginning of the handshake
+// response which is sent alone as SSLRequest packet: capability flags(4),
+// max packet size(4), character set(1) and filler(23). MariaDB reads
+// extended capabilities from the last 4 bytes of the filler.
+// (see https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_connection_phase_packets_protocol_ssl_request.html)
+func handshakeResponsePrefix(flags uint32, collation byte, mariaDBFlags uint32) []byte {
+	payload := make([]byte, sslRequestLen)
+	binary.LittleEndian.PutUint32(payload, flags)
+	binary.LittleEndian.PutUint32(payload[4:], maxPacketSize)
+	payload[8] = collation
+	binary.LittleEndian.PutUint32(payload[28:], mariaDBFlags)
+	return payload
+}
+
+// authenticate reads the result of authentication. Server can
+// switch to another plugin or continue the exchange of the plugin
+// before sending OK_PACKET.
+func (h *handshaker) authenticate(plugin string, scramble []byte) error {
+	for {
+		payload, err := h.read()
+		if err != nil {
+			return err
+		}
+
+		switch payload[0] {
+		case mysqlproto.OK_PACKET, mysqlproto.ERR_PACKET:
+			return handleOK(payload, h.flags)
+		case authSwitchRequest:
+			plugin, scramble, err = parseAuthSwitchRequest(payload)
+			if err != nil {
+				return err
+			}
+			auth, err := authResponse(plugin, h.password, scramble)
+			if err != nil {
+				return err
+			}
+			if err := h.write(auth); err != nil {
+				return err
+			}
+		case authMoreData:
+			if plugin != authCachingSHA2Password || len(payload) < 2 {
+				return errMalformedPacket
+			}
+			switch payload[1] {
+			case cachingSHA2FastAuthSuccess:
+				// OK_PACKET follows
+			case cachingSHA2PerformFullAuth:
+				if err := h.fullAuth(scramble); err != nil {
+					return err
+				}
+			default:
+				return errMalformedPacket
+			}
+		default:
+			return handleOK(payload, h.flags)
+		}
+	}
+}
+
+// fullAuth sends the password when caching_sha2_password
+// doesn't have it in the cache of the server. Password is sent
+// in plaintext over the secure connection, otherwise it's encrypted
+// by the public key requested from the server.
+func (h *handshaker) fullAuth(scramble []byte) error {
+	password := append([]byte(h.password), 0)
+	if h.secure {
+		return h.write(password)
+	}
+
+	if err := h.write([]byte{cachingSHA2RequestPublicKey}); err != nil {
+		return err
+	}
+	payload, err := h.read()
+	if err != nil {
+		return err
+	}
+	if payload[0] != authMoreData {
+		return handleOK(payload, h.flags)
+	}
+
+	key, err := parsePublicKey(payload[1:])
+	if err != nil {
+		return err
+	}
+	encrypted, err := encryptPassword(password, scramble, key)
+	if err != nil {
+		return err
+	}
+	return h.write(encrypted)
+}
+
+// read returns payload of the next packet sent by the server
+func (h *handshaker) read() ([]byte, error) {
+	packet, err := h.stream.NextPacket()
+	if err != nil {
+		return nil, err
+	}
+	if len(packet.Payload) == 0 {
+		return nil, errMalformedPacket
+	}
+	h.seq = packet.SequenceID + 1
+	return packet.Payload, nil
+}
+
+// write sends the payload in the packet with the next sequence ID
+func (h *handshaker) write(payload []byte) error {
+	length := len(payload)
+	packet := append([]byte{byte(length), byte(length >> 8), byte(length >> 16), h.seq}, payload...)
+	h.seq++
+	_, err := h.stream.Write(packet)
+	return err
+}
+
+// parseAuthSwitchRequest returns the plugin name and its data
+// (see https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_connection_phase_packets_protocol_auth_switch_request.html)
+func parseAuthSwitchRequest(payload []byte) (string, []byte, error) {
+	end := bytes.IndexByte(payload[1:], 0)
+	if end < 0 {
+		return "", nil, errMalformedPacket
+	}
+	plugin := string(payload[1 : 1+end])
+	// the last byte of the seed is a NUL terminator
+	scramble := bytes.TrimRight(payload[1+end+1:], "\x00")
+	return plugin, append([]byte(nil), scramble...), nil
+}
+
+// authResponse returns the response of the plugin to the scramble
+func authResponse(plugin, password string, scramble []byte) ([]byte, error) {
+	switch plugin {
+	case authNativePassword:
+		return scrambleNativePassword(password, scramble), nil
+	case authCachingSHA2Password:
+		return scrambleSHA256Password(password, scramble), nil
+	}
+	return nil, fmt.Errorf("mysqldriver: unsupported auth plugin %q", plugin)
+}
+
+// scrambleNativePassword computes the response of mysql_native_password:
+// SHA1(password) XOR SHA1(scramble + SHA1(SHA1(password)))
+func scrambleNativePassword(password string, scramble []byte) []byte {
+	if password == "" {
+		return nil
+	}
+	if len(scramble) > 20 {
+		scramble = scramble[:20]
+	}
+
+	stage1 := sha1.Sum([]byte(password))
+	stage2 := sha1.Sum(stage1[:])
+	hash := sha1.New()
+	hash.Write(scramble)
+	hash.Write(stage2[:])
+	result := hash.Sum(nil)
+	for i := range result {
+		result[i] ^= stage1[i]
+	}
+	return result
+}
+
+// scrambleSHA256Password computes the response of caching_sha2_password:
+// SHA256(password) XOR SHA256(SHA256(SHA256(password)) + scramble)
+func scrambleSHA256Password(password string, scramble []byte) []byte {
+	if password == "" {
+		return nil
+	}
+
+	stage1 := sha256.Sum256([]byte(password))
+	stage2 := sha256.Sum256(stage1[:])
+	hash := sha256.New()
+	hash.Write(stage2[:])
+	hash.Write(scramble)
+	result := hash.Sum(nil)
+	for i := range result {
+		result[i] ^= stage1[i]
+	}
+	return result
+}
+
+// parsePublicKey parses RSA public key of the server in PEM format
+func parsePublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errMalformedPublicKey
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errMalformedPublicKey
+	}
+	return rsaKey, nil
+}
+
+// encryptPassword XORs NUL terminated password with the scramble
+// and encrypts it by RSA public key of the server
+func encryptPassword(password, scramble []byte, key *rsa.PublicKey) ([]byte, error) {
+	if len(scramble) == 0 {
+		return nil, errMalformedPacket
+	}
+	plain := make([]byte, len(password))
+	for i := range password {
+		plain[i] = password[i] ^ scramble[i%len(scramble)]
+	}
+	return rsa.EncryptOAEP(sha1.New(), rand.Reader, key, plain, nil)
+}
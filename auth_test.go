@@ -0,0 +1,270 @@
+package mysqldriver
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+var handshakeScramble = []byte{
+	0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+	0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c,
+}
+
+var okPayload = []byte{mysqlproto.OK_PACKET, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00}
+
+// authServer is the server side of the connection phase
+type authServer struct {
+	t    *testing.T
+	conn net.Conn
+}
+
+func (s *authServer) read() (byte, []byte) {
+	header := make([]byte, 4)
+	_, err := io.ReadFull(s.conn, header)
+	assert.NoError(s.t, err)
+	payload := make([]byte, packetLength(header))
+	_, err = io.ReadFull(s.conn, payload)
+	assert.NoError(s.t, err)
+	return header[3], payload
+}
+
+func (s *authServer) write(seq byte, payload []byte) {
+	length := len(payload)
+	_, err := s.conn.Write(append([]byte{byte(length), byte(length >> 8), byte(length >> 16), seq}, payload...))
+	assert.NoError(s.t, err)
+}
+
+// startAuthServer runs the server which sends handshakePayload
+// and passes the connection to fn
+func startAuthServer(t *testing.T, fn func(s *authServer)) (*netConn, chan struct{}) {
+	return startHandshakeServer(t, handshakePayload, fn)
+}
+
+// startHandshakeServer runs the server which sends the handshake
+// and passes the connection to fn
+func startHandshakeServer(t *testing.T, handshake []byte, fn func(s *authServer)) (*netConn, chan struct{}) {
+	client, server := net.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer server.Close()
+		s := &authServer{t: t, conn: server}
+		s.write(0, handshake)
+		fn(s)
+	}()
+	return &netConn{Conn: client, capture: true}, done
+}
+
+// authData returns auth response of the handshake response
+// sent by the driver with the capability flags
+func authData(response []byte) []byte {
+	offset := sslRequestLen
+	offset += bytes.IndexByte(response[offset:], 0) + 1 // username
+	length := int(response[offset])
+	return response[offset+1 : offset+1+length]
+}
+
+func TestConnectHandshakeFastAuth(t *testing.T) {
+	conn, done := startAuthServer(t, func(s *authServer) {
+		seq, response := s.read()
+		assert.Equal(t, seq, byte(1))
+		assert.True(t, bytes.Contains(response, []byte("root\x00")))
+		assert.True(t, bytes.Contains(response, []byte("test\x00")))
+		// connection attributes are empty
+		assert.True(t, bytes.HasSuffix(response, []byte("caching_sha2_password\x00\x00")))
+		assert.Equal(t, authData(response), scrambleSHA256Password("secret", handshakeScramble))
+
+		s.write(2, []byte{authMoreData, cachingSHA2FastAuthSuccess})
+		s.write(3, okPayload)
+	})
+	defer conn.Close()
+
+	stream, err := connectHandshake(conn, capabilityFlags, charsetUTF8Code, nil, "root", "secret", "test", nil, false, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, stream.CapabilityFlags, capabilityFlags)
+	assert.Equal(t, conn.firstPacket(), handshakePayload)
+	<-done
+}
+
+func TestConnectHandshakeNegotiatesMariaDBCapabilities(t *testing.T) {
+	conn, done := startHandshakeServer(t, mariaDBHandshakePayload, func(s *authServer) {
+		_, response := s.read()
+		flags := binary.LittleEndian.Uint32(response)
+		assert.Equal(t, flags&mysqlproto.CLIENT_LONG_PASSWORD, uint32(0))
+		assert.Equal(t, response[28:32], []byte{0x08, 0x00, 0x00, 0x00})
+		s.write(2, okPayload)
+	})
+	defer conn.Close()
+
+	_, err := connectHandshake(conn, capabilityFlags, charsetUTF8Code, nil, "root", "secret", "test", nil, false, 0)
+	assert.NoError(t, err)
+	<-done
+
+	// MySQL reserves the bytes of extended capabilities
+	conn, done = startAuthServer(t, func(s *authServer) {
+		_, response := s.read()
+		assert.Equal(t, response[28:32], []byte{0x00, 0x00, 0x00, 0x00})
+		s.write(2, []byte{authMoreData, cachingSHA2FastAuthSuccess})
+		s.write(3, okPayload)
+	})
+	defer conn.Close()
+
+	_, err = connectHandshake(conn, capabilityFlags, charsetUTF8Code, nil, "root", "secret", "test", nil, false, 0)
+	assert.NoError(t, err)
+	<-done
+}
+
+func TestConnectHandshakeFullAuthWithPublicKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	public := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	conn, done := startAuthServer(t, func(s *authServer) {
+		s.read()
+		s.write(2, []byte{authMoreData, cachingSHA2PerformFullAuth})
+
+		seq, request := s.read()
+		assert.Equal(t, seq, byte(3))
+		assert.Equal(t, request, []byte{cachingSHA2RequestPublicKey})
+		s.write(4, append([]byte{authMoreData}, public...))
+
+		seq, encrypted := s.read()
+		assert.Equal(t, seq, byte(5))
+		plain, err := rsa.DecryptOAEP(sha1.New(), nil, key, encrypted, nil)
+		assert.NoError(t, err)
+		for i := range plain {
+			plain[i] ^= handshakeScramble[i%len(handshakeScramble)]
+		}
+		assert.Equal(t, plain, []byte("secret\x00"))
+		s.write(6, okPayload)
+	})
+	defer conn.Close()
+
+	_, err = connectHandshake(conn, capabilityFlags, charsetUTF8Code, nil, "root", "secret", "test", nil, false, 0)
+	assert.NoError(t, err)
+	<-done
+}
+
+func TestConnectHandshakeFullAuthOverLocalConnection(t *testing.T) {
+	conn, done := startAuthServer(t, func(s *authServer) {
+		s.read()
+		s.write(2, []byte{authMoreData, cachingSHA2PerformFullAuth})
+		_, password := s.read()
+		assert.Equal(t, password, []byte("secret\x00"))
+		s.write(4, okPayload)
+	})
+	defer conn.Close()
+
+	_, err := connectHandshake(conn, capabilityFlags, charsetUTF8Code, nil, "root", "secret", "", nil, true, 0)
+	assert.NoError(t, err)
+	<-done
+}
+
+func TestConnectHandshakeAuthSwitch(t *testing.T) {
+	scramble := []byte("abcdefghijklmnopqrst")
+	conn, done := startAuthServer(t, func(s *authServer) {
+		_, response := s.read()
+		// database isn't sent when it's empty
+		assert.False(t, bytes.Contains(response, []byte("test\x00")))
+
+		request := append([]byte{authSwitchRequest}, "mysql_native_password\x00"...)
+		request = append(append(request, scramble...), 0)
+		s.write(2, request)
+
+		seq, auth := s.read()
+		assert.Equal(t, seq, byte(3))
+		assert.Equal(t, auth, scrambleNativePassword("secret", scramble))
+		s.write(4, okPayload)
+	})
+	defer conn.Close()
+
+	stream, err := connectHandshake(conn, capabilityFlags, charsetUTF8Code, nil, "root", "secret", "", nil, false, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, stream.CapabilityFlags&mysqlproto.CLIENT_CONNECT_WITH_DB, uint32(0))
+	<-done
+}
+
+func TestConnectHandshakeReturnsAuthError(t *testing.T) {
+	errPayload := append([]byte{mysqlproto.ERR_PACKET, 0x15, 0x04, '#'}, "28000Access denied"...)
+	conn, done := startAuthServer(t, func(s *authServer) {
+		s.read()
+		s.write(2, errPayload)
+	})
+	defer conn.Close()
+
+	_, err := connectHandshake(conn, capabilityFlags, charsetUTF8Code, nil, "root", "wrong", "test", nil, false, 0)
+	_, ok := err.(mysqlproto.ERRPacket)
+	assert.True(t, ok)
+	<-done
+}
+
+func TestConnectHandshakeUnsupportedPlugin(t *testing.T) {
+	conn, done := startAuthServer(t, func(s *authServer) {
+		s.read()
+		s.write(2, append([]byte{authSwitchRequest}, "sha256_password\x00seed\x00"...))
+	})
+	defer conn.Close()
+
+	_, err := connectHandshake(conn, capabilityFlags, charsetUTF8Code, nil, "root", "secret", "test", nil, false, 0)
+	assert.Equal(t, err.Error(), `mysqldriver: unsupported auth plugin "sha256_password"`)
+	<-done
+}
+
+func TestScrambleNativePassword(t *testing.T) {
+	assert.Nil(t, scrambleNativePassword("", handshakeScramble))
+
+	// server checks SHA1(response XOR SHA1(scramble + stage2)) == stage2
+	// where stage2 = SHA1(SHA1(password)) is stored by the server
+	stage1 := sha1.Sum([]byte("secret"))
+	stage2 := sha1.Sum(stage1[:])
+	mask := sha1.Sum(append(append([]byte(nil), handshakeScramble...), stage2[:]...))
+	response := scrambleNativePassword("secret", handshakeScramble)
+	for i := range response {
+		response[i] ^= mask[i]
+	}
+	assert.Equal(t, sha1.Sum(response), stage2)
+}
+
+func TestScrambleSHA256Password(t *testing.T) {
+	assert.Nil(t, scrambleSHA256Password("", handshakeScramble))
+
+	// server checks SHA256(response XOR SHA256(stage2 + scramble)) == stage2
+	// where stage2 = SHA256(SHA256(password)) is cached by the server
+	stage1 := sha256.Sum256([]byte("secret"))
+	stage2 := sha256.Sum256(stage1[:])
+	mask := sha256.Sum256(append(append([]byte(nil), stage2[:]...), handshakeScramble...))
+	response := scrambleSHA256Password("secret", handshakeScramble)
+	for i := range response {
+		response[i] ^= mask[i]
+	}
+	assert.Equal(t, sha256.Sum256(response), stage2)
+}
+
+func TestParseAuthSwitchRequest(t *testing.T) {
+	plugin, scramble, err := parseAuthSwitchRequest(append([]byte{authSwitchRequest}, "mysql_native_password\x00abc\x00"...))
+	assert.NoError(t, err)
+	assert.Equal(t, plugin, "mysql_native_password")
+	assert.Equal(t, scramble, []byte("abc"))
+
+	_, _, err = parseAuthSwitchRequest([]byte{authSwitchRequest, 'a'})
+	assert.Equal(t, err, errMalformedPacket)
+}
+
+func TestParsePublicKeyMalformed(t *testing.T) {
+	_, err := parsePublicKey([]byte("not a key"))
+	assert.Equal(t, err, errMalformedPublicKey)
+}
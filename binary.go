@@ -0,0 +1,274 @@
+package mysqldriver
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/pubnative/mysqlproto-go"
+)
+
+// Binary protocol row (used for result sets of prepared statements)
+// starts with 0x00 header followed by the NULL bitmap and values
+// of all non-NULL columns. NULL values aren't present in the values
+// section at all, so the bitmap is the only way to detect them.
+//
+// NULL bitmap of the result set row has an offset of 2 bits,
+// so the first column is represented by the third bit of the first byte.
+// (see https://dev.mysql.com/doc/internals/en/binary-protocol-resultset-row.html)
+const binaryRowNullBitmapOffset = 2
+
+// binaryRowNullBitmapLen returns length of the NULL bitmap
+// of the binary protocol row with the given number of columns
+func binaryRowNullBitmapLen(columns int) int {
+	return (columns + 7 + binaryRowNullBitmapOffset) / 8
+}
+
+// binaryRowValuesOffset returns the offset of the first value
+// in the binary protocol row packet
+func binaryRowValuesOffset(columns int) uint64 {
+	return uint64(1 + binaryRowNullBitmapLen(columns))
+}
+
+// binaryRowNull reports whether the column with the given
+// index of the binary protocol row packet is NULL
+func binaryRowNull(packet []byte, column int) bool {
+	pos := column + binaryRowNullBitmapOffset
+	return packet[1+pos/8]&(1<<uint(pos%8)) != 0
+}
+
+var errMalformedBinaryValue = errors.New("mysqldriver: malformed binary protocol value")
+
+// binaryDecoder converts values of the binary protocol row
+// into the same textual representation as text protocol uses,
+// so all the accessors of Rows work for both of the protocols.
+// Numbers and dates are encoded into the internal buffer
+// which is reused for every new row, strings are returned
+// as they are without copying.
+type binaryDecoder struct {
+	buf []byte
+}
+
+// reset prepares decoder for the next row.
+// Values returned for the previous row can't be used after reset.
+func (d *binaryDecoder) reset() {
+	d.buf = d.buf[:0]
+}
+
+// decode reads value of the given type at the offset of the packet
+// and returns its textual representation and offset of the next value
+func (d *binaryDecoder) decode(packet []byte, offset uint64, fieldType byte, unsigned bool) ([]byte, uint64, error) {
+	data := packet[offset:]
+	start := len(d.buf)
+
+	switch fieldType {
+	case fieldTypeTiny:
+		if len(data) < 1 {
+			return nil, 0, errMalformedBinaryValue
+		}
+		if unsigned {
+			d.buf = strconv.AppendUint(d.buf, uint64(data[0]), 10)
+		} else {
+			d.buf = strconv.AppendInt(d.buf, int64(int8(data[0])), 10)
+		}
+		return d.buf[start:], offset + 1, nil
+
+	case fieldTypeShort, fieldTypeYear:
+		if len(data) < 2 {
+			return nil, 0, errMalformedBinaryValue
+		}
+		num := binary.LittleEndian.Uint16(data)
+		if unsigned || fieldType == fieldTypeYear {
+			d.buf = strconv.AppendUint(d.buf, uint64(num), 10)
+		} else {
+			d.buf = strconv.AppendInt(d.buf, int64(int16(num)), 10)
+		}
+		return d.buf[start:], offset + 2, nil
+
+	case fieldTypeLong, fieldTypeInt24:
+		if len(data) < 4 {
+			return nil, 0, errMalformedBinaryValue
+		}
+		num := binary.LittleEndian.Uint32(data)
+		if unsigned {
+			d.buf = strconv.AppendUint(d.buf, uint64(num), 10)
+		} else {
+			d.buf = strconv.AppendInt(d.buf, int64(int32(num)), 10)
+		}
+		return d.buf[start:], offset + 4, nil
+
+	case fieldTypeLongLong:
+		if len(data) < 8 {
+			return nil, 0, errMalformedBinaryValue
+		}
+		num := binary.LittleEndian.Uint64(data)
+		if unsigned {
+			d.buf = strconv.AppendUint(d.buf, num, 10)
+		} else {
+			d.buf = strconv.AppendInt(d.buf, int64(num), 10)
+		}
+		return d.buf[start:], offset + 8, nil
+
+	case fieldTypeFloat:
+		if len(data) < 4 {
+			return nil, 0, errMalformedBinaryValue
+		}
+		num := math.Float32frombits(binary.LittleEndian.Uint32(data))
+		d.buf = strconv.AppendFloat(d.buf, float64(num), 'g', -1, 32)
+		return d.buf[start:], offset + 4, nil
+
+	case fieldTypeDouble:
+		if len(data) < 8 {
+			return nil, 0, errMalformedBinaryValue
+		}
+		num := math.Float64frombits(binary.LittleEndian.Uint64(data))
+		d.buf = strconv.AppendFloat(d.buf, num, 'g', -1, 64)
+		return d.buf[start:], offset + 8, nil
+
+	case fieldTypeDate, fieldTypeNewDate, fieldTypeDateTime, fieldTypeTimestamp:
+		if len(data) < 1 || len(data) < 1+int(data[0]) {
+			return nil, 0, errMalformedBinaryValue
+		}
+		if err := d.appendDateTime(data[1:1+data[0]], fieldType); err != nil {
+			return nil, 0, err
+		}
+		return d.buf[start:], offset + 1 + uint64(data[0]), nil
+
+	case fieldTypeTime:
+		if len(data) < 1 || len(data) < 1+int(data[0]) {
+			return nil, 0, errMalformedBinaryValue
+		}
+		if err := d.appendTime(data[1 : 1+data[0]]); err != nil {
+			return nil, 0, err
+		}
+		return d.buf[start:], offset + 1 + uint64(data[0]), nil
+
+	default:
+		// DECIMAL, strings, BLOBs, ENUM, SET, BIT, JSON and GEOMETRY
+		// are sent as length encoded strings as in the text protocol
+		value, next, _ := mysqlproto.ReadRowValue(packet, offset)
+		if next > uint64(len(packet)) {
+			return nil, 0, errMalformedBinaryValue
+		}
+		return value, next, nil
+	}
+}
+
+// appendDateTime encodes DATE, DATETIME and TIMESTAMP value which
+// consists of year(2), month(1), day(1), hour(1), minute(1), second(1)
+// and microsecond(4). Trailing zero parts are omitted by the server.
+func (d *binaryDecoder) appendDateTime(data []byte, fieldType byte) error {
+	if len(data) != 0 && len(data) != 4 && len(data) != 7 && len(data) != 11 {
+		return errMalformedBinaryValue
+	}
+
+	var year, month, day, hour, min, sec, usec int
+	if len(data) >= 4 {
+		year = int(binary.LittleEndian.Uint16(data))
+		month, day = int(data[2]), int(data[3])
+	}
+	if len(data) >= 7 {
+		hour, min, sec = int(data[4]), int(data[5]), int(data[6])
+	}
+	if len(data) == 11 {
+		usec = int(binary.LittleEndian.Uint32(data[7:]))
+	}
+
+	d.appendDigits(year, 4)
+	d.buf = append(d.buf, '-')
+	d.appendDigits(month, 2)
+	d.buf = append(d.buf, '-')
+	d.appendDigits(day, 2)
+	if fieldType == fieldTypeDate || fieldType == fieldTypeNewDate {
+		return nil
+	}
+
+	d.buf = append(d.buf, ' ')
+	d.appendDigits(hour, 2)
+	d.buf = append(d.buf, ':')
+	d.appendDigits(min, 2)
+	d.buf = append(d.buf, ':')
+	d.appendDigits(sec, 2)
+	if usec > 0 {
+		d.buf = append(d.buf, '.')
+		d.appendDigits(usec, 6)
+	}
+	return nil
+}
+
+// appendTime encodes TIME value which consists of is_negative(1),
+// days(4), hour(1), minute(1), second(1) and microsecond(4).
+// Trailing zero parts are omitted by the server.
+func (d *binaryDecoder) appendTime(data []byte) error {
+	if len(data) != 0 && len(data) != 8 && len(data) != 12 {
+		return errMalformedBinaryValue
+	}
+
+	var negative bool
+	var hours, min, sec, usec int
+	if len(data) >= 8 {
+		negative = data[0] == 1
+		hours = int(binary.LittleEndian.Uint32(data[1:]))*24 + int(data[5])
+		min, sec = int(data[6]), int(data[7])
+	}
+	if len(data) == 12 {
+		usec = int(binary.LittleEndian.Uint32(data[8:]))
+	}
+
+	if negative {
+		d.buf = append(d.buf, '-')
+	}
+	d.appendDigits(hours, 2)
+	d.buf = append(d.buf, ':')
+	d.appendDigits(min, 2)
+	d.buf = append(d.buf, ':')
+	d.appendDigits(sec, 2)
+	if usec > 0 {
+		d.buf = append(d.buf, '.')
+		d.appendDigits(usec, 6)
+	}
+	return nil
+}
+
+// appendDigits appends number padded with zeros to the given width
+func (d *binaryDecoder) appendDigits(num, width int) {
+	digits := 1
+	for n := num / 10; n > 0; n /= 10 {
+		digits++
+	}
+	for ; digits < width; digits++ {
+		d.buf = append(d.buf, '0')
+	}
+	d.buf = strconv.AppendInt(d.buf, int64(num), 10)
+}
+
+// nullBinaryBytes reads the next value of the binary protocol row
+// and returns its textual representation (see func (Rows) NullBytes)
+func (r *Rows) nullBinaryBytes() ([]byte, bool) {
+	column := r.definitions[r.readColumns]
+
+	var value []byte
+	null := binaryRowNull(r.packet, r.readColumns)
+	if !null {
+		var err error
+		if r.offset >= uint64(len(r.packet)) {
+			err = errMalformedPacket
+		} else {
+			value, r.offset, err = r.binary.decode(r.packet, r.offset, column.Type, column.Unsigned())
+		}
+		if err != nil {
+			r.errParse = err
+			r.readColumns = len(r.definitions)
+			return nil, true
+		}
+	}
+
+	r.columns[column.Name] = columnValue{
+		data: value,
+		null: null,
+	}
+	r.readColumns += 1
+
+	return value, null
+}
@@ -0,0 +1,149 @@
+package mysqldriver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBinaryRowNullBitmapLen(t *testing.T) {
+	assert.Equal(t, binaryRowNullBitmapLen(1), 1)
+	assert.Equal(t, binaryRowNullBitmapLen(6), 1)
+	assert.Equal(t, binaryRowNullBitmapLen(7), 2)
+	assert.Equal(t, binaryRowNullBitmapLen(14), 2)
+	assert.Equal(t, binaryRowNullBitmapLen(15), 3)
+	assert.Equal(t, binaryRowValuesOffset(6), uint64(2))
+	assert.Equal(t, binaryRowValuesOffset(7), uint64(3))
+}
+
+func TestBinaryRowNullMixedNullability(t *testing.T) {
+	// 9 columns: 0, 2, 5, 6 and 8 are NULL
+	// bits (with 2 bits offset): 2, 4, 7, 8, 10
+	packet := []byte{0x00, 0x94, 0x05}
+	nulls := []bool{true, false, true, false, false, true, true, false, true}
+	for i, null := range nulls {
+		assert.Equal(t, binaryRowNull(packet, i), null, i)
+	}
+}
+
+func TestBinaryRowNullFirstBitsAreReserved(t *testing.T) {
+	// the first two bits are reserved and
+	// don't represent any column
+	packet := []byte{0x00, 0x03}
+	for i := 0; i < 6; i++ {
+		assert.False(t, binaryRowNull(packet, i))
+	}
+	packet = []byte{0x00, 0x04}
+	assert.True(t, binaryRowNull(packet, 0))
+	assert.False(t, binaryRowNull(packet, 1))
+}
+
+func TestBinaryDecoderIntegers(t *testing.T) {
+	cases := []struct {
+		fieldType byte
+		unsigned  bool
+		data      []byte
+		expected  string
+	}{
+		{fieldTypeTiny, false, []byte{0xfe}, "-2"},
+		{fieldTypeTiny, true, []byte{0xfe}, "254"},
+		{fieldTypeShort, false, []byte{0x18, 0xfc}, "-1000"},
+		{fieldTypeShort, true, []byte{0x18, 0xfc}, "64536"},
+		{fieldTypeYear, false, []byte{0xe1, 0x07}, "2017"},
+		{fieldTypeLong, false, []byte{0xff, 0xff, 0xff, 0xff}, "-1"},
+		{fieldTypeLong, true, []byte{0xff, 0xff, 0xff, 0xff}, "4294967295"},
+		{fieldTypeInt24, false, []byte{0x40, 0x42, 0x0f, 0x00}, "1000000"},
+		{fieldTypeLongLong, false, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, "-1"},
+		{fieldTypeLongLong, true, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, "18446744073709551615"},
+	}
+
+	for _, c := range cases {
+		d := &binaryDecoder{}
+		value, next, err := d.decode(c.data, 0, c.fieldType, c.unsigned)
+		assert.NoError(t, err)
+		assert.Equal(t, string(value), c.expected)
+		assert.Equal(t, next, uint64(len(c.data)))
+	}
+}
+
+func TestBinaryDecoderFloats(t *testing.T) {
+	d := &binaryDecoder{}
+	value, next, err := d.decode([]byte{0x00, 0x00, 0x90, 0x40}, 0, fieldTypeFloat, false)
+	assert.NoError(t, err)
+	assert.Equal(t, string(value), "4.5")
+	assert.Equal(t, next, uint64(4))
+
+	value, next, err = d.decode([]byte{0x9a, 0x99, 0x99, 0x99, 0x99, 0x99, 0x0d, 0x40}, 0, fieldTypeDouble, false)
+	assert.NoError(t, err)
+	assert.Equal(t, string(value), "3.7")
+	assert.Equal(t, next, uint64(8))
+}
+
+func TestBinaryDecoderDateTime(t *testing.T) {
+	cases := []struct {
+		fieldType byte
+		data      []byte
+		expected  string
+	}{
+		{fieldTypeDate, []byte{0x04, 0xe1, 0x07, 0x03, 0x04}, "2017-03-04"},
+		{fieldTypeDate, []byte{0x00}, "0000-00-00"},
+		{fieldTypeDateTime, []byte{0x00}, "0000-00-00 00:00:00"},
+		{fieldTypeDateTime, []byte{0x04, 0xe1, 0x07, 0x03, 0x04}, "2017-03-04 00:00:00"},
+		{fieldTypeDateTime, []byte{0x07, 0xe1, 0x07, 0x03, 0x04, 0x05, 0x06, 0x07}, "2017-03-04 05:06:07"},
+		{fieldTypeTimestamp, []byte{0x0b, 0xe1, 0x07, 0x03, 0x04, 0x05, 0x06, 0x07, 0x40, 0xe2, 0x01, 0x00}, "2017-03-04 05:06:07.123456"},
+		{fieldTypeDateTime, []byte{0x0b, 0xe1, 0x07, 0x03, 0x04, 0x05, 0x06, 0x07, 0x01, 0x00, 0x00, 0x00}, "2017-03-04 05:06:07.000001"},
+		{fieldTypeTime, []byte{0x00}, "00:00:00"},
+		{fieldTypeTime, []byte{0x08, 0x01, 0x01, 0x00, 0x00, 0x00, 0x02, 0x03, 0x04}, "-26:03:04"},
+		{fieldTypeTime, []byte{0x0c, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a, 0x0b, 0x0c, 0xa0, 0x86, 0x01, 0x00}, "10:11:12.100000"},
+	}
+
+	for _, c := range cases {
+		d := &binaryDecoder{}
+		value, next, err := d.decode(c.data, 0, c.fieldType, false)
+		assert.NoError(t, err)
+		assert.Equal(t, string(value), c.expected)
+		assert.Equal(t, next, uint64(len(c.data)))
+	}
+}
+
+func TestBinaryDecoderDateTimeIsParsedByTimeAccessor(t *testing.T) {
+	d := &binaryDecoder{}
+	data := []byte{0x0b, 0xe1, 0x07, 0x03, 0x04, 0x05, 0x06, 0x07, 0x40, 0xe2, 0x01, 0x00}
+	value, _, err := d.decode(data, 0, fieldTypeDateTime, false)
+	assert.NoError(t, err)
+	tm, err := parseDateTime(value, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, tm, time.Date(2017, 3, 4, 5, 6, 7, 123456000, time.UTC))
+}
+
+func TestBinaryDecoderSequentialValues(t *testing.T) {
+	// TINY(-2), DATE(2017-03-04), LONG(16)
+	packet := []byte{0xfe, 0x04, 0xe1, 0x07, 0x03, 0x04, 0x10, 0x00, 0x00, 0x00}
+	d := &binaryDecoder{}
+
+	tiny, offset, err := d.decode(packet, 0, fieldTypeTiny, false)
+	assert.NoError(t, err)
+	date, offset, err := d.decode(packet, offset, fieldTypeDate, false)
+	assert.NoError(t, err)
+	long, offset, err := d.decode(packet, offset, fieldTypeLong, false)
+	assert.NoError(t, err)
+
+	assert.Equal(t, string(tiny), "-2")
+	assert.Equal(t, string(date), "2017-03-04")
+	assert.Equal(t, string(long), "16")
+	assert.Equal(t, offset, uint64(len(packet)))
+
+	d.reset()
+	assert.Len(t, d.buf, 0)
+}
+
+func TestBinaryDecoderMalformed(t *testing.T) {
+	d := &binaryDecoder{}
+	_, _, err := d.decode([]byte{0x01, 0x02}, 0, fieldTypeLong, false)
+	assert.Equal(t, err, errMalformedBinaryValue)
+	_, _, err = d.decode([]byte{0x07, 0xe1, 0x07}, 0, fieldTypeDateTime, false)
+	assert.Equal(t, err, errMalformedBinaryValue)
+	_, _, err = d.decode([]byte{0x05, 0x00, 0x00, 0x00, 0x00, 0x00}, 0, fieldTypeTime, false)
+	assert.Equal(t, err, errMalformedBinaryValue)
+}
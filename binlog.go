@@ -0,0 +1,191 @@
+package mysqldriver
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/pubnative/mysqlproto-go"
+)
+
+const (
+	binlogDumpNonBlock   uint16 = 0x01 // BINLOG_DUMP_NON_BLOCK
+	binlogEventHeaderLen        = 19
+)
+
+// BinlogOptions configures replication stream started by StartBinlogDump
+type BinlogOptions struct {
+	// ServerID identifies the client as a replica. It must be unique
+	// among all replicas of the source.
+	ServerID uint32
+
+	// File and Position of the first event, e.g. as reported
+	// by SHOW MASTER STATUS. Empty file means the first binary log
+	// and zero position means the beginning of the file.
+	File     string
+	Position uint32
+
+	// Hostname, User, Password and Port are reported to the source
+	// by COM_REGISTER_SLAVE and are shown by SHOW REPLICAS
+	Hostname string
+	User     string
+	Password string
+	Port     uint16
+
+	// NonBlocking makes the stream end with io.EOF after the last
+	// event instead of waiting for new events
+	NonBlocking bool
+}
+
+// BinlogStream is a stream of raw binary log events
+// sent by the source after COM_BINLOG_DUMP
+type BinlogStream struct {
+	conn *Conn
+	done bool
+}
+
+// BinlogEventHeader is the common header of all binary log events
+// (see https://dev.mysql.com/doc/internals/en/binlog-event-header.html)
+type BinlogEventHeader struct {
+	Timestamp    uint32 // seconds since Unix epoch
+	Type         byte   // event type, e.g. 0x04 for ROTATE_EVENT
+	ServerID     uint32 // server which created the event
+	EventSize    uint32 // size of the event including the header
+	NextPosition uint32 // position of the next event in the binary log
+	Flags        uint16
+}
+
+// StartBinlogDump registers the connection as a replica by sending
+// COM_REGISTER_SLAVE and requests binary log events by COM_BINLOG_DUMP.
+// It requires REPLICATION SLAVE privilege.
+//
+// Source is told that the client understands event checksums,
+// so events end with the checksum when binlog_checksum is enabled.
+// Events aren't parsed, see ParseBinlogEventHeader to read their header.
+//
+// Connection is dedicated to the stream and can't be used for other
+// queries. It's closed by BinlogStream.Close.
+//
+//	stream, _ := conn.StartBinlogDump(mysqldriver.BinlogOptions{ServerID: 100, File: "binlog.000001", Position: 4})
+//	defer stream.Close()
+//	for {
+//		event, err := stream.Next()
+//		if err != nil {
+//			break
+//		}
+//		header, _ := mysqldriver.ParseBinlogEventHeader(event)
+//		fmt.Println(header.Type, header.NextPosition)
+//	}
+func (c *Conn) StartBinlogDump(opts BinlogOptions) (*BinlogStream, error) {
+	if _, err := c.Exec("SET @master_binlog_checksum = @@global.binlog_checksum"); err != nil {
+		return nil, err
+	}
+
+	if err := c.command(comRegisterSlave, registerSlavePayload(opts)); err != nil {
+		return nil, err
+	}
+
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+
+	if _, err := c.conn.Write(commandPacket(comBinlogDump, binlogDumpPayload(opts))); err != nil {
+		c.valid = false
+		c.release()
+		return nil, err
+	}
+
+	return &BinlogStream{conn: c}, nil
+}
+
+// Next returns the next binary log event. The returned slice is valid
+// until the next call of Next. It returns io.EOF when all events
+// are read by non-blocking stream and ERRPacket when source
+// can't send events, e.g. because binary log file doesn't exist.
+func (s *BinlogStream) Next() ([]byte, error) {
+	if s.done {
+		return nil, io.EOF
+	}
+
+	packet, err := s.conn.conn.NextPacket()
+	if err != nil {
+		s.finish()
+		return nil, err
+	}
+
+	payload := packet.Payload
+	switch {
+	case isEOFPacket(payload):
+		s.finish()
+		return nil, io.EOF
+	case len(payload) > 0 && payload[0] == mysqlproto.ERR_PACKET:
+		s.finish()
+		errPacket, err := mysqlproto.ParseERRPacket(payload, s.conn.conn.CapabilityFlags)
+		if err != nil {
+			return nil, err
+		}
+		return nil, errPacket
+	case len(payload) > 0 && payload[0] == mysqlproto.OK_PACKET:
+		return payload[1:], nil
+	default:
+		s.finish()
+		return nil, errMalformedPacket
+	}
+}
+
+// Close stops the stream and closes the connection
+func (s *BinlogStream) Close() error {
+	s.finish()
+	return s.conn.Close()
+}
+
+func (s *BinlogStream) finish() {
+	if !s.done {
+		s.done = true
+		s.conn.valid = false
+		s.conn.release()
+	}
+}
+
+// ParseBinlogEventHeader parses the header of the event returned
+// by BinlogStream.Next. Event body starts right after the header.
+func ParseBinlogEventHeader(event []byte) (BinlogEventHeader, error) {
+	if len(event) < binlogEventHeaderLen {
+		return BinlogEventHeader{}, errMalformedPacket
+	}
+
+	return BinlogEventHeader{
+		Timestamp:    binary.LittleEndian.Uint32(event[0:]),
+		Type:         event[4],
+		ServerID:     binary.LittleEndian.Uint32(event[5:]),
+		EventSize:    binary.LittleEndian.Uint32(event[9:]),
+		NextPosition: binary.LittleEndian.Uint32(event[13:]),
+		Flags:        binary.LittleEndian.Uint16(event[17:]),
+	}, nil
+}
+
+// registerSlavePayload creates payload of COM_REGISTER_SLAVE
+func registerSlavePayload(opts BinlogOptions) []byte {
+	payload := make([]byte, 4, 18+len(opts.Hostname)+len(opts.User)+len(opts.Password))
+	binary.LittleEndian.PutUint32(payload, opts.ServerID)
+	for _, str := range []string{opts.Hostname, opts.User, opts.Password} {
+		payload = append(payload, byte(len(str)))
+		payload = append(payload, str...)
+	}
+	// port(2), replication_rank(4), master_id(4)
+	payload = append(payload, byte(opts.Port), byte(opts.Port>>8))
+	return append(payload, 0, 0, 0, 0, 0, 0, 0, 0)
+}
+
+// binlogDumpPayload creates payload of COM_BINLOG_DUMP
+func binlogDumpPayload(opts BinlogOptions) []byte {
+	var flags uint16
+	if opts.NonBlocking {
+		flags |= binlogDumpNonBlock
+	}
+
+	payload := make([]byte, 10, 10+len(opts.File))
+	binary.LittleEndian.PutUint32(payload, opts.Position)
+	binary.LittleEndian.PutUint16(payload[4:], flags)
+	binary.LittleEndian.PutUint32(payload[6:], opts.ServerID)
+	return append(payload, opts.File...)
+}
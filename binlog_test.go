@@ -0,0 +1,74 @@
+package mysqldriver
+
+import (
+	"io"
+	"testing"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartBinlogDump(t *testing.T) {
+	okPacket := []byte{mysqlproto.OK_PACKET, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00}
+	rotate := []byte{
+		mysqlproto.OK_PACKET,
+		0x00, 0x00, 0x00, 0x00, // timestamp
+		0x04,                   // ROTATE_EVENT
+		0x01, 0x00, 0x00, 0x00, // server ID
+		0x1b, 0x00, 0x00, 0x00, // event size
+		0x00, 0x00, 0x00, 0x00, // next position
+		0x20, 0x00, // flags
+		0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // position
+	}
+	conn := newPacketConn(okPacket, okPacket, rotate, eofPacket)
+
+	stream, err := conn.StartBinlogDump(BinlogOptions{ServerID: 100, File: "binlog.000001", Position: 4, NonBlocking: true})
+	assert.NoError(t, err)
+	assert.Equal(t, conn.acquire(), ErrConnectionBusy)
+
+	event, err := stream.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, event, rotate[1:])
+	header, err := ParseBinlogEventHeader(event)
+	assert.NoError(t, err)
+	assert.Equal(t, header, BinlogEventHeader{
+		Type:      0x04,
+		ServerID:  1,
+		EventSize: 27,
+		Flags:     0x20,
+	})
+
+	_, err = stream.Next()
+	assert.Equal(t, err, io.EOF)
+	_, err = stream.Next()
+	assert.Equal(t, err, io.EOF)
+	assert.False(t, conn.valid)
+}
+
+func TestParseBinlogEventHeaderMalformed(t *testing.T) {
+	_, err := ParseBinlogEventHeader(make([]byte, 18))
+	assert.Equal(t, err, errMalformedPacket)
+}
+
+func TestRegisterSlavePayload(t *testing.T) {
+	payload := registerSlavePayload(BinlogOptions{ServerID: 100, Hostname: "cdc", User: "u", Port: 3306})
+	assert.Equal(t, payload, []byte{
+		0x64, 0x00, 0x00, 0x00,
+		0x03, 'c', 'd', 'c',
+		0x01, 'u',
+		0x00,
+		0xea, 0x0c,
+		0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+	})
+}
+
+func TestBinlogDumpPayload(t *testing.T) {
+	payload := binlogDumpPayload(BinlogOptions{ServerID: 100, File: "bin.1", Position: 4, NonBlocking: true})
+	assert.Equal(t, payload, []byte{
+		0x04, 0x00, 0x00, 0x00,
+		0x01, 0x00,
+		0x64, 0x00, 0x00, 0x00,
+		'b', 'i', 'n', '.', '1',
+	})
+}
@@ -0,0 +1,127 @@
+package mysqldriver
+
+import (
+	"errors"
+)
+
+// ErrResultTooLarge is returned by QueryBuffered when
+// the result set exceeds MaxBufferBytes of the connection
+var ErrResultTooLarge = errors.New("mysqldriver: result set exceeds MaxBufferBytes")
+
+// BufferedRows represents result set which is read into memory
+// completely. Connection can be used for other queries
+// right away, while rows are still being read.
+// BufferedRows provides the same methods as Rows does.
+type BufferedRows struct {
+	Rows
+}
+
+// QueryBuffered performs SELECT query the same way as Query does,
+// but reads all rows of the result set into memory before returning.
+//
+//	rows, _ := conn.QueryBuffered("SELECT name FROM dogs")
+//	conn.Exec("DELETE FROM dogs") // connection is available
+//	for rows.Next() {
+//		rows.String() // dog's name
+//	}
+//
+// When MaxBufferBytes of the connection is set and
+// the result set is bigger, the rest of the rows are discarded
+// and ErrResultTooLarge is returned.
+func (c *Conn) QueryBuffered(sql string) (*BufferedRows, error) {
+	rows, err := c.Query(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	// statement without result set is already released
+	if !rows.eof {
+		err := rows.bufferRows()
+		c.release()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rows.eof = false
+	rows.buffered = true
+	return &BufferedRows{Rows: *rows}, nil
+}
+
+// bufferRows reads the rest of the rows into memory without
+// releasing the connection. When the result set exceeds
+// MaxBufferBytes, the rest of the rows are drained
+// and ErrResultTooLarge is returned.
+func (r *Rows) bufferRows() error {
+	var size int64
+	var buffer [][]byte
+	tooLarge := false
+	for {
+		packet, err := r.readRow()
+		if err != nil {
+			return r.conn.readError(err)
+		}
+		if packet == nil {
+			break
+		}
+		if tooLarge {
+			continue
+		}
+
+		size += int64(len(packet))
+		if r.conn.MaxBufferBytes > 0 && size > r.conn.MaxBufferBytes {
+			tooLarge, buffer = true, nil
+			continue
+		}
+
+		// packet is reused by the stream for the next row
+		buffer = append(buffer, append([]byte(nil), packet...))
+	}
+
+	// only the first result set is buffered, e.g. of CALL statement
+	if err := r.skipResultSets(); err != nil {
+		return err
+	}
+
+	if tooLarge {
+		return ErrResultTooLarge
+	}
+	r.buffer = buffer
+	return nil
+}
+
+// Len returns the total number of rows of the result set
+// regardless of how many of them have been read
+func (r *BufferedRows) Len() int {
+	return len(r.buffer)
+}
+
+// Reset moves cursor before the first row, so rows
+// can be iterated again. Parse error of the previous
+// iteration is reset as well.
+//
+//	rows, _ := conn.QueryBuffered("SELECT name FROM dogs")
+//	fmt.Println(rows.Len(), "dogs")
+//	for rows.Next() {
+//	}
+//	rows.Reset()
+//	rows.Next() // the first row
+func (r *BufferedRows) Reset() {
+	r.position = 0
+	r.eof = false
+	r.errParse = nil
+	r.packet = nil
+	r.offset = 0
+	r.readColumns = 0
+}
+
+func (r *Rows) nextBuffered() bool {
+	if r.position >= len(r.buffer) {
+		r.eof = true
+		return false
+	}
+
+	r.setRow(r.buffer[r.position])
+	r.position++
+	return true
+}
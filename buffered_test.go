@@ -0,0 +1,85 @@
+package mysqldriver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnQueryBuffered(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec(`INSERT INTO people(firstname, age) VALUES ("Bob", 3), ("Alice", 5)`)
+		assert.NoError(t, err)
+
+		rows, err := conn.QueryBuffered("SELECT firstname, age FROM people ORDER BY id")
+		assert.NoError(t, err)
+
+		// connection is available right away
+		_, err = conn.Exec("DELETE FROM people")
+		assert.NoError(t, err)
+
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.String(), "Bob")
+		assert.Equal(t, rows.Int(), 3)
+		assert.True(t, rows.Next())
+		row := rows.Row()
+		assert.Equal(t, row.String("firstname"), "Alice")
+		assert.Equal(t, row.Int("age"), 5)
+		assert.False(t, rows.Next())
+		assert.False(t, rows.Next())
+		assert.NoError(t, rows.LastError())
+	})
+}
+
+func TestConnQueryBufferedExceedsMaxBufferBytes(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec(`INSERT INTO people(firstname) VALUES (REPEAT("a", 100)), (REPEAT("b", 100))`)
+		assert.NoError(t, err)
+
+		conn.MaxBufferBytes = 150
+		_, err = conn.QueryBuffered("SELECT firstname FROM people")
+		assert.Equal(t, err, ErrResultTooLarge)
+		assert.True(t, conn.valid)
+
+		conn.MaxBufferBytes = 1000
+		rows, err := conn.QueryBuffered("SELECT firstname FROM people")
+		assert.NoError(t, err)
+		var count int
+		for rows.Next() {
+			count++
+		}
+		assert.Equal(t, count, 2)
+	})
+}
+
+func TestBufferedRowsLenAndReset(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01},
+		columnDefinition("age"),
+		eofPacket,
+		[]byte{0x01, '3'},
+		[]byte{0x01, 'x'},
+		eofPacket,
+	)
+
+	rows, err := conn.QueryBuffered("SELECT age FROM people")
+	assert.NoError(t, err)
+	assert.Equal(t, rows.Len(), 2)
+
+	var ages []int
+	for rows.Next() {
+		ages = append(ages, rows.Int())
+	}
+	assert.Equal(t, ages, []int{3, 0})
+	assert.NotNil(t, rows.LastError())
+	assert.Equal(t, rows.Len(), 2)
+
+	rows.Reset()
+	assert.NoError(t, rows.LastError())
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.String(), "3")
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.String(), "x")
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.LastError())
+}
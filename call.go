@@ -0,0 +1,57 @@
+package mysqldriver
+
+import (
+	"fmt"
+)
+
+// Call executes the stored procedure with the arguments converted
+// into SQL literals the same way as QueryArgs does. Returned Rows
+// contain result sets of SELECT statements of the procedure
+// starting from the first one (see func (Rows) NextResultSet).
+// Procedure which doesn't return rows has no result sets.
+//
+// The final result of the procedure is available after all
+// result sets are read (see func (Conn) AffectedRows).
+//
+//	rows, err := conn.Call("report.daily_sales", "2020-01-02", 10)
+//	if err != nil {
+//		// handle error
+//	}
+//	for ok := true; ok; ok = rows.NextResultSet() {
+//		for rows.Next() {
+//			// read values of the row
+//		}
+//	}
+//	if err := rows.LastError(); err == nil {
+//		affected := conn.AffectedRows()
+//	}
+//
+// OUT and INOUT parameters are set into session variables
+// which can be passed by QueryArgs and selected afterwards:
+//
+//	rows, err := conn.QueryArgs("CALL count_dogs(?, @total)", "Bob")
+//	// read result sets
+//	rows, err = conn.Query("SELECT @total")
+func (c *Conn) Call(name string, args ...interface{}) (*Rows, error) {
+	sql, err := c.callStatement(name, args)
+	if err != nil {
+		return nil, err
+	}
+	return c.Query(sql)
+}
+
+// callStatement creates CALL statement of the procedure
+func (c *Conn) callStatement(name string, args []interface{}) (string, error) {
+	buf := append([]byte("CALL "), quoteIdentifier(name)...)
+	buf = append(buf, '(')
+	for i, arg := range args {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+		var err error
+		if buf, err = c.appendLiteral(buf, arg); err != nil {
+			return "", fmt.Errorf("mysqldriver: argument %d: %v", i, err)
+		}
+	}
+	return string(append(buf, ')')), nil
+}
@@ -0,0 +1,66 @@
+package mysqldriver
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallStatement(t *testing.T) {
+	conn := &Conn{}
+	sql, err := conn.callStatement("report.daily_sales", []interface{}{"Bob's", 10, nil, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)})
+	assert.NoError(t, err)
+	assert.Equal(t, sql, "CALL `report`.`daily_sales`('Bob\\'s', 10, NULL, '2020-01-02 00:00:00')")
+
+	sql, err = conn.callStatement("cleanup", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, "CALL `cleanup`()")
+
+	_, err = conn.callStatement("cleanup", []interface{}{1, struct{}{}})
+	assert.Equal(t, err.Error(), "mysqldriver: argument 1: unsupported type struct {}")
+}
+
+func TestConnCall(t *testing.T) {
+	conn, s := newSessionConn("8.0.22",
+		[]byte{0x01}, columnDefinition("total"), eofPacket,
+		[]byte{0x01, '3'},
+		eofMoreResultsPacket,
+		[]byte{mysqlproto.OK_PACKET, 0x01, 0x00, 0x02, 0x00, 0x00, 0x00},
+	)
+
+	rows, err := conn.Call("count_dogs", "Bob")
+	assert.NoError(t, err)
+	assert.True(t, bytes.Contains(s.written, []byte("CALL `count_dogs`('Bob')")))
+
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Int(), 3)
+	assert.False(t, rows.Next())
+	assert.False(t, rows.NextResultSet())
+	assert.NoError(t, rows.LastError())
+	assert.NoError(t, conn.acquire())
+}
+
+func TestConnCallWithDB(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec("DROP PROCEDURE IF EXISTS add_person")
+		assert.NoError(t, err)
+		// affected rows of the last statement are returned by CALL
+		_, err = conn.Exec("CREATE PROCEDURE add_person(name VARCHAR(255)) BEGIN " +
+			"SELECT COUNT(*) AS total FROM people; INSERT INTO people(firstname) VALUES (name); END")
+		assert.NoError(t, err)
+		defer conn.Exec("DROP PROCEDURE add_person")
+
+		rows, err := conn.Call("add_person", "Bob")
+		assert.NoError(t, err)
+		for ok := true; ok; ok = rows.NextResultSet() {
+			for rows.Next() {
+				assert.Equal(t, rows.Int(), 0)
+			}
+		}
+		assert.NoError(t, rows.LastError())
+		assert.Equal(t, conn.AffectedRows(), uint64(1))
+	})
+}
@@ -0,0 +1,97 @@
+package mysqldriver
+
+import "github.com/pubnative/mysqlproto-go"
+
+// ChangeUser sends COM_CHANGE_USER command which authenticates
+// another user on the same connection and selects the database
+// without reconnecting, e.g. to reuse the connection for another
+// tenant. Empty database means no database is selected.
+// Session state is reset as after establishing of a new connection:
+// user variables, temporary tables, prepared statements and session
+// variables are cleared and the transaction in progress is rolled back.
+// Connection becomes invalid when the user can't be authenticated.
+//
+// KILL QUERY sent when Options.KillOnCancel is set still uses
+// the credentials passed to NewConnOptions.
+//
+// Connection with the changed user belongs to another user than the
+// pool's one, so DB.PutConn closes it instead of putting it into
+// the pool and ReconnectingConn doesn't re-establish it.
+func (c *Conn) ChangeUser(username, password, database string) error {
+	c.userChanged = true
+	if err := c.changeUser(username, password, database); err != nil {
+		return err
+	}
+
+	c.database = database
+	c.currentUser = ""
+	c.schemas = nil
+	return c.resetSession()
+}
+
+// Database returns the database selected by NewConn or ChangeUser.
+// It doesn't follow USE statements performed by the connection.
+func (c *Conn) Database() string {
+	return c.database
+}
+
+// changeUser performs the exchange of COM_CHANGE_USER command.
+// Server replies the same way as it replies to the handshake
+// response, usually by auth switch request with a new scramble.
+func (c *Conn) changeUser(username, password, database string) error {
+	if err := c.acquire(); err != nil {
+		return err
+	}
+	defer c.release()
+
+	// auth switch request follows when the server
+	// expects another plugin for the user
+	plugin := c.handshake.AuthPlugin
+	if plugin != authCachingSHA2Password {
+		plugin = authNativePassword
+	}
+	auth, err := authResponse(plugin, password, c.handshake.Scramble)
+	if err != nil {
+		return err
+	}
+
+	_, encrypted := c.TLSConnectionState()
+	h := &handshaker{
+		stream:   c.conn.Stream,
+		flags:    c.conn.CapabilityFlags,
+		password: password,
+		secure:   encrypted || c.localConnection(),
+	}
+	payload := changeUserPayload(h.flags, username, auth, database, plugin, handshakeCollation(c.Charset()), c.connectAttrs)
+	if err := h.write(payload); err != nil {
+		c.valid = false
+		return c.timeoutError(err)
+	}
+	if err := h.authenticate(plugin, c.handshake.Scramble); err != nil {
+		c.valid = false
+		return c.timeoutError(err)
+	}
+	return nil
+}
+
+// localConnection reports whether connection is established over unix socket
+func (c *Conn) localConnection() bool {
+	return c.netConn != nil && c.netConn.RemoteAddr() != nil && c.netConn.RemoteAddr().Network() == "unix"
+}
+
+// changeUserPayload returns payload of COM_CHANGE_USER command
+// (see https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_com_change_user.html)
+func changeUserPayload(flags uint32, username string, auth []byte, database, plugin string, collation byte, attrs []byte) []byte {
+	payload := append([]byte{comChangeUser}, username...)
+	payload = append(payload, 0, byte(len(auth)))
+	payload = append(payload, auth...)
+	payload = append(append(payload, database...), 0)
+	payload = append(payload, collation, 0)
+	if flags&mysqlproto.CLIENT_PLUGIN_AUTH != 0 {
+		payload = append(append(payload, plugin...), 0)
+	}
+	if flags&mysqlproto.CLIENT_CONNECT_ATTRS != 0 {
+		payload = appendConnectAttrs(payload, attrs)
+	}
+	return payload
+}
@@ -0,0 +1,118 @@
+package mysqldriver
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// startChangeUserServer runs the server which passes the connection
+// to fn after the initial handshake of handshakePayload
+func startChangeUserServer(t *testing.T, fn func(s *authServer)) (*Conn, chan struct{}) {
+	client, server := net.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer server.Close()
+		fn(&authServer{t: t, conn: server})
+	}()
+
+	handshake, err := parseHandshake(handshakePayload)
+	assert.NoError(t, err)
+	conn := &netConn{Conn: client}
+	return &Conn{
+		conn:      mysqlproto.Conn{Stream: mysqlproto.NewStream(conn, 0), CapabilityFlags: capabilityFlags},
+		netConn:   conn,
+		valid:     true,
+		handshake: handshake,
+		database:  "test",
+	}, done
+}
+
+func TestConnChangeUser(t *testing.T) {
+	scramble := []byte("abcdefghijklmnopqrst")
+	conn, done := startChangeUserServer(t, func(s *authServer) {
+		seq, request := s.read()
+		assert.Equal(t, seq, byte(0))
+		assert.Equal(t, request[0], comChangeUser)
+		assert.True(t, bytes.HasPrefix(request[1:], []byte("app\x00")))
+		assert.True(t, bytes.Contains(request, []byte("tenant\x00")))
+		assert.True(t, bytes.HasSuffix(request, []byte("caching_sha2_password\x00\x00")))
+		auth := scrambleSHA256Password("secret", handshakeScramble)
+		assert.Equal(t, request[5], byte(len(auth)))
+		assert.Equal(t, request[6:6+len(auth)], auth)
+
+		// user is authenticated by another plugin
+		switchRequest := append([]byte{authSwitchRequest}, "mysql_native_password\x00"...)
+		s.write(1, append(append(switchRequest, scramble...), 0))
+		seq, response := s.read()
+		assert.Equal(t, seq, byte(2))
+		assert.Equal(t, response, scrambleNativePassword("secret", scramble))
+		s.write(3, okPayload)
+	})
+	defer conn.Close()
+	conn.currentUser = "root@%"
+	tx := &Tx{conn: conn}
+	conn.tx = tx
+
+	assert.NoError(t, conn.ChangeUser("app", "secret", "tenant"))
+	<-done
+	assert.True(t, conn.valid)
+	assert.Equal(t, conn.Database(), "tenant")
+	assert.Equal(t, conn.currentUser, "")
+	assert.Nil(t, conn.tx)
+	assert.True(t, conn.userChanged)
+	assert.True(t, tx.done)
+}
+
+func TestConnChangeUserAccessDenied(t *testing.T) {
+	errPayload := append([]byte{mysqlproto.ERR_PACKET, 0x15, 0x04, '#'}, "28000Access denied"...)
+	conn, done := startChangeUserServer(t, func(s *authServer) {
+		s.read()
+		s.write(1, errPayload)
+	})
+	defer conn.Close()
+
+	err := conn.ChangeUser("app", "wrong", "tenant")
+	<-done
+	_, ok := err.(mysqlproto.ERRPacket)
+	assert.True(t, ok)
+	assert.False(t, conn.valid)
+	assert.Equal(t, conn.Database(), "test")
+}
+
+func TestChangeUserPayload(t *testing.T) {
+	payload := changeUserPayload(mysqlproto.CLIENT_PLUGIN_AUTH, "app", []byte{0xaa, 0xbb}, "tenant", authNativePassword, charsetUTF8Code, nil)
+	expected := append([]byte{comChangeUser}, "app\x00"...)
+	expected = append(expected, 0x02, 0xaa, 0xbb)
+	expected = append(expected, "tenant\x00"...)
+	expected = append(expected, charsetUTF8Code, 0x00)
+	expected = append(expected, "mysql_native_password\x00"...)
+	assert.Equal(t, payload, expected)
+
+	payload = changeUserPayload(0, "app", nil, "", authNativePassword, 8, nil)
+	assert.Equal(t, payload, []byte{comChangeUser, 'a', 'p', 'p', 0x00, 0x00, 0x00, 8, 0x00})
+}
+
+func TestConnChangeUserSelectsDatabase(t *testing.T) {
+	conn, err := NewConn("root", "", "tcp", "127.0.0.1:3306", "", time.Duration(0))
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Exec("SET @mysqldriver_change_user = 1")
+	assert.NoError(t, err)
+	assert.NoError(t, conn.ChangeUser("root", "", "test"))
+	assert.Equal(t, conn.Database(), "test")
+
+	rows, err := conn.Query("SELECT DATABASE(), @mysqldriver_change_user")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.String(), "test")
+	_, null := rows.NullInt()
+	assert.True(t, null)
+	assert.False(t, rows.Next())
+}
@@ -0,0 +1,110 @@
+package charset
+
+import (
+	"fmt"
+
+	"github.com/pubnative/mysqldriver-go"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// encodings by collation ID, nil encoding means that
+// values of the collation don't need conversion
+// (see SELECT ID, CHARACTER_SET_NAME FROM information_schema.COLLATIONS)
+var encodings = map[uint16]encoding.Encoding{}
+
+func init() {
+	add := func(enc encoding.Encoding, ids ...uint16) {
+		for _, id := range ids {
+			encodings[id] = enc
+		}
+	}
+	addRange := func(enc encoding.Encoding, from, to uint16) {
+		for id := from; id <= to; id++ {
+			encodings[id] = enc
+		}
+	}
+
+	utf16 := unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM)
+	utf16le := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)
+
+	add(nil, 11, 65, 63)      // ascii, binary
+	add(nil, 33, 76, 83, 223) // utf8mb3
+	addRange(nil, 192, 215)   // utf8mb3
+	add(nil, 45, 46)          // utf8mb4
+	addRange(nil, 224, 247)   // utf8mb4
+	addRange(nil, 255, 323)   // utf8mb4
+
+	add(charmap.Windows1252, 5, 8, 15, 31, 47, 48, 49, 94) // latin1 is cp1252 in MySQL
+	add(charmap.ISO8859_2, 2, 9, 21, 27, 77)               // latin2
+	add(charmap.ISO8859_7, 25, 70)                         // greek
+	add(charmap.ISO8859_8, 16, 71)                         // hebrew
+	add(charmap.ISO8859_9, 30, 78)                         // latin5
+	add(charmap.ISO8859_13, 20, 41, 42, 79)                // latin7
+	add(charmap.KOI8R, 7, 74)
+	add(charmap.KOI8U, 22, 75)
+	add(charmap.Windows1250, 26, 34, 44, 66, 99)
+	add(charmap.Windows1251, 14, 23, 50, 51, 52)
+	add(charmap.Windows1256, 57, 67)
+	add(charmap.Windows1257, 29, 58, 59)
+	add(charmap.CodePage866, 36, 68)
+	add(charmap.Macintosh, 39, 53) // macroman
+
+	add(japanese.ShiftJIS, 13, 88, 95, 96) // sjis, cp932
+	add(japanese.EUCJP, 12, 91, 97, 98)    // ujis, eucjpms
+	add(korean.EUCKR, 19, 85)
+	add(simplifiedchinese.GBK, 24, 86, 28, 87) // gb2312, gbk
+	add(simplifiedchinese.GB18030, 248, 249, 250)
+	add(traditionalchinese.Big5, 1, 84)
+
+	add(utf16, 35, 90, 159) // ucs2
+	addRange(utf16, 128, 151)
+	add(utf16, 54, 55, 327) // utf16
+	addRange(utf16, 101, 124)
+	add(utf16le, 56, 62)
+}
+
+// Encoding returns encoding of the collation. It returns nil
+// for UTF-8, ASCII and binary collations which don't need conversion.
+func Encoding(collation uint16) (encoding.Encoding, error) {
+	enc, ok := encodings[collation]
+	if !ok {
+		return nil, fmt.Errorf("charset: unsupported collation %d", collation)
+	}
+	return enc, nil
+}
+
+// Decode converts value of the column into UTF-8
+// using character set of the column collation
+func Decode(column mysqldriver.ColumnInfo, value []byte) (string, error) {
+	enc, err := Encoding(column.CharacterSet)
+	if err != nil {
+		return "", err
+	}
+	if enc == nil {
+		return string(value), nil
+	}
+
+	decoded, err := enc.NewDecoder().Bytes(value)
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
+
+// DecodedString reads the next value of the row the same way
+// as Rows.String does and converts it into UTF-8
+// (see func Decode). NULL value is represented as an empty string.
+func DecodedString(rows *mysqldriver.Rows) (string, error) {
+	column, ok := rows.NextColumn()
+	value, null := rows.NullBytes()
+	if !ok || null {
+		return "", nil
+	}
+	return Decode(column, value)
+}
@@ -0,0 +1,66 @@
+package charset
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pubnative/mysqldriver-go"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestEncoding(t *testing.T) {
+	enc, err := Encoding(8) // latin1_swedish_ci
+	assert.NoError(t, err)
+	assert.Equal(t, enc, charmap.Windows1252)
+
+	enc, err = Encoding(255) // utf8mb4_0900_ai_ci
+	assert.NoError(t, err)
+	assert.Nil(t, enc)
+
+	enc, err = Encoding(63) // binary
+	assert.NoError(t, err)
+	assert.Nil(t, enc)
+
+	_, err = Encoding(1000)
+	assert.EqualError(t, err, "charset: unsupported collation 1000")
+}
+
+func TestDecode(t *testing.T) {
+	str, err := Decode(mysqldriver.ColumnInfo{CharacterSet: 8}, []byte("caf\xe9"))
+	assert.NoError(t, err)
+	assert.Equal(t, str, "café")
+
+	str, err = Decode(mysqldriver.ColumnInfo{CharacterSet: 51}, []byte("\xcf\xf0\xe8\xe2\xe5\xf2")) // cp1251_general_ci
+	assert.NoError(t, err)
+	assert.Equal(t, str, "Привет")
+
+	str, err = Decode(mysqldriver.ColumnInfo{CharacterSet: 45}, []byte("café"))
+	assert.NoError(t, err)
+	assert.Equal(t, str, "café")
+}
+
+func TestDecodedString(t *testing.T) {
+	conn, err := mysqldriver.NewConn("root", "", "tcp", "127.0.0.1:3306", "test", time.Duration(0))
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	// server sends values in the character set of the column
+	_, err = conn.Exec("SET character_set_results = NULL")
+	assert.NoError(t, err)
+
+	rows, err := conn.Query("SELECT CONVERT('café' USING latin1), CONVERT('Привет' USING cp1251), NULL")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	str, err := DecodedString(rows)
+	assert.NoError(t, err)
+	assert.Equal(t, str, "café")
+	str, err = DecodedString(rows)
+	assert.NoError(t, err)
+	assert.Equal(t, str, "Привет")
+	str, err = DecodedString(rows)
+	assert.NoError(t, err)
+	assert.Equal(t, str, "")
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.LastError())
+}
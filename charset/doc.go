@@ -0,0 +1,21 @@
+/*
+Package charset converts values of the columns which use
+legacy character sets like latin1 or cp1251 into UTF-8.
+It uses golang.org/x/text/encoding, so mysqldriver package
+itself doesn't depend on it.
+
+Character set of the value is detected by the collation
+of the column sent by the server:
+
+	rows, _ := conn.Query("SELECT name FROM legacy_dogs")
+	for rows.Next() {
+		name, err := charset.DecodedString(rows)
+	}
+
+Server converts values into character_set_results of the session
+(utf8 after NewConn) and reports its collation in the column definition,
+so such values are returned as they are. To read values in the character
+set of the column, e.g. when it contains data of mixed character sets,
+the conversion must be disabled by "SET character_set_results = NULL".
+*/
+package charset
@@ -0,0 +1,95 @@
+package mysqldriver
+
+import "github.com/pubnative/mysqlproto-go"
+
+// defaultCharset is the character set of the session
+// when Options.Charset isn't set
+const defaultCharset = "utf8"
+
+// charsetCollations are IDs of the default collations of the character
+// sets supported by Options.Charset, which are sent in the handshake
+// response, so the session uses the character set from the beginning,
+// e.g. in the error messages of authentication and after Reset.
+// (see SELECT ID, CHARACTER_SET_NAME FROM information_schema.COLLATIONS WHERE IS_DEFAULT = 'Yes')
+//
+// Only ASCII-safe character sets are supported: bytes of multibyte
+// characters are above 0x7f, so a backslash inserted by escapeString
+// can't become the second byte of a character. Character sets like
+// big5, sjis, cp932, gbk and gb18030 allow it, so "\xbf'" escaped as
+// "\xbf\\'" is read by the server as a character followed by an
+// unescaped quote (see CVE-2006-2753).
+var charsetCollations = map[string]byte{
+	"dec8":     3,
+	"cp850":    4,
+	"hp8":      6,
+	"koi8r":    7,
+	"latin1":   8,
+	"latin2":   9,
+	"ascii":    11,
+	"ujis":     12,
+	"hebrew":   16,
+	"tis620":   18,
+	"euckr":    19,
+	"koi8u":    22,
+	"gb2312":   24,
+	"greek":    25,
+	"cp1250":   26,
+	"latin5":   30,
+	"armscii8": 32,
+	"cp866":    36,
+	"keybcs2":  37,
+	"macce":    38,
+	"macroman": 39,
+	"cp852":    40,
+	"latin7":   41,
+	"cp1251":   51,
+	"cp1256":   57,
+	"cp1257":   59,
+	"geostd8":  92,
+	"eucjpms":  97,
+	"utf8":     charsetUTF8Code,
+	"utf8mb3":  charsetUTF8Code,
+	"utf8mb4":  45, // utf8mb4_general_ci is supported by MySQL 5.5 and newer
+	"binary":   63,
+}
+
+// handshakeCollation returns ID of the collation sent in the handshake
+// response. Unknown character sets are sent as utf8.
+func handshakeCollation(charset string) byte {
+	if id, ok := charsetCollations[charset]; ok {
+		return id
+	}
+	return charsetUTF8Code
+}
+
+// supportedCharset reports whether charset can be used by Options.Charset
+func supportedCharset(charset string) bool {
+	_, ok := charsetCollations[charset]
+	return ok
+}
+
+// Charset returns the character set of the session set by
+// Options.Charset when connection is established, e.g. "utf8mb4".
+// Server sends text values in this character set unless
+// character_set_results session variable is changed.
+func (c *Conn) Charset() string {
+	if c.charset == "" {
+		return defaultCharset
+	}
+	return c.charset
+}
+
+// setCharset sends "SET NAMES" command with the character set
+func setCharset(conn mysqlproto.Conn, charset string) error {
+	data := mysqlproto.ComQueryRequest([]byte("SET NAMES " + charset))
+	if _, err := conn.Write(data); err != nil {
+		return err
+	}
+
+	packet, err := conn.NextPacket()
+	if err != nil {
+		return err
+	}
+
+	return handleOK(packet.Payload, conn.CapabilityFlags)
+}
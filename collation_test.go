@@ -0,0 +1,107 @@
+package mysqldriver
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandshakeCollation(t *testing.T) {
+	assert.Equal(t, handshakeCollation("utf8"), byte(charsetUTF8Code))
+	assert.Equal(t, handshakeCollation("utf8mb4"), byte(45))
+	assert.Equal(t, handshakeCollation("latin1"), byte(8))
+}
+
+func TestSupportedCharset(t *testing.T) {
+	assert.True(t, supportedCharset("utf8mb4"))
+	assert.True(t, supportedCharset("latin1"))
+	assert.True(t, supportedCharset("ujis"))
+	assert.False(t, supportedCharset(""))
+	assert.False(t, supportedCharset("utf8; DROP TABLE dogs"))
+	// backslash can be the second byte of the character
+	for _, charset := range []string{"big5", "sjis", "cp932", "gbk", "gb18030", "swe7"} {
+		assert.False(t, supportedCharset(charset))
+	}
+}
+
+func TestNewConnOptionsRefusesUnsafeCharset(t *testing.T) {
+	for _, charset := range []string{"gbk", "GBK", "sjis", "big5", "cp932", "gb18030"} {
+		_, err := NewConnOptions(context.Background(), "root", "", "tcp", "127.0.0.1:1", "test", time.Duration(0),
+			Options{Charset: charset})
+		assert.EqualError(t, err, `mysqldriver: unsupported charset "`+charset+`"`)
+	}
+}
+
+func TestEscapeStringOfMultibyteLeadByte(t *testing.T) {
+	// in gbk "\xbf\x5c" is a character, so the quote would be unescaped,
+	// but all supported character sets read the backslash as it is
+	payload := "\xbf\x27 OR 1=1 -- "
+	assert.Equal(t, escapeString(payload, false), "\xbf\x5c\x27 OR 1=1 -- ")
+	assert.Equal(t, escapeString(payload, true), "\xbf\x27\x27 OR 1=1 -- ")
+}
+
+func TestConnCharset(t *testing.T) {
+	assert.Equal(t, (&Conn{}).Charset(), "utf8")
+	assert.Equal(t, (&Conn{charset: "latin1"}).Charset(), "latin1")
+}
+
+func TestConnectHandshakeSendsCollation(t *testing.T) {
+	conn, done := startAuthServer(t, func(s *authServer) {
+		_, response := s.read()
+		assert.Equal(t, response[8], byte(45))
+		s.write(2, []byte{authMoreData, cachingSHA2FastAuthSuccess})
+		s.write(3, okPayload)
+	})
+	defer conn.Close()
+
+	_, err := connectHandshake(conn, capabilityFlags, 45, nil, "root", "secret", "test", nil, false, 0)
+	assert.NoError(t, err)
+	<-done
+}
+
+func TestNewConnOptionsInvalidCharset(t *testing.T) {
+	_, err := NewConnOptions(context.Background(), "root", "", "tcp", "127.0.0.1:1", "test", time.Duration(0),
+		Options{Charset: "utf8 COLLATE utf8_bin"})
+	assert.EqualError(t, err, `mysqldriver: unsupported charset "utf8 COLLATE utf8_bin"`)
+}
+
+func TestConnResetKeepsCharset(t *testing.T) {
+	// server restores the character set of the handshake response
+	conn, s := newSessionConn("8.0.22", okPayload)
+	conn.charset = "latin1"
+	assert.NoError(t, conn.Reset())
+	assert.False(t, bytes.Contains(s.written, []byte("SET NAMES")))
+}
+
+func TestQueryArgsOfMultibyteLeadByte(t *testing.T) {
+	conn, err := NewConnOptions(context.Background(), "root", "", "tcp", "127.0.0.1:3306", "test", time.Duration(0),
+		Options{Charset: "latin1"})
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	payload := "\xbf' OR 1=1 -- "
+	rows, err := conn.QueryArgs("SELECT ?", payload)
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.String(), payload)
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.LastError())
+}
+
+func TestNewConnOptionsCharset(t *testing.T) {
+	conn, err := NewConnOptions(context.Background(), "root", "", "tcp", "127.0.0.1:3306", "test", time.Duration(0),
+		Options{Charset: "latin1"})
+	assert.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, conn.Charset(), "latin1")
+
+	rows, err := conn.Query("SELECT @@character_set_client, @@character_set_results")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.String(), "latin1")
+	assert.Equal(t, rows.String(), "latin1")
+	assert.False(t, rows.Next())
+}
@@ -0,0 +1,195 @@
+package mysqldriver
+
+// ColumnInfo describes a column of the result set
+// or a parameter of the prepared statement
+// (see https://dev.mysql.com/doc/internals/en/com-query-response.html#column-definition)
+type ColumnInfo struct {
+	Schema       string // database name
+	Table        string // table alias
+	OrgTable     string // original table name
+	Name         string // column alias
+	OrgName      string // original column name
+	CharacterSet uint16 // collation ID
+	MaxLength    uint32 // maximum length of the value
+	Type         byte   // column type, e.g. 0x03 for INT
+	Flags        uint16 // column flags, e.g. NOT NULL or UNSIGNED
+	Decimals     byte   // number of decimals for numeric and temporal types
+
+	// MariaDB extended metadata, e.g. "uuid" or "inet6" type
+	// of the column which is sent as a string or binary
+	// and "json" format of the LONGTEXT column declared as JSON
+	ExtendedType   string
+	ExtendedFormat string
+}
+
+// Nullable reports whether value of the column can be NULL
+func (c ColumnInfo) Nullable() bool {
+	return c.Flags&flagNotNULL == 0
+}
+
+// Unsigned reports whether column is of unsigned numeric type
+func (c ColumnInfo) Unsigned() bool {
+	return c.Flags&flagUnsigned != 0
+}
+
+// Zerofill reports whether column has ZEROFILL attribute,
+// so its values are padded with zeros up to MaxLength
+func (c ColumnInfo) Zerofill() bool {
+	return c.Flags&flagZerofill != 0
+}
+
+// TypeName returns SQL name of the column type like "INT" or "VARCHAR".
+// Binary string types are reported as "BINARY", "VARBINARY" and
+// "BLOB" types, the rest of them as "CHAR", "VARCHAR" and "TEXT" types.
+// UNSIGNED attribute isn't included (see func (ColumnInfo) Unsigned).
+// Empty string is returned for unknown type.
+func (c ColumnInfo) TypeName() string {
+	binary := c.CharacterSet == binaryCharacterSet
+	text := func(binaryName, textName string) string {
+		if binary {
+			return binaryName
+		}
+		return textName
+	}
+
+	switch c.Type {
+	case fieldTypeDecimal, fieldTypeNewDecimal:
+		return "DECIMAL"
+	case fieldTypeTiny:
+		return "TINYINT"
+	case fieldTypeShort:
+		return "SMALLINT"
+	case fieldTypeInt24:
+		return "MEDIUMINT"
+	case fieldTypeLong:
+		return "INT"
+	case fieldTypeLongLong:
+		return "BIGINT"
+	case fieldTypeFloat:
+		return "FLOAT"
+	case fieldTypeDouble:
+		return "DOUBLE"
+	case fieldTypeNULL:
+		return "NULL"
+	case fieldTypeTimestamp:
+		return "TIMESTAMP"
+	case fieldTypeDate, fieldTypeNewDate:
+		return "DATE"
+	case fieldTypeTime:
+		return "TIME"
+	case fieldTypeDateTime:
+		return "DATETIME"
+	case fieldTypeYear:
+		return "YEAR"
+	case fieldTypeBit:
+		return "BIT"
+	case fieldTypeJSON:
+		return "JSON"
+	case fieldTypeEnum:
+		return "ENUM"
+	case fieldTypeSet:
+		return "SET"
+	case fieldTypeGeometry:
+		return "GEOMETRY"
+	case fieldTypeTinyBLOB:
+		return text("TINYBLOB", "TINYTEXT")
+	case fieldTypeMediumBLOB:
+		return text("MEDIUMBLOB", "MEDIUMTEXT")
+	case fieldTypeLongBLOB:
+		return text("LONGBLOB", "LONGTEXT")
+	case fieldTypeBLOB:
+		return text("BLOB", "TEXT")
+	case fieldTypeVarChar, fieldTypeVarString:
+		return text("VARBINARY", "VARCHAR")
+	case fieldTypeString:
+		// server sends ENUM and SET columns as strings with flags
+		switch {
+		case c.Flags&flagEnum != 0:
+			return "ENUM"
+		case c.Flags&flagSet != 0:
+			return "SET"
+		}
+		return text("BINARY", "CHAR")
+	}
+	return ""
+}
+
+// MariaDB extended metadata types
+const (
+	extendedMetadataType   byte = 0x00
+	extendedMetadataFormat byte = 0x01
+)
+
+// parseExtendedMetadata parses key-value pairs of MariaDB extended metadata
+// (see https://mariadb.com/kb/en/result-set-packets/#column-definition-packet)
+func parseExtendedMetadata(column *ColumnInfo, metadata []byte) error {
+	for offset := 0; offset < len(metadata); {
+		key := metadata[offset]
+		value, next, err := readLengthEncodedString(metadata, offset+1)
+		if err != nil {
+			return err
+		}
+		offset = next
+
+		switch key {
+		case extendedMetadataType:
+			column.ExtendedType = string(value)
+		case extendedMetadataFormat:
+			column.ExtendedFormat = string(value)
+		}
+	}
+	return nil
+}
+
+// parseColumnDefinition parses Protocol::ColumnDefinition41 packet.
+// Extended is set when MARIADB_CLIENT_EXTENDED_METADATA
+// capability is negotiated with the server.
+func parseColumnDefinition(payload []byte, extended bool) (ColumnInfo, error) {
+	var column ColumnInfo
+	var err error
+	offset := 0
+
+	// catalog is always "def"
+	if _, offset, err = readLengthEncodedString(payload, offset); err != nil {
+		return column, err
+	}
+
+	names := []*string{&column.Schema, &column.Table, &column.OrgTable, &column.Name, &column.OrgName}
+	for _, name := range names {
+		var value []byte
+		if value, offset, err = readLengthEncodedString(payload, offset); err != nil {
+			return column, err
+		}
+		*name = string(value)
+	}
+
+	// MariaDB sends extended metadata before the fixed-length fields
+	if extended {
+		var metadata []byte
+		if metadata, offset, err = readLengthEncodedString(payload, offset); err != nil {
+			return column, err
+		}
+		if err = parseExtendedMetadata(&column, metadata); err != nil {
+			return column, err
+		}
+	}
+
+	// length of the fixed-length fields is always 0x0c
+	if _, offset, err = readLengthEncodedInteger(payload, offset); err != nil {
+		return column, err
+	}
+	if column.CharacterSet, offset, err = readUint16(payload, offset); err != nil {
+		return column, err
+	}
+	if column.MaxLength, offset, err = readUint32(payload, offset); err != nil {
+		return column, err
+	}
+	if offset+4 > len(payload) {
+		return column, errMalformedPacket
+	}
+	column.Type = payload[offset]
+	column.Flags = uint16(payload[offset+1]) | uint16(payload[offset+2])<<8
+	column.Decimals = payload[offset+3]
+
+	return column, nil
+}
@@ -0,0 +1,166 @@
+package mysqldriver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseColumnDefinition(t *testing.T) {
+	payload := []byte{
+		0x03, 'd', 'e', 'f',
+		0x04, 't', 'e', 's', 't',
+		0x01, 'p',
+		0x06, 'p', 'e', 'o', 'p', 'l', 'e',
+		0x03, 'a', 'g', 'e',
+		0x03, 'a', 'g', 'e',
+		0x0c,
+		0x3f, 0x00, // binary collation
+		0x0a, 0x00, 0x00, 0x00, // max length
+		fieldTypeLong,
+		0x21, 0x00, // NOT NULL, UNSIGNED
+		0x00,
+		0x00, 0x00, // filler
+	}
+
+	column, err := parseColumnDefinition(payload, false)
+	assert.NoError(t, err)
+	assert.Equal(t, column, ColumnInfo{
+		Schema:       "test",
+		Table:        "p",
+		OrgTable:     "people",
+		Name:         "age",
+		OrgName:      "age",
+		CharacterSet: 63,
+		MaxLength:    10,
+		Type:         fieldTypeLong,
+		Flags:        flagNotNULL | flagUnsigned,
+		Decimals:     0,
+	})
+	assert.False(t, column.Nullable())
+	assert.True(t, column.Unsigned())
+	assert.False(t, column.Zerofill())
+
+	_, err = parseColumnDefinition(payload[:30], false)
+	assert.Equal(t, err, errMalformedPacket)
+}
+
+func TestColumnInfoTypeName(t *testing.T) {
+	assert.Equal(t, ColumnInfo{Type: fieldTypeLong, Flags: flagUnsigned}.TypeName(), "INT")
+	assert.Equal(t, ColumnInfo{Type: fieldTypeNewDecimal}.TypeName(), "DECIMAL")
+	assert.Equal(t, ColumnInfo{Type: fieldTypeVarString, CharacterSet: 45}.TypeName(), "VARCHAR")
+	assert.Equal(t, ColumnInfo{Type: fieldTypeVarString, CharacterSet: binaryCharacterSet}.TypeName(), "VARBINARY")
+	assert.Equal(t, ColumnInfo{Type: fieldTypeBLOB, CharacterSet: 45}.TypeName(), "TEXT")
+	assert.Equal(t, ColumnInfo{Type: fieldTypeBLOB, CharacterSet: binaryCharacterSet}.TypeName(), "BLOB")
+	assert.Equal(t, ColumnInfo{Type: fieldTypeString, CharacterSet: 45, Flags: flagEnum}.TypeName(), "ENUM")
+	assert.Equal(t, ColumnInfo{Type: fieldTypeString, CharacterSet: 45}.TypeName(), "CHAR")
+	assert.Equal(t, ColumnInfo{Type: 0xe0}.TypeName(), "")
+}
+
+func TestParseColumnDefinitionOfExpression(t *testing.T) {
+	// RANK() OVER (ORDER BY age) AS position
+	payload := []byte{
+		0x03, 'd', 'e', 'f',
+		0x00, // schema
+		0x00, // table
+		0x00, // org_table
+		0x08, 'p', 'o', 's', 'i', 't', 'i', 'o', 'n',
+		0x00, // org_name
+		0x0c,
+		0x3f, 0x00,
+		0x15, 0x00, 0x00, 0x00,
+		fieldTypeLongLong,
+		0x81, 0x00, // NOT NULL, BINARY
+		0x00,
+		0x00, 0x00,
+	}
+
+	column, err := parseColumnDefinition(payload, false)
+	assert.NoError(t, err)
+	assert.Equal(t, column.Name, "position")
+	assert.Equal(t, column.OrgName, "")
+	assert.Equal(t, column.Table, "")
+	assert.Equal(t, column.Type, fieldTypeLongLong)
+}
+
+// UUID column of MariaDB 10.7 sent with extended metadata
+var mariaDBUUIDColumn = []byte{
+	0x03, 'd', 'e', 'f',
+	0x04, 't', 'e', 's', 't',
+	0x01, 't',
+	0x01, 't',
+	0x02, 'i', 'd',
+	0x02, 'i', 'd',
+	0x06, 0x00, 0x04, 'u', 'u', 'i', 'd',
+	0x0c,
+	0x08, 0x00, // latin1
+	0x24, 0x00, 0x00, 0x00, // max length
+	fieldTypeString,
+	0x81, 0x00, // NOT NULL, BINARY
+	0x00,
+	0x00, 0x00, // filler
+}
+
+func TestParseColumnDefinitionWithExtendedMetadata(t *testing.T) {
+	column, err := parseColumnDefinition(mariaDBUUIDColumn, true)
+	assert.NoError(t, err)
+	assert.Equal(t, column.Name, "id")
+	assert.Equal(t, column.Type, fieldTypeString)
+	assert.Equal(t, column.MaxLength, uint32(36))
+	assert.Equal(t, column.ExtendedType, "uuid")
+	assert.Equal(t, column.ExtendedFormat, "")
+
+	// JSON column is LONGTEXT with "json" format
+	payload := []byte{
+		0x03, 'd', 'e', 'f',
+		0x04, 't', 'e', 's', 't',
+		0x01, 't',
+		0x01, 't',
+		0x03, 'd', 'o', 'c',
+		0x03, 'd', 'o', 'c',
+		0x06, 0x01, 0x04, 'j', 's', 'o', 'n',
+		0x0c,
+		0x2d, 0x00, // utf8mb4_general_ci
+		0xff, 0xff, 0xff, 0xff, // max length
+		fieldTypeBLOB,
+		0x90, 0x00, // BLOB
+		0x00,
+		0x00, 0x00, // filler
+	}
+
+	column, err = parseColumnDefinition(payload, true)
+	assert.NoError(t, err)
+	assert.Equal(t, column.Name, "doc")
+	assert.Equal(t, column.ExtendedType, "")
+	assert.Equal(t, column.ExtendedFormat, "json")
+
+	// length of the format exceeds metadata
+	malformed := append([]byte{}, payload[:21]...)
+	malformed = append(malformed, 0x06, 0x01, 0x08, 'j', 's', 'o', 'n')
+	malformed = append(malformed, payload[28:]...)
+	_, err = parseColumnDefinition(malformed, true)
+	assert.Equal(t, err, errMalformedPacket)
+}
+
+func TestParseColumnDefinitionWithoutExtendedMetadata(t *testing.T) {
+	// COM_FIELD_LIST response of MySQL ends with the default value
+	payload := append(columnDefinition("name"), 0x07, 'u', 'n', 'k', 'n', 'o', 'w', 'n')
+	column, err := parseColumnDefinition(payload, false)
+	assert.NoError(t, err)
+	assert.Equal(t, column.Name, "name")
+	assert.Equal(t, column.Type, fieldTypeVarString)
+	assert.Equal(t, column.ExtendedType, "")
+}
+
+func TestQueryReadsExtendedMetadataWhenNegotiated(t *testing.T) {
+	conn := newPacketConn([]byte{0x01}, mariaDBUUIDColumn, eofPacket, []byte{0x01, '1'}, eofPacket)
+	conn.extendedMetadata = true
+
+	rows, err := conn.Query("SELECT id FROM t")
+	assert.NoError(t, err)
+	assert.Equal(t, rows.Columns()[0].ExtendedType, "uuid")
+	assert.Equal(t, rows.Columns()[0].MaxLength, uint32(36))
+	for rows.Next() {
+	}
+	assert.NoError(t, rows.LastError())
+}
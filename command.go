@@ -0,0 +1,134 @@
+package mysqldriver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pubnative/mysqlproto-go"
+)
+
+// ErrResetNotSupported is returned by Reset when the server
+// doesn't support COM_RESET_CONNECTION command
+var ErrResetNotSupported = errors.New("mysqldriver: server doesn't support COM_RESET_CONNECTION")
+
+// Debug sends COM_DEBUG command which makes the server
+// dump debug information into its error log.
+// It requires SUPER privilege, otherwise ERRPacket is returned
+// and connection can be used further.
+func (c *Conn) Debug() error {
+	return c.command(comDebug, nil)
+}
+
+// Ping checks whether connection is alive by sending COM_PING command.
+// Connection becomes invalid when server doesn't respond.
+func (c *Conn) Ping() error {
+	return c.command(comPing, nil)
+}
+
+// Reset sends COM_RESET_CONNECTION command which restores the state
+// of the session as it's after login without re-authentication:
+// user variables, temporary tables, prepared statements and session
+// variables are cleared and the transaction in progress is rolled back.
+// It's supported by MySQL 5.7.3 and MariaDB 10.2.4 and newer. Otherwise,
+// ErrResetNotSupported is returned without sending the command,
+// so the connection can be closed and replaced by a new one instead.
+func (c *Conn) Reset() error {
+	if !supportsResetConnection(c.handshake.ServerVersion) {
+		return ErrResetNotSupported
+	}
+	if err := c.command(comResetConnection, nil); err != nil {
+		return err
+	}
+	return c.resetSession()
+}
+
+// resetSession updates the state of the connection after the session
+// is reset by the server and enables the session options again
+func (c *Conn) resetSession() error {
+	if c.tx != nil {
+		c.tx.done = true
+		c.tx = nil
+	}
+	if c.trackGTIDs {
+		if _, err := c.Exec("SET SESSION session_track_gtids = OWN_GTID"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PingContext checks whether connection is alive the same way
+// as Ping does. When ctx is canceled or its deadline is exceeded
+// before the server responds, PingContext returns ctx.Err()
+// and connection becomes invalid.
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+//	defer cancel()
+//	if err := conn.PingContext(ctx); err != nil {
+//		conn.Close() // replica doesn't respond in time
+//	}
+func (c *Conn) PingContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := c.acquire(); err != nil {
+		return err
+	}
+	c.watchContext(ctx)
+
+	return c.sendSimpleCommand(comPing, nil)
+}
+
+// command sends the command which expects OK_PACKET
+// or EOF_PACKET in response
+func (c *Conn) command(command byte, payload []byte) error {
+	if err := c.acquire(); err != nil {
+		return err
+	}
+	return c.sendSimpleCommand(command, payload)
+}
+
+// sendSimpleCommand sends the command on the acquired connection
+// and reads OK_PACKET or EOF_PACKET in response
+func (c *Conn) sendSimpleCommand(command byte, payload []byte) error {
+	defer c.release()
+
+	if _, err := c.conn.Write(commandPacket(command, payload)); err != nil {
+		c.valid = false
+		return c.timeoutError(err)
+	}
+
+	packet, err := c.conn.NextPacket()
+	if err != nil {
+		c.valid = false
+		return c.timeoutError(err)
+	}
+
+	switch {
+	case packet.Payload[0] == mysqlproto.OK_PACKET || isEOFPacket(packet.Payload):
+		return nil
+	case packet.Payload[0] == mysqlproto.ERR_PACKET:
+		errPacket, err := mysqlproto.ParseERRPacket(packet.Payload, c.conn.CapabilityFlags)
+		if err != nil {
+			c.valid = false
+			return err
+		}
+		return errPacket
+	default:
+		c.valid = false
+		return fmt.Errorf("mysqldriver: unknown error occured. Payload: %x", packet.Payload)
+	}
+}
+
+// supportsResetConnection reports whether the server
+// supports COM_RESET_CONNECTION command. MariaDB reports
+// its version after "5.5.5-" prefix, e.g. "5.5.5-10.4.12-MariaDB".
+func supportsResetConnection(version string) bool {
+	if strings.Contains(version, "MariaDB") {
+		return versionAtLeast(strings.TrimPrefix(version, "5.5.5-"), 10, 2, 4)
+	}
+	return versionAtLeast(version, 5, 7, 3)
+}
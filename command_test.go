@@ -0,0 +1,100 @@
+package mysqldriver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnDebug(t *testing.T) {
+	conn, err := NewConn("root", "", "tcp", "127.0.0.1:3306", "test", time.Duration(0))
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	assert.NoError(t, conn.Debug())
+	assert.True(t, conn.valid)
+}
+
+func TestConnDebugWithoutPrivilege(t *testing.T) {
+	root, err := NewConn("root", "", "tcp", "127.0.0.1:3306", "test", time.Duration(0))
+	assert.NoError(t, err)
+	defer root.Close()
+	_, err = root.Exec("CREATE USER IF NOT EXISTS 'mysqldriver_debug'@'%'")
+	assert.NoError(t, err)
+	defer root.Exec("DROP USER 'mysqldriver_debug'@'%'")
+
+	conn, err := NewConn("mysqldriver_debug", "", "tcp", "127.0.0.1:3306", "", time.Duration(0))
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	err = conn.Debug()
+	_, ok := err.(mysqlproto.ERRPacket)
+	assert.True(t, ok)
+	assert.True(t, conn.valid)
+
+	rows, err := conn.Query("SELECT 1")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	assert.False(t, rows.Next())
+}
+
+func TestConnPing(t *testing.T) {
+	conn, err := NewConn("root", "", "tcp", "127.0.0.1:3306", "test", time.Duration(0))
+	assert.NoError(t, err)
+
+	assert.NoError(t, conn.Ping())
+	assert.True(t, conn.valid)
+
+	assert.Nil(t, conn.conn.Close())
+	assert.NotNil(t, conn.Ping())
+	assert.False(t, conn.valid)
+}
+
+func TestConnReset(t *testing.T) {
+	conn, s := newSessionConn("8.0.22", okPayload)
+	tx := &Tx{conn: conn}
+	conn.tx = tx
+
+	assert.NoError(t, conn.Reset())
+	assert.Equal(t, s.written, []byte{0x01, 0x00, 0x00, 0x00, comResetConnection})
+	assert.True(t, conn.valid)
+	// transaction is rolled back by the server
+	assert.Nil(t, conn.tx)
+	assert.Equal(t, tx.Commit(), ErrTxDone)
+}
+
+func TestConnResetNotSupported(t *testing.T) {
+	conn, s := newSessionConn("5.6.51-log")
+	assert.Equal(t, conn.Reset(), ErrResetNotSupported)
+	assert.Len(t, s.written, 0)
+	assert.True(t, conn.valid)
+}
+
+func TestSupportsResetConnection(t *testing.T) {
+	assert.True(t, supportsResetConnection("8.0.22"))
+	assert.True(t, supportsResetConnection("5.7.3"))
+	assert.False(t, supportsResetConnection("5.7.2"))
+	assert.True(t, supportsResetConnection("5.5.5-10.4.12-MariaDB"))
+	assert.True(t, supportsResetConnection("10.2.4-MariaDB-log"))
+	assert.False(t, supportsResetConnection("5.5.5-10.1.48-MariaDB"))
+	assert.False(t, supportsResetConnection(""))
+}
+
+func TestConnResetClearsSession(t *testing.T) {
+	conn, err := NewConn("root", "", "tcp", "127.0.0.1:3306", "test", time.Duration(0))
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Exec("SET @mysqldriver_reset = 1")
+	assert.NoError(t, err)
+	assert.NoError(t, conn.Reset())
+
+	rows, err := conn.Query("SELECT @mysqldriver_reset")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	_, null := rows.NullInt()
+	assert.True(t, null)
+	assert.False(t, rows.Next())
+}
@@ -0,0 +1,230 @@
+package mysqldriver
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"sync/atomic"
+)
+
+// compressedHeaderLen is the length of the compressed packet header:
+// length of compressed payload(3), sequence ID(1)
+// and length of uncompressed payload(3)
+const compressedHeaderLen = 7
+
+// minCompressLength is the length of the payload which
+// is sent uncompressed because compression doesn't pay off
+const minCompressLength = 50
+
+// clientZstdCompression is CLIENT_ZSTD_COMPRESSION_ALGORITHM capability
+const clientZstdCompression = uint32(CapabilityZstdCompression)
+
+// compression algorithms of Options.CompressionAlgorithm
+const (
+	compressionZlib = "zlib"
+	compressionZstd = "zstd"
+)
+
+// defaultZstdLevel is the default level of zstd used by the server
+const defaultZstdLevel = 3
+
+// maxZstdLevel is the max level of zstd accepted by the server
+const maxZstdLevel = 22
+
+// CompressionCodec compresses payloads of the compressed protocol.
+// The driver implements only zlib, zstd codec is set
+// by Options.ZstdCodec, e.g. adapter of a zstd package.
+type CompressionCodec interface {
+	// Compress appends compressed src to dst
+	Compress(dst, src []byte, level int) ([]byte, error)
+	// Decompress appends decompressed src to dst
+	Decompress(dst, src []byte) ([]byte, error)
+}
+
+// zlibCodec is CompressionCodec of zlib algorithm
+type zlibCodec struct{}
+
+func (zlibCodec) Compress(dst, src []byte, level int) ([]byte, error) {
+	buf := bytes.NewBuffer(dst)
+	w, err := zlib.NewWriterLevel(buf, level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(src); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (zlibCodec) Decompress(dst, src []byte) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	buf := bytes.NewBuffer(dst)
+	if _, err := buf.ReadFrom(r); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// compression implements the compressed protocol which wraps
+// packets of the connection after the handshake
+// (see https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_basic_compression.html)
+type compression struct {
+	conn      *netConn
+	algorithm string           // "zlib" or "zstd"
+	codec     CompressionCodec // codec of the algorithm
+	level     int              // level of the written packets
+	seq       byte             // sequence ID of the next compressed packet
+	writes    packetScanner    // finds commands which reset the sequence
+	header    [compressedHeaderLen]byte
+	data      []byte // uncompressed payload which isn't read yet
+}
+
+// startCompression makes the connection compress packets by zlib
+// after CLIENT_COMPRESS is negotiated by the handshake
+func (c *netConn) startCompression() {
+	level := c.compressionLevel
+	if level == 0 {
+		level = zlib.DefaultCompression
+	}
+	c.compression = &compression{conn: c, algorithm: compressionZlib, codec: zlibCodec{}, level: level}
+}
+
+// startZstdCompression makes the connection compress packets by zstd
+// after CLIENT_ZSTD_COMPRESSION_ALGORITHM is negotiated by the handshake
+func (c *netConn) startZstdCompression() {
+	c.compression = &compression{conn: c, algorithm: compressionZstd, codec: c.zstdCodec, level: c.zstdLevel()}
+}
+
+// zstdLevel returns zstd level of the written packets
+// which is also sent to the server in the handshake response
+func (c *netConn) zstdLevel() int {
+	if c.compressionLevel == 0 {
+		return defaultZstdLevel
+	}
+	return c.compressionLevel
+}
+
+// CompressionAlgorithm returns "zlib" or "zstd" when the connection
+// uses the compressed protocol (see Options.Compress)
+// and an empty string otherwise
+func (c *Conn) CompressionAlgorithm() string {
+	if c.netConn == nil || c.netConn.compression == nil {
+		return ""
+	}
+	return c.netConn.compression.algorithm
+}
+
+// Read returns uncompressed payload of the compressed packets
+func (z *compression) Read(b []byte) (int, error) {
+	if len(z.data) == 0 {
+		if err := z.readPacket(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(b, z.data)
+	z.data = z.data[n:]
+	return n, nil
+}
+
+// readPacket reads the next compressed packet
+func (z *compression) readPacket() error {
+	if _, err := io.ReadFull(wire{z.conn}, z.header[:]); err != nil {
+		return err
+	}
+	compressedLen := packetLength(z.header[:])
+	uncompressedLen := packetLength(z.header[4:])
+	z.seq = z.header[3] + 1
+
+	payload := make([]byte, compressedLen)
+	if _, err := io.ReadFull(wire{z.conn}, payload); err != nil {
+		return err
+	}
+	if uncompressedLen == 0 {
+		z.data = payload
+		return nil
+	}
+
+	data, err := z.codec.Decompress(make([]byte, 0, uncompressedLen), payload)
+	if err != nil {
+		return err
+	}
+	if len(data) != uncompressedLen {
+		return errMalformedPacket
+	}
+	z.data = data
+	return nil
+}
+
+// Write sends the packets in compressed packets.
+// Sequence of compressed packets starts over with every command.
+func (z *compression) Write(b []byte) (int, error) {
+	for data := b; len(data) > 0; {
+		seq, rest, ok := z.writes.next(data)
+		if !ok {
+			break
+		}
+		if seq == 0 {
+			z.seq = 0
+		}
+		data = rest
+	}
+
+	for data := b; len(data) > 0; {
+		n := len(data)
+		if n > maxPacketSize {
+			n = maxPacketSize
+		}
+		if err := z.writePacket(data[:n]); err != nil {
+			return 0, err
+		}
+		data = data[n:]
+	}
+	return len(b), nil
+}
+
+// writePacket sends the data in one compressed packet. Short data
+// or data which doesn't shrink is sent uncompressed.
+func (z *compression) writePacket(data []byte) error {
+	payload := data
+	uncompressedLen := 0
+	if len(data) >= minCompressLength {
+		compressed, err := z.codec.Compress(nil, data, z.level)
+		if err != nil {
+			return err
+		}
+		if len(compressed) < len(data) {
+			payload, uncompressedLen = compressed, len(data)
+		}
+	}
+
+	packet := make([]byte, compressedHeaderLen, compressedHeaderLen+len(payload))
+	packet[0], packet[1], packet[2] = byte(len(payload)), byte(len(payload)>>8), byte(len(payload)>>16)
+	packet[3] = z.seq
+	packet[4], packet[5], packet[6] = byte(uncompressedLen), byte(uncompressedLen>>8), byte(uncompressedLen>>16)
+	z.seq++
+	_, err := wire{z.conn}.Write(append(packet, payload...))
+	return err
+}
+
+// wire reads and writes bytes sent over the network,
+// so compressed packets are counted by BytesReceived and BytesSent
+type wire struct{ c *netConn }
+
+func (w wire) Read(b []byte) (int, error) {
+	n, err := w.c.Conn.Read(b)
+	atomic.AddUint64(&w.c.bytesRead, uint64(n))
+	return n, err
+}
+
+func (w wire) Write(b []byte) (int, error) {
+	n, err := w.c.Conn.Write(b)
+	atomic.AddUint64(&w.c.bytesWritten, uint64(n))
+	return n, err
+}
@@ -0,0 +1,257 @@
+package mysqldriver
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressionWritesPackets(t *testing.T) {
+	w := &writeRecorder{}
+	conn := &netConn{Conn: w}
+	conn.startCompression()
+
+	ping := commandPacket(comPing, nil)
+	query := commandPacket(0x03, []byte("SELECT '"+strings.Repeat("a", 1000)+"'")) // COM_QUERY
+	_, err := conn.Write(ping)
+	assert.NoError(t, err)
+	_, err = conn.Write(query)
+	assert.NoError(t, err)
+
+	// short packet is sent uncompressed
+	assert.Equal(t, w.written[:compressedHeaderLen+len(ping)], append([]byte{0x05, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, ping...))
+	compressed := w.written[compressedHeaderLen+len(ping):]
+	assert.Equal(t, compressed[3], byte(0)) // sequence starts over with the command
+	assert.Equal(t, packetLength(compressed[4:]), len(query))
+	assert.True(t, len(compressed) < len(query))
+	assert.Equal(t, conn.bytesWritten, uint64(len(w.written)))
+
+	r := &netConn{Conn: &readRecorder{data: bytes.NewReader(w.written)}}
+	r.startCompression()
+	data := make([]byte, len(ping)+len(query))
+	_, err = io.ReadFull(r, data)
+	assert.NoError(t, err)
+	assert.Equal(t, data, append(ping, query...))
+	assert.Equal(t, r.bytesRead, uint64(len(w.written)))
+}
+
+func TestCompressionContinuesSequenceOfServer(t *testing.T) {
+	packet := []byte{0x01, 0x00, 0x00, 0x01, authMoreData}
+	data := append([]byte{0x05, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00}, packet...)
+	s := &sessionConn{readRecorder: readRecorder{data: bytes.NewReader(data)}}
+	conn := &netConn{Conn: s}
+	conn.startCompression()
+
+	_, err := io.ReadFull(conn, make([]byte, len(packet)))
+	assert.NoError(t, err)
+	_, err = conn.Write([]byte{0x01, 0x00, 0x00, 0x02, cachingSHA2RequestPublicKey})
+	assert.NoError(t, err)
+	assert.Equal(t, s.written[3], byte(4))
+}
+
+func TestCompressionReturnsErrorOfMalformedPacket(t *testing.T) {
+	w := &writeRecorder{}
+	conn := &netConn{Conn: w}
+	conn.startCompression()
+	_, err := conn.Write(commandPacket(0x03, []byte(strings.Repeat("a", 100))))
+	assert.NoError(t, err)
+	w.written[4]++ // uncompressed length
+
+	r := &netConn{Conn: &readRecorder{data: bytes.NewReader(w.written)}}
+	r.startCompression()
+	_, err = r.Read(make([]byte, 10))
+	assert.Equal(t, err, errMalformedPacket)
+}
+
+func TestConnectHandshakeStartsCompression(t *testing.T) {
+	conn, done := startAuthServer(t, func(s *authServer) {
+		s.read()
+		s.write(2, []byte{authMoreData, cachingSHA2FastAuthSuccess})
+		s.write(3, okPayload)
+	})
+	defer conn.Close()
+
+	stream, err := connectHandshake(conn, capabilityFlags|mysqlproto.CLIENT_COMPRESS, charsetUTF8Code, nil, "root", "secret", "test", nil, false, 0)
+	assert.NoError(t, err)
+	assert.True(t, stream.CapabilityFlags&mysqlproto.CLIENT_COMPRESS != 0)
+	assert.NotNil(t, conn.compression)
+	assert.Equal(t, (&Conn{netConn: conn}).CompressionAlgorithm(), "zlib")
+	<-done
+}
+
+func TestConnectHandshakeFallsBackToUncompressed(t *testing.T) {
+	// server allows only zstd or uncompressed protocol
+	handshake := append([]byte(nil), handshakePayload...)
+	handshake[21] &^= byte(mysqlproto.CLIENT_COMPRESS)
+	conn, done := startHandshakeServer(t, handshake, func(s *authServer) {
+		s.read()
+		s.write(2, []byte{authMoreData, cachingSHA2FastAuthSuccess})
+		s.write(3, okPayload)
+	})
+	defer conn.Close()
+
+	stream, err := connectHandshake(conn, capabilityFlags|mysqlproto.CLIENT_COMPRESS, charsetUTF8Code, nil, "root", "secret", "test", nil, false, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, stream.CapabilityFlags&mysqlproto.CLIENT_COMPRESS, uint32(0))
+	assert.Nil(t, conn.compression)
+	assert.Equal(t, (&Conn{netConn: conn}).CompressionAlgorithm(), "")
+	<-done
+}
+
+// testZstdCodec stands for zstd codec of the application,
+// it compresses by zlib and records the level
+type testZstdCodec struct{ level int }
+
+func (c *testZstdCodec) Compress(dst, src []byte, level int) ([]byte, error) {
+	c.level = level
+	return zlibCodec{}.Compress(dst, src, zlib.BestSpeed)
+}
+
+func (c *testZstdCodec) Decompress(dst, src []byte) ([]byte, error) {
+	return zlibCodec{}.Decompress(dst, src)
+}
+
+func TestCompressionZstdCodec(t *testing.T) {
+	codec := &testZstdCodec{}
+	w := &writeRecorder{}
+	conn := &netConn{Conn: w, zstdCodec: codec}
+	conn.startZstdCompression()
+	query := commandPacket(0x03, []byte("SELECT '"+strings.Repeat("a", 1000)+"'"))
+	_, err := conn.Write(query)
+	assert.NoError(t, err)
+	assert.Equal(t, codec.level, defaultZstdLevel)
+	assert.True(t, len(w.written) < len(query))
+
+	r := &netConn{Conn: &readRecorder{data: bytes.NewReader(w.written)}, zstdCodec: codec}
+	r.startZstdCompression()
+	data := make([]byte, len(query))
+	_, err = io.ReadFull(r, data)
+	assert.NoError(t, err)
+	assert.Equal(t, data, query)
+}
+
+func TestConnectHandshakeNegotiatesZstd(t *testing.T) {
+	conn, done := startAuthServer(t, func(s *authServer) {
+		_, response := s.read()
+		flags := binary.LittleEndian.Uint32(response)
+		assert.True(t, flags&clientZstdCompression != 0)
+		assert.Equal(t, flags&mysqlproto.CLIENT_COMPRESS, uint32(0))
+		assert.Equal(t, response[len(response)-1], byte(7)) // zstd level
+		s.write(2, []byte{authMoreData, cachingSHA2FastAuthSuccess})
+		s.write(3, okPayload)
+	})
+	defer conn.Close()
+	conn.zstdCodec, conn.compressionLevel = &testZstdCodec{}, 7
+
+	_, err := connectHandshake(conn, capabilityFlags|mysqlproto.CLIENT_COMPRESS|clientZstdCompression, charsetUTF8Code, nil, "root", "secret", "test", nil, false, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, (&Conn{netConn: conn}).CompressionAlgorithm(), "zstd")
+	<-done
+}
+
+func TestConnectHandshakeFallsBackFromZstdToZlib(t *testing.T) {
+	handshake := append([]byte(nil), handshakePayload...)
+	handshake[27] &^= byte(clientZstdCompression >> 24)
+	conn, done := startHandshakeServer(t, handshake, func(s *authServer) {
+		_, response := s.read()
+		flags := binary.LittleEndian.Uint32(response)
+		assert.Equal(t, flags&clientZstdCompression, uint32(0))
+		assert.True(t, flags&mysqlproto.CLIENT_COMPRESS != 0)
+		s.write(2, []byte{authMoreData, cachingSHA2FastAuthSuccess})
+		s.write(3, okPayload)
+	})
+	defer conn.Close()
+	conn.zstdCodec = &testZstdCodec{}
+
+	_, err := connectHandshake(conn, capabilityFlags|mysqlproto.CLIENT_COMPRESS|clientZstdCompression, charsetUTF8Code, nil, "root", "secret", "test", nil, false, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, (&Conn{netConn: conn}).CompressionAlgorithm(), "zlib")
+	<-done
+}
+
+func TestCompressionLevel(t *testing.T) {
+	var values []string
+	for i := 0; i < 2000; i++ {
+		values = append(values, strconv.Itoa(i*i))
+	}
+	query := commandPacket(0x03, []byte("SELECT * FROM dogs WHERE id IN ("+strings.Join(values, ",")+")"))
+	written := func(level int) []byte {
+		w := &writeRecorder{}
+		conn := &netConn{Conn: w, compressionLevel: level}
+		conn.startCompression()
+		_, err := conn.Write(query)
+		assert.NoError(t, err)
+
+		r := &netConn{Conn: &readRecorder{data: bytes.NewReader(w.written)}}
+		r.startCompression()
+		read := make([]byte, len(query))
+		_, err = io.ReadFull(r, read)
+		assert.NoError(t, err)
+		assert.Equal(t, read, query)
+		return w.written
+	}
+
+	assert.Equal(t, written(0), written(zlib.DefaultCompression))
+	assert.True(t, len(written(9)) < len(written(1)))
+}
+
+func TestNewConnOptionsInvalidCompressionLevel(t *testing.T) {
+	_, err := NewConnOptions(context.Background(), "root", "", "tcp", "127.0.0.1:1", "test", time.Duration(0),
+		Options{Compress: true, CompressionLevel: 10})
+	assert.EqualError(t, err, "mysqldriver: invalid compression level 10")
+
+	_, err = NewConnOptions(context.Background(), "root", "", "tcp", "127.0.0.1:1", "test", time.Duration(0),
+		Options{Compress: true, CompressionAlgorithm: "zstd", ZstdCodec: &testZstdCodec{}, CompressionLevel: 23})
+	assert.EqualError(t, err, "mysqldriver: invalid compression level 23")
+}
+
+func TestNewConnOptionsInvalidCompressionAlgorithm(t *testing.T) {
+	_, err := NewConnOptions(context.Background(), "root", "", "tcp", "127.0.0.1:1", "test", time.Duration(0),
+		Options{Compress: true, CompressionAlgorithm: "lz4"})
+	assert.EqualError(t, err, "mysqldriver: unsupported compression algorithm \"lz4\"")
+
+	_, err = NewConnOptions(context.Background(), "root", "", "tcp", "127.0.0.1:1", "test", time.Duration(0),
+		Options{Compress: true, CompressionAlgorithm: "zstd"})
+	assert.EqualError(t, err, "mysqldriver: zstd compression requires ZstdCodec")
+}
+
+func TestConnCompress(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		note := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 20)
+		for i := 0; i < 100; i++ {
+			_, err := conn.Exec("INSERT INTO people(firstname, lastname, note) VALUES ('Bob', 'Smith', '" + note + "')")
+			assert.NoError(t, err)
+		}
+	})
+
+	received := func(opts Options) uint64 {
+		conn, err := NewConnOptions(context.Background(), "root", "", "tcp", "127.0.0.1:3306", "test", time.Duration(0), opts)
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		before := conn.BytesReceived()
+		rows, err := conn.Query("SELECT * FROM people")
+		assert.NoError(t, err)
+		count := 0
+		for rows.Next() {
+			count++
+		}
+		assert.NoError(t, rows.LastError())
+		assert.Equal(t, count, 100)
+		return conn.BytesReceived() - before
+	}
+
+	plain := received(Options{})
+	compressed := received(Options{Compress: true})
+	t.Logf("wide SELECT: %d bytes plain, %d bytes compressed", plain, compressed)
+	assert.True(t, compressed < plain/2)
+}
@@ -1,8 +1,14 @@
 package mysqldriver
 
 import (
+	"compress/zlib"
 	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"net"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/pubnative/mysqlproto-go"
@@ -12,17 +18,115 @@ var capabilityFlags = mysqlproto.CLIENT_LONG_PASSWORD |
 	mysqlproto.CLIENT_FOUND_ROWS |
 	mysqlproto.CLIENT_LONG_FLAG |
 	mysqlproto.CLIENT_CONNECT_WITH_DB |
+	mysqlproto.CLIENT_LOCAL_FILES |
 	mysqlproto.CLIENT_PLUGIN_AUTH |
 	mysqlproto.CLIENT_TRANSACTIONS |
 	mysqlproto.CLIENT_PROTOCOL_41 |
+	mysqlproto.CLIENT_MULTI_RESULTS |
+	mysqlproto.CLIENT_PS_MULTI_RESULTS |
 	mysqlproto.CLIENT_SECURE_CONNECTION |
-	mysqlproto.CLIENT_SESSION_TRACK
+	mysqlproto.CLIENT_SESSION_TRACK |
+	mysqlproto.CLIENT_CONNECT_ATTRS
+
+// ErrConnectionBusy is returned when a query or command is performed
+// while the connection is still in use by another one. For instance,
+// when another go-routine uses the same connection or when
+// the result set of the previous query isn't read completely.
+var ErrConnectionBusy = errors.New("mysqldriver: connection is busy with another query")
 
 // Conn represents connection to MySQL server
 type Conn struct {
-	conn   mysqlproto.Conn
-	valid  bool
-	closed bool
+	// StopOnParseError makes Rows.Next return false as soon as
+	// a value can't be parsed by one of the accessors, e.g. when
+	// Int() is called for non-numeric value. The rest of the rows
+	// are discarded. By default, parse error is returned
+	// by LastError after iterating over all rows.
+	StopOnParseError bool
+
+	// StripGrouping makes numeric accessors of Rows and Row remove
+	// thousands separators before parsing, so formatted numbers
+	// like "1,234.56" returned by FORMAT() can be read by Float64.
+	// By default, such values can't be parsed.
+	StripGrouping bool
+
+	// DefaultSchema qualifies bare table names following FROM, JOIN,
+	// INTO and UPDATE keywords of the statements performed by Query,
+	// Exec, Prepare and the table of LoadData, e.g. "SELECT * FROM dogs" is sent as
+	// "SELECT * FROM `tenant`.dogs". Names are found heuristically
+	// without parsing SQL (see qualifyTables), so statements which
+	// are already qualified aren't changed. Empty value disables it.
+	DefaultSchema string
+
+	// QueryTimeout limits the total time of Query and Exec
+	// including reading all rows of the result set. It doesn't
+	// depend on the read timeout which is applied to every packet.
+	// When timeout is exceeded, Rows.Next returns false and
+	// Rows.LastError returns ErrQueryTimeout. Connection becomes
+	// invalid and is closed when it's returned to the pool.
+	// Zero value means no timeout.
+	QueryTimeout time.Duration
+
+	// MaxExecutionTime makes the server abort statements performed
+	// by QueryContext which run for longer. SELECT statements get
+	// MAX_EXECUTION_TIME optimizer hint when the server supports it
+	// (MySQL 5.7.8 and newer). Otherwise, max_execution_time session
+	// variable (max_statement_time of MariaDB) is set before the query
+	// and restored after it. In this case rows are read into memory
+	// before QueryContext returns, so the variable is restored even
+	// when the query fails. Zero value means no limit.
+	MaxExecutionTime time.Duration
+
+	// Location is used by Time and NullTime accessors to interpret
+	// DATE, DATETIME and TIMESTAMP values, e.g. it should be set
+	// to the time zone of the session to read TIMESTAMP values
+	// correctly. Nil value means UTC.
+	Location *time.Location
+
+	// MaxBufferBytes limits the size of the result set
+	// read by QueryBuffered. Zero value means no limit.
+	MaxBufferBytes int64
+
+	// MaxRows limits the number of rows read by QueryRows
+	// and Rows.MapScanAll.
+	// Zero value means no limit.
+	MaxRows int
+
+	// LocalFiles provides files requested by the server for
+	// LOAD DATA LOCAL INFILE statements performed by Exec
+	// (see AllowLocalFiles). Nil value refuses all files.
+	LocalFiles LocalFileHandler
+
+	conn    mysqlproto.Conn
+	netConn *netConn // network connection used by conn
+	valid   bool
+	closed  bool
+	busy    int32         // 1 while a command or a result set uses the stream
+	watch   *contextWatch // context of the current query
+	tx      *Tx           // transaction in progress
+
+	// dial establishes a new connection with the same
+	// credentials when Options.KillOnCancel is set
+	dial func(ctx context.Context) (*Conn, error)
+
+	interrupted int32 // 1 after Rows.Interrupt, updated atomically
+
+	handshake       HandshakeInfo
+	handshakePacket []byte                  // payload of the initial handshake packet
+	status          uint16                  // status flags of the last OK_PACKET
+	lastOK          mysqlproto.OKPacket     // the last OK_PACKET returned by Exec
+	warnings        uint16                  // warning count of the last OK_PACKET or EOF_PACKET
+	schemas         map[string]*TableSchema // cache of TableSchema
+
+	maxAllowedPacket int       // max_allowed_packet when Options.CheckPacketSize is set
+	idleSince        time.Time // time when connection is returned to the pool
+	currentUser      string    // cached result of CurrentUser
+	consistencyToken string    // GTID set reported in the session state
+	trackGTIDs       bool      // session_track_gtids is enabled again by Reset
+	database         string    // database selected by NewConn or ChangeUser
+	userChanged      bool      // ChangeUser has been sent, the connection isn't reused
+	charset          string    // character set of the session
+	connectAttrs     []byte    // encoded connection attributes sent by ChangeUser
+	extendedMetadata bool      // MariaDB sends extended metadata of the columns
 }
 
 // Contains connection statistics
@@ -43,37 +147,271 @@ func NewConn(username, password, protocol, address, database string, readTimeout
 // TODO use Go Context to establish a MySQL connection.
 func NewConnContext(ctx context.Context, username, password, protocol, address,
 	database string, readTimeout time.Duration) (*Conn, error) {
+	return NewConnOptions(ctx, username, password, protocol, address, database, readTimeout, Options{})
+}
+
+// Options changes the way connection is established (see NewConnOptions).
+// Zero value represents the default behavior of NewConn.
+type Options struct {
+	// NoFoundRows disables CLIENT_FOUND_ROWS capability, so AffectedRows
+	// of UPDATE statement is the number of changed rows instead of
+	// the number of matched rows (see func (Conn) MatchedRows).
+	NoFoundRows bool
+
+	// CheckPacketSize reads max_allowed_packet of the server after
+	// connecting, so Query and Exec return PacketTooLargeError
+	// for too large statements without sending them. Otherwise,
+	// the error is returned only when server refuses the statement
+	// and connection can't be used after that.
+	// The limit is available by func (Conn) MaxAllowedPacket.
+	CheckPacketSize bool
+
+	// KillOnCancel makes QueryContext and ExecContext stop the statement
+	// on the server when the context is canceled. Driver establishes
+	// another connection with the same credentials and sends
+	// "KILL QUERY <connection id>" over it. Otherwise, server
+	// keeps executing the statement of the abandoned connection.
+	KillOnCancel bool
+
+	// StrictSequence verifies that sequence IDs of the packets sent
+	// by the server follow each other. Packet with unexpected
+	// sequence ID fails the command with ErrSequenceMismatch
+	// and connection becomes invalid instead of reading
+	// the corrupted stream. It's useful to detect proxies which
+	// mishandle the protocol, but some servers reset sequence IDs
+	// in the unusual ways, so it's disabled by default.
+	StrictSequence bool
+
+	// TrackGTIDs enables session_track_gtids of the session after
+	// connecting, so the server reports GTID set of the transactions
+	// committed by the connection (see func (Conn) ConsistencyToken).
+	TrackGTIDs bool
+
+	// TLSMode encrypts the connection with TLS. The server must
+	// have CLIENT_SSL capability, otherwise ErrTLSNotSupported
+	// is returned. TLSConfig set with TLSDisabled mode
+	// works as TLSVerifyFull.
+	TLSMode TLSMode
+
+	// TLSConfig configures TLS client, e.g. root CAs
+	// and client certificates. Empty ServerName is set
+	// to the host of the address.
+	TLSConfig *tls.Config
+
+	// Compress enables the compressed protocol when the server
+	// has CLIENT_COMPRESS capability. Packets are compressed
+	// by zlib, so it reduces traffic of large result sets
+	// at the cost of CPU, e.g. over slow links to the server.
+	// When the server doesn't allow zlib (protocol_compression_algorithms
+	// of MySQL 8.0.18+), the connection falls back to the uncompressed
+	// protocol, see func (Conn) CompressionAlgorithm.
+	Compress bool
+
+	// CompressionAlgorithm is "zlib" or "zstd" algorithm of the
+	// compressed protocol when Compress is set. zstd of MySQL 8.0.18+
+	// requires ZstdCodec, when the server doesn't support it
+	// the connection falls back to zlib. Empty value means "zlib".
+	CompressionAlgorithm string
+
+	// ZstdCodec compresses packets when CompressionAlgorithm
+	// is "zstd". The standard library doesn't implement zstd,
+	// so the codec is provided by the application.
+	ZstdCodec CompressionCodec
+
+	// CompressionLevel is the level of the packets sent by the driver
+	// when Compress is set: from 1 (best speed) to 9 (best compression)
+	// for zlib or up to 22 for zstd. Level of zstd is sent
+	// to the server, level of zlib is chosen by the server.
+	// Zero value means the default level of the algorithm.
+	CompressionLevel int
+
+	// ConnectTimeout limits establishing of the connection including
+	// dialing, TLS handshake and authentication. It works along with
+	// the deadline of the context passed to NewConnOptions.
+	// Zero value means no timeout.
+	ConnectTimeout time.Duration
+
+	// WriteTimeout is applied to every write to the connection,
+	// the same way as the read timeout is applied to every packet
+	// read from the stream. When it's exceeded, the command fails with
+	// the timeout error of the network connection and connection
+	// becomes invalid. Zero value means no timeout.
+	WriteTimeout time.Duration
+
+	// Charset is the character set of the session, e.g. "utf8mb4"
+	// or "latin1". It's sent in the handshake response and set
+	// by "SET NAMES" after connecting. Values of the text protocol
+	// are sent by the server in this character set as raw bytes,
+	// they aren't converted by the driver (see charset.DecodedString).
+	// Character sets which aren't ASCII-safe like big5, sjis, cp932,
+	// gbk and gb18030 are refused, because EscapeString and QueryArgs
+	// can't escape strings of them safely. Don't change the character
+	// set of the session by SET NAMES for the same reason.
+	// Empty value means "utf8".
+	Charset string
+
+	// ConnectAttrs are connection attributes sent to the server along
+	// with the default ones like _client_name, _os and _pid, e.g.
+	// {"program_name": "billing"}. DBAs can find connections of the
+	// application in performance_schema.session_connect_attrs.
+	// Attributes aren't sent when the server doesn't have
+	// CLIENT_CONNECT_ATTRS capability.
+	ConnectAttrs map[string]string
+}
+
+// NewConnOptions establishes a connection to the DB
+// the same way as NewConnContext does using the given options
+func NewConnOptions(ctx context.Context, username, password, protocol, address,
+	database string, readTimeout time.Duration, opts Options) (*Conn, error) {
+
+	charset := strings.ToLower(opts.Charset)
+	if charset == "" {
+		charset = defaultCharset
+	}
+	if !supportedCharset(charset) {
+		return nil, fmt.Errorf("mysqldriver: unsupported charset %q", opts.Charset)
+	}
 
-	conn, err := (&net.Dialer{}).DialContext(ctx, protocol, address)
+	var zstdCodec CompressionCodec
+	maxLevel := zlib.BestCompression
+	switch opts.CompressionAlgorithm {
+	case "", compressionZlib:
+	case compressionZstd:
+		if opts.ZstdCodec == nil {
+			return nil, errors.New("mysqldriver: zstd compression requires ZstdCodec")
+		}
+		zstdCodec, maxLevel = opts.ZstdCodec, maxZstdLevel
+	default:
+		return nil, fmt.Errorf("mysqldriver: unsupported compression algorithm %q", opts.CompressionAlgorithm)
+	}
+	if opts.CompressionLevel < 0 || opts.CompressionLevel > maxLevel {
+		return nil, fmt.Errorf("mysqldriver: invalid compression level %d", opts.CompressionLevel)
+	}
+
+	attrs := encodeConnectAttrs(connectAttrs(opts.ConnectAttrs))
+
+	flags := capabilityFlags
+	if opts.NoFoundRows {
+		flags &^= mysqlproto.CLIENT_FOUND_ROWS
+	}
+	if opts.Compress {
+		flags |= mysqlproto.CLIENT_COMPRESS
+		if zstdCodec != nil {
+			flags |= clientZstdCompression
+		}
+	}
+
+	var deadline time.Time
+	if opts.ConnectTimeout > 0 {
+		deadline = time.Now().Add(opts.ConnectTimeout)
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	dialed, err := (&net.Dialer{}).DialContext(ctx, protocol, address)
 	if err != nil {
 		return nil, err
 	}
+	conn := &netConn{Conn: dialed, capture: true, strictSequence: opts.StrictSequence, writeTimeout: opts.WriteTimeout, compressionLevel: opts.CompressionLevel, zstdCodec: zstdCodec}
+	// the stream can't extend the deadline by the read timeout
+	// until the connection is established
+	if err := conn.setQueryDeadline(deadline); err != nil {
+		dialed.Close()
+		return nil, err
+	}
 
-	stream, err := mysqlproto.ConnectPlainHandshake(
-		conn, capabilityFlags,
-		username, password, database, nil, readTimeout,
+	stream, err := connectHandshake(
+		conn, flags, handshakeCollation(charset), attrs,
+		username, password, database,
+		opts.tlsConfig(protocol, address), protocol == "unix", readTimeout,
 	)
 
 	if err != nil {
-		return &Conn{conn: stream, valid: false, closed: false}, err
+		return &Conn{conn: stream, netConn: conn, valid: false, closed: false}, err
 	}
 
-	if err = setUTF8Charset(stream); err != nil {
-		return &Conn{conn: stream, valid: false, closed: false}, err
+	// server information isn't required to use the connection,
+	// so malformed handshake is ignored
+	packet := conn.firstPacket()
+	handshake, _ := parseHandshake(packet)
+	conn.capture, conn.first = false, nil
+
+	if err = setCharset(stream, charset); err != nil {
+		return &Conn{conn: stream, netConn: conn, handshake: handshake, handshakePacket: packet, status: handshake.StatusFlags, valid: false, closed: false}, err
+	}
+	if err = conn.setQueryDeadline(time.Time{}); err != nil {
+		return &Conn{conn: stream, netConn: conn, handshake: handshake, handshakePacket: packet, status: handshake.StatusFlags, valid: false, closed: false}, err
 	}
 
-	return &Conn{conn: stream, valid: true, closed: false}, nil
+	c := &Conn{conn: stream, netConn: conn, handshake: handshake, handshakePacket: packet, status: handshake.StatusFlags, valid: true, closed: false, database: database, charset: charset, connectAttrs: attrs}
+	// connectHandshake negotiates extended capabilities the same way
+	c.extendedMetadata = handshake.MariaDBCapabilities&mariaDBClientCapabilities&mariaDBExtendedMetadata != 0
+	if opts.KillOnCancel {
+		c.dial = func(ctx context.Context) (*Conn, error) {
+			return NewConnOptions(ctx, username, password, protocol, address, database, readTimeout, Options{
+				TLSMode:        opts.TLSMode,
+				TLSConfig:      opts.TLSConfig,
+				ConnectTimeout: opts.ConnectTimeout,
+				WriteTimeout:   opts.WriteTimeout,
+				Charset:        opts.Charset,
+				ConnectAttrs:   opts.ConnectAttrs,
+			})
+		}
+	}
+	if opts.CheckPacketSize {
+		if err = c.readMaxAllowedPacket(); err != nil {
+			c.valid = false
+			return c, err
+		}
+	}
+	if opts.TrackGTIDs {
+		c.trackGTIDs = true
+		if _, err = c.Exec("SET SESSION session_track_gtids = OWN_GTID"); err != nil {
+			c.valid = false
+			return c, err
+		}
+	}
+	return c, nil
 }
 
-// Close closes the connection
+// Close sends COM_QUIT command, so the server doesn't count
+// the connection as aborted, and closes the connection.
+// COM_QUIT isn't sent when the connection is invalid or busy
+// with another command. Connection can't be used after Close.
+// It's safe to call Close several times.
 func (c *Conn) Close() error {
-	if !c.closed {
-		c.closed = true
-		return c.conn.Close()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	if c.valid && c.acquire() == nil {
+		// server closes the connection without the response,
+		// so the error of the connection gone already is ignored
+		c.conn.Write(commandPacket(comQuit, nil))
+		c.release()
+	}
+	c.valid = false
+	return c.conn.Close()
+}
+
+// acquire marks connection as busy. It returns ErrConnectionBusy
+// when connection is already in use by another command
+func (c *Conn) acquire() error {
+	if !atomic.CompareAndSwapInt32(&c.busy, 0, 1) {
+		return ErrConnectionBusy
 	}
 	return nil
 }
 
+// release marks connection as available for the next command
+func (c *Conn) release() {
+	c.stopWatch()
+	c.stopQueryTimeout()
+	atomic.StoreInt32(&c.busy, 0)
+}
+
 // Stats returns statistics about the connection
 func (c *Conn) Stats() Stats {
 	return Stats{
@@ -81,23 +419,27 @@ func (c *Conn) Stats() Stats {
 	}
 }
 
-// Add sum ups all stats
-func (s Stats) Add(stats Stats) Stats {
-	return Stats{
-		Syscalls: s.Syscalls + stats.Syscalls,
+// BytesSent returns the number of bytes sent to the server
+// since connection is established including packet headers
+func (c *Conn) BytesSent() uint64 {
+	if c.netConn == nil {
+		return 0
 	}
+	return atomic.LoadUint64(&c.netConn.bytesWritten)
 }
 
-func setUTF8Charset(conn mysqlproto.Conn) error {
-	data := mysqlproto.ComQueryRequest([]byte("SET NAMES utf8"))
-	if _, err := conn.Write(data); err != nil {
-		return err
+// BytesReceived returns the number of bytes received from the server
+// since connection is established including packet headers
+func (c *Conn) BytesReceived() uint64 {
+	if c.netConn == nil {
+		return 0
 	}
+	return atomic.LoadUint64(&c.netConn.bytesRead)
+}
 
-	packet, err := conn.NextPacket()
-	if err != nil {
-		return err
+// Add sum ups all stats
+func (s Stats) Add(stats Stats) Stats {
+	return Stats{
+		Syscalls: s.Syscalls + stats.Syscalls,
 	}
-
-	return handleOK(packet.Payload, conn.CapabilityFlags)
 }
@@ -46,3 +46,29 @@ func TestConnClose(t *testing.T) {
 	assert.Nil(t, conn.Close())
 	assert.True(t, conn.closed)
 }
+
+func TestConnCloseSendsQuit(t *testing.T) {
+	conn, s := newSessionConn("8.0.22")
+	assert.Nil(t, conn.Close())
+	assert.Equal(t, s.written, []byte{0x01, 0x00, 0x00, 0x00, comQuit})
+	assert.True(t, s.closed)
+	assert.False(t, conn.valid)
+
+	s.written = nil
+	assert.Nil(t, conn.Close())
+	assert.Nil(t, s.written)
+}
+
+func TestConnCloseDoesntSendQuitWhenConnectionIsBusy(t *testing.T) {
+	conn, s := newSessionConn("8.0.22")
+	assert.Nil(t, conn.acquire())
+	assert.Nil(t, conn.Close())
+	assert.Nil(t, s.written)
+	assert.True(t, s.closed)
+
+	conn, s = newSessionConn("8.0.22")
+	conn.valid = false
+	assert.Nil(t, conn.Close())
+	assert.Nil(t, s.written)
+	assert.True(t, s.closed)
+}
@@ -0,0 +1,77 @@
+package mysqldriver
+
+import (
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
+)
+
+// clientName is reported by _client_name connection attribute
+const clientName = "mysqldriver-go"
+
+// connectAttrs returns connection attributes sent in the handshake
+// response: the default attributes describing the client process
+// and the custom ones which can override them. Server shows them
+// in performance_schema.session_connect_attrs.
+func connectAttrs(custom map[string]string) map[string]string {
+	attrs := map[string]string{
+		"_client_name":     clientName,
+		"_os":              runtime.GOOS,
+		"_platform":        runtime.GOARCH,
+		"_pid":             strconv.Itoa(os.Getpid()),
+		"_runtime_version": runtime.Version(),
+	}
+	if version := clientVersion(); version != "" {
+		attrs["_client_version"] = version
+	}
+	for key, value := range custom {
+		attrs[key] = value
+	}
+	return attrs
+}
+
+// clientVersion returns version of the driver module
+// built into the binary or empty string when it's unknown
+func clientVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	modules := append([]*debug.Module{&info.Main}, info.Deps...)
+	for _, module := range modules {
+		if module.Path == "github.com/pubnative/mysqldriver-go" && module.Version != "(devel)" {
+			return module.Version
+		}
+	}
+	return ""
+}
+
+// appendConnectAttrs appends encoded attributes
+// or their zero length when there are no attributes
+func appendConnectAttrs(payload, attrs []byte) []byte {
+	if len(attrs) == 0 {
+		return append(payload, 0)
+	}
+	return append(payload, attrs...)
+}
+
+// encodeConnectAttrs encodes attributes as length-encoded
+// key-value pairs prefixed by their total length. Keys are sorted,
+// so the encoding doesn't depend on the order of the map.
+// (see https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_connection_phase_packets_protocol_handshake_response.html)
+func encodeConnectAttrs(attrs map[string]string) []byte {
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []byte
+	for _, key := range keys {
+		pairs = appendLengthEncodedString(pairs, key)
+		pairs = appendLengthEncodedString(pairs, attrs[key])
+	}
+	return append(appendLengthEncodedInteger(nil, uint64(len(pairs))), pairs...)
+}
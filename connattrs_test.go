@@ -0,0 +1,93 @@
+package mysqldriver
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectAttrs(t *testing.T) {
+	attrs := connectAttrs(map[string]string{"program_name": "billing", "_os": "plan9"})
+	assert.Equal(t, attrs["_client_name"], "mysqldriver-go")
+	assert.Equal(t, attrs["_platform"], runtime.GOARCH)
+	assert.Equal(t, attrs["_pid"], strconv.Itoa(os.Getpid()))
+	assert.Equal(t, attrs["program_name"], "billing")
+	// custom attributes override the default ones
+	assert.Equal(t, attrs["_os"], "plan9")
+}
+
+func TestEncodeConnectAttrs(t *testing.T) {
+	encoded := encodeConnectAttrs(map[string]string{"b": "22", "a": "1"})
+	assert.Equal(t, encoded, []byte{0x09, 0x01, 'a', 0x01, '1', 0x01, 'b', 0x02, '2', '2'})
+	assert.Equal(t, encodeConnectAttrs(nil), []byte{0x00})
+}
+
+func TestConnectHandshakeSendsConnectAttrs(t *testing.T) {
+	attrs := encodeConnectAttrs(map[string]string{"program_name": "billing"})
+	conn, done := startAuthServer(t, func(s *authServer) {
+		_, response := s.read()
+		assert.True(t, bytes.HasSuffix(response, append([]byte("caching_sha2_password\x00"), attrs...)))
+		s.write(2, []byte{authMoreData, cachingSHA2FastAuthSuccess})
+		s.write(3, okPayload)
+	})
+	defer conn.Close()
+
+	_, err := connectHandshake(conn, capabilityFlags, charsetUTF8Code, attrs, "root", "secret", "test", nil, false, 0)
+	assert.NoError(t, err)
+	<-done
+}
+
+func TestConnectHandshakeWithoutConnectAttrsCapability(t *testing.T) {
+	payload := append([]byte(nil), handshakePayload...)
+	payload[26] &^= byte(CapabilityConnectAttrs >> 16) // upper capability flags
+	handshake, err := parseHandshake(payload)
+	assert.NoError(t, err)
+	assert.False(t, handshake.Capabilities.Has(CapabilityConnectAttrs))
+
+	client, server := net.Pipe()
+	defer client.Close()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer server.Close()
+		s := &authServer{t: t, conn: server}
+		s.write(0, payload)
+		_, response := s.read()
+		assert.False(t, bytes.Contains(response, []byte("program_name")))
+		assert.True(t, bytes.HasSuffix(response, []byte("caching_sha2_password\x00")))
+		s.write(2, []byte{authMoreData, cachingSHA2FastAuthSuccess})
+		s.write(3, okPayload)
+	}()
+
+	attrs := encodeConnectAttrs(map[string]string{"program_name": "billing"})
+	stream, err := connectHandshake(&netConn{Conn: client}, capabilityFlags, charsetUTF8Code, attrs, "root", "secret", "test", nil, false, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, stream.CapabilityFlags&mysqlproto.CLIENT_CONNECT_ATTRS, uint32(0))
+	<-done
+}
+
+func TestNewConnOptionsConnectAttrs(t *testing.T) {
+	conn, err := NewConnOptions(context.Background(), "root", "", "tcp", "127.0.0.1:3306", "test", time.Duration(0),
+		Options{ConnectAttrs: map[string]string{"program_name": "mysqldriver_test"}})
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	rows, err := conn.Query("SELECT ATTR_NAME, ATTR_VALUE FROM performance_schema.session_connect_attrs " +
+		"WHERE PROCESSLIST_ID = CONNECTION_ID()")
+	assert.NoError(t, err)
+	attrs := map[string]string{}
+	for rows.Next() {
+		attrs[rows.String()] = rows.String()
+	}
+	assert.NoError(t, rows.LastError())
+	assert.Equal(t, attrs["_client_name"], "mysqldriver-go")
+	assert.Equal(t, attrs["program_name"], "mysqldriver_test")
+}
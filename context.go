@@ -0,0 +1,157 @@
+package mysqldriver
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/pubnative/mysqlproto-go"
+)
+
+// killQueryTimeout limits establishing of the connection
+// used to send KILL QUERY (see Options.KillOnCancel)
+const killQueryTimeout = 5 * time.Second
+
+// contextWatch interrupts reading of the query
+// when the context is canceled
+type contextWatch struct {
+	ctx      context.Context
+	stop     chan struct{}
+	done     chan struct{}
+	canceled int32 // 1 when context is canceled during the query
+}
+
+// QueryContext performs the query the same way as Query does.
+// When ctx is canceled or its deadline is exceeded before
+// all rows are read, reading is aborted: Rows.Next returns false
+// and Rows.LastError returns ctx.Err(). The rest of the result set
+// isn't read, so connection becomes invalid and it's closed
+// when it's returned to the pool (see func (DB) PutConn).
+// With Options.KillOnCancel the query is also stopped on the server.
+//
+// Cancellation doesn't affect the connection after
+// the last row is read, Query has failed or rows are discarded.
+//
+// When MaxExecutionTime of the connection is set, the query is also
+// limited on the server (see Conn.MaxExecutionTime).
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+//	defer cancel()
+//	rows, _ := conn.QueryContext(ctx, "SELECT name FROM dogs")
+//	for rows.Next() {
+//		rows.String()
+//	}
+//	rows.LastError() // context.DeadlineExceeded when query is too slow
+func (c *Conn) QueryContext(ctx context.Context, sql string) (*Rows, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if c.MaxExecutionTime > 0 {
+		hinted, ok := c.executionTimeHint(sql)
+		if !ok {
+			return c.queryWithSessionTimeout(ctx, sql)
+		}
+		sql = hinted
+	}
+
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	c.watchContext(ctx)
+
+	return c.query(sql)
+}
+
+// ExecContext executes the statement the same way as Exec does.
+// When ctx is canceled or its deadline is exceeded before
+// the server responds, ExecContext returns ctx.Err() and
+// connection becomes invalid (see func (Conn) QueryContext).
+//
+// Server keeps executing the statement after cancellation
+// unless connection is established with Options.KillOnCancel.
+func (c *Conn) ExecContext(ctx context.Context, sql string) (mysqlproto.OKPacket, error) {
+	if err := ctx.Err(); err != nil {
+		return mysqlproto.OKPacket{}, err
+	}
+
+	if err := c.acquire(); err != nil {
+		return mysqlproto.OKPacket{}, err
+	}
+	c.watchContext(ctx)
+
+	return c.exec(sql)
+}
+
+// watchContext aborts reads and writes of the connection
+// as soon as ctx is done until the connection is released
+func (c *Conn) watchContext(ctx context.Context) {
+	if ctx.Done() == nil || c.netConn == nil {
+		return
+	}
+
+	w := &contextWatch{
+		ctx:  ctx,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	c.watch = w
+
+	go func() {
+		defer close(w.done)
+		select {
+		case <-ctx.Done():
+			atomic.StoreInt32(&w.canceled, 1)
+			c.netConn.interrupt()
+			if c.dial != nil {
+				go c.killQuery()
+			}
+		case <-w.stop:
+		}
+	}()
+}
+
+// killQuery stops the statement of the connection on the server
+// by sending KILL QUERY over another connection
+func (c *Conn) killQuery() {
+	id := c.handshake.ConnectionID
+	if id == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), killQueryTimeout)
+	defer cancel()
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	conn.Exec("KILL QUERY " + strconv.FormatUint(uint64(id), 10))
+}
+
+// stopWatch waits until context isn't watched anymore.
+// Connection becomes invalid if the context has been canceled
+// because the stream can be interrupted in the middle of the packet.
+func (c *Conn) stopWatch() {
+	w := c.watch
+	if w == nil {
+		return
+	}
+	c.watch = nil
+
+	close(w.stop)
+	<-w.done
+	if atomic.LoadInt32(&w.canceled) == 1 {
+		c.valid = false
+	}
+}
+
+// contextError returns error of the watched context
+// when it's canceled during the query
+func (c *Conn) contextError() error {
+	if c.watch != nil && atomic.LoadInt32(&c.watch.canceled) == 1 {
+		return c.watch.ctx.Err()
+	}
+	return nil
+}
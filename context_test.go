@@ -0,0 +1,154 @@
+package mysqldriver
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// newPipeConn returns connection to the server which reads
+// the query and responds with given payloads
+func newPipeConn(payloads ...[]byte) *Conn {
+	client, server := net.Pipe()
+	go func() {
+		buf := make([]byte, 1024)
+		server.Read(buf)
+		server.Write(framePackets(nil, payloads...))
+	}()
+
+	conn := &netConn{Conn: client}
+	stream := mysqlproto.NewStream(conn, 0)
+	return &Conn{
+		conn:    mysqlproto.Conn{Stream: stream, CapabilityFlags: capabilityFlags},
+		netConn: conn,
+		valid:   true,
+	}
+}
+
+func TestQueryContextCanceledWhileReadingRows(t *testing.T) {
+	// server never sends the end of the result set
+	conn := newPipeConn([]byte{0x01}, columnDefinition("id"), eofPacket, []byte{0x01, '1'})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rows, err := conn.QueryContext(ctx, "SELECT id FROM people")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Int(), 1)
+
+	time.AfterFunc(10*time.Millisecond, cancel)
+	assert.False(t, rows.Next())
+	assert.Equal(t, rows.LastError(), context.Canceled)
+	assert.False(t, conn.valid)
+	assert.Equal(t, atomic.LoadInt32(&conn.busy), int32(0))
+	assert.Nil(t, conn.watch)
+}
+
+func TestQueryContextDeadlineExceeded(t *testing.T) {
+	conn := newPipeConn()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	rows, err := conn.QueryContext(ctx, "SELECT SLEEP(1)")
+	assert.Nil(t, rows)
+	assert.Equal(t, err, context.DeadlineExceeded)
+	assert.False(t, conn.valid)
+	assert.Equal(t, atomic.LoadInt32(&conn.busy), int32(0))
+}
+
+func TestQueryContextCanceledAfterReadingAllRows(t *testing.T) {
+	conn := newPipeConn([]byte{0x01}, columnDefinition("id"), eofPacket, []byte{0x01, '1'}, eofPacket)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rows, err := conn.QueryContext(ctx, "SELECT id FROM people")
+	assert.NoError(t, err)
+	for rows.Next() {
+	}
+	cancel()
+
+	assert.NoError(t, rows.LastError())
+	assert.True(t, conn.valid)
+	assert.Nil(t, conn.watch)
+}
+
+func TestQueryContextAlreadyCanceled(t *testing.T) {
+	conn := newPipeConn()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := conn.QueryContext(ctx, "SELECT 1")
+	assert.Equal(t, err, context.Canceled)
+	assert.True(t, conn.valid)
+	assert.Equal(t, atomic.LoadInt32(&conn.busy), int32(0))
+}
+
+func TestExecContextDeadlineExceeded(t *testing.T) {
+	conn := newPipeConn()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := conn.ExecContext(ctx, "DO SLEEP(1)")
+	assert.Equal(t, err, context.DeadlineExceeded)
+	assert.False(t, conn.valid)
+	assert.Equal(t, atomic.LoadInt32(&conn.busy), int32(0))
+	assert.Nil(t, conn.watch)
+}
+
+func TestPingContextDeadlineExceeded(t *testing.T) {
+	conn := newPipeConn()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, conn.PingContext(ctx), context.DeadlineExceeded)
+	assert.False(t, conn.valid)
+	assert.Equal(t, atomic.LoadInt32(&conn.busy), int32(0))
+	assert.Nil(t, conn.watch)
+}
+
+func TestPingContext(t *testing.T) {
+	conn := newPipeConn([]byte{mysqlproto.OK_PACKET, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00})
+
+	assert.NoError(t, conn.PingContext(context.Background()))
+	assert.True(t, conn.valid)
+}
+
+func TestExecContext(t *testing.T) {
+	conn := newPipeConn([]byte{mysqlproto.OK_PACKET, 0x01, 0x00, 0x02, 0x00, 0x00, 0x00})
+
+	pkt, err := conn.ExecContext(context.Background(), "DELETE FROM people")
+	assert.NoError(t, err)
+	assert.Equal(t, pkt, conn.lastOK)
+	assert.True(t, conn.valid)
+}
+
+func TestExecContextKillOnCancel(t *testing.T) {
+	conn, err := NewConnOptions(context.Background(), "root", "", "tcp", "127.0.0.1:3306", "test", time.Duration(0), Options{KillOnCancel: true})
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = conn.ExecContext(ctx, "DO SLEEP(10)")
+	assert.Equal(t, err, context.DeadlineExceeded)
+	assert.False(t, conn.valid)
+
+	observer, err := NewConn("root", "", "tcp", "127.0.0.1:3306", "test", time.Duration(0))
+	assert.NoError(t, err)
+	defer observer.Close()
+
+	sql := "SELECT COUNT(*) FROM information_schema.PROCESSLIST WHERE INFO = 'DO SLEEP(10)'"
+	running := 1
+	for deadline := time.Now().Add(2 * time.Second); running > 0 && time.Now().Before(deadline); {
+		time.Sleep(50 * time.Millisecond)
+		rows, err := observer.Query(sql)
+		assert.NoError(t, err)
+		for rows.Next() {
+			running = rows.Int()
+		}
+	}
+	assert.Equal(t, running, 0)
+}
@@ -0,0 +1,35 @@
+package mysqldriver
+
+import "encoding/binary"
+
+// cursorTypeReadOnly is the flag of COM_STMT_EXECUTE
+// which opens read-only cursor on the server
+const cursorTypeReadOnly byte = 0x01
+
+// cursor of the server which holds the result set of the statement.
+// Rows are fetched from the cursor in batches by COM_STMT_FETCH.
+type cursor struct {
+	stmt      uint32 // statement ID
+	fetchSize int
+	fetch     bool // the batch is read, the next one isn't requested yet
+}
+
+// fetchRows requests the next batch of rows of the cursor
+// (see https://dev.mysql.com/doc/internals/en/com-stmt-fetch.html)
+func (r *Rows) fetchRows() error {
+	// statement_id(4), num_rows(4)
+	payload := make([]byte, 8)
+	binary.LittleEndian.PutUint32(payload, r.cursor.stmt)
+	binary.LittleEndian.PutUint32(payload[4:], uint32(r.cursor.fetchSize))
+	if _, err := r.conn.conn.Write(commandPacket(comStmtFetch, payload)); err != nil {
+		return err
+	}
+	r.cursor.fetch = false
+	return nil
+}
+
+// cursorExhausted reports whether all rows of the cursor are sent
+// according to the status of EOF_PACKET which ends the batch
+func (c *Conn) cursorExhausted() bool {
+	return c.status&serverStatusCursorExists == 0 || c.status&serverStatusLastRowSent != 0
+}
@@ -0,0 +1,114 @@
+package mysqldriver
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// cursorEOFPacket returns EOF_PACKET with the status flags
+func cursorEOFPacket(status uint16) []byte {
+	return []byte{mysqlproto.EOF_PACKET, 0x00, 0x00, byte(status), byte(status >> 8)}
+}
+
+func fetchPacket(stmt uint32, rows int) []byte {
+	return commandPacket(comStmtFetch, []byte{byte(stmt), 0x00, 0x00, 0x00, byte(rows), 0x00, 0x00, 0x00})
+}
+
+func TestStmtQueryFetchesRowsFromCursor(t *testing.T) {
+	conn, s := newSessionConn("8.0.22",
+		[]byte{0x01}, columnDefinition("name"), cursorEOFPacket(serverStatusCursorExists),
+		[]byte{0x00, 0x00, 0x03, 'b', 'o', 'b'},
+		[]byte{0x00, 0x00, 0x03, 'b', 'e', 'n'},
+		cursorEOFPacket(serverStatusCursorExists),
+		[]byte{0x00, 0x00, 0x03, 'm', 'a', 'x'},
+		cursorEOFPacket(serverStatusLastRowSent),
+	)
+	stmt := &Stmt{conn: conn, id: 7, FetchSize: 2}
+
+	rows, err := stmt.Query()
+	assert.NoError(t, err)
+	assert.Equal(t, s.written[9], cursorTypeReadOnly)
+	// rows aren't requested before Next
+	assert.False(t, bytes.Contains(s.written, fetchPacket(7, 2)))
+
+	var names []string
+	for rows.Next() {
+		names = append(names, rows.String())
+	}
+	assert.NoError(t, rows.LastError())
+	assert.Equal(t, names, []string{"bob", "ben", "max"})
+	assert.Equal(t, bytes.Count(s.written, fetchPacket(7, 2)), 2)
+	assert.Equal(t, conn.acquire(), nil)
+}
+
+func TestStmtQueryWithoutCursor(t *testing.T) {
+	// server sends rows when statement can't open the cursor
+	conn, s := newSessionConn("8.0.22",
+		[]byte{0x01}, columnDefinition("name"), eofPacket,
+		[]byte{0x00, 0x00, 0x03, 'b', 'o', 'b'},
+		eofPacket,
+	)
+	stmt := &Stmt{conn: conn, id: 7, FetchSize: 2}
+
+	rows, err := stmt.Query()
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.String(), "bob")
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.LastError())
+	assert.False(t, bytes.Contains(s.written, fetchPacket(7, 2)))
+}
+
+func TestStmtExecDoesntFetchRowsFromCursor(t *testing.T) {
+	conn, s := newSessionConn("8.0.22",
+		[]byte{0x01}, columnDefinition("name"), cursorEOFPacket(serverStatusCursorExists),
+	)
+	stmt := &Stmt{conn: conn, id: 7, FetchSize: 2}
+
+	_, err := stmt.Exec()
+	assert.NoError(t, err)
+	assert.False(t, bytes.Contains(s.written, fetchPacket(7, 2)))
+	assert.Equal(t, conn.acquire(), nil)
+}
+
+func TestStmtQueryWithFetchSize(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		for _, name := range []string{"bob", "ben", "max"} {
+			_, err := conn.Exec("INSERT INTO people(firstname) VALUES ('" + name + "')")
+			assert.NoError(t, err)
+		}
+
+		stmt, err := conn.Prepare("SELECT firstname FROM people ORDER BY id")
+		assert.NoError(t, err)
+		defer stmt.Close()
+		stmt.FetchSize = 2
+
+		rows, err := stmt.Query()
+		assert.NoError(t, err)
+		var names []string
+		for rows.Next() {
+			names = append(names, rows.String())
+		}
+		assert.NoError(t, rows.LastError())
+		assert.Equal(t, names, []string{"bob", "ben", "max"})
+
+		// statement is executed again after the cursor is closed early
+		rows, err = stmt.Query()
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.String(), "bob")
+		rows.discard()
+		assert.NoError(t, rows.LastError())
+
+		rows, err = stmt.Query()
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.String(), "bob")
+		for rows.Next() {
+		}
+		assert.NoError(t, rows.LastError())
+	})
+}
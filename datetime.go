@@ -0,0 +1,85 @@
+package mysqldriver
+
+import (
+	"fmt"
+	"time"
+)
+
+// pow10 holds multipliers used to convert fractional part
+// of the seconds with 1-6 digits into nanoseconds
+var pow10 = [...]int{1e9, 1e8, 1e7, 1e6, 1e5, 1e4, 1e3}
+
+// parseDateTime parses DATE, DATETIME and TIMESTAMP values
+// in the formats of the text protocol:
+//
+//	2006-01-02
+//	2006-01-02 15:04:05
+//	2006-01-02 15:04:05.999999 (from 1 up to 6 digits of fraction)
+//
+// Zero dates like "0000-00-00" or "0000-00-00 00:00:00"
+// are represented as zero time.Time
+func parseDateTime(b []byte, loc *time.Location) (time.Time, error) {
+	if len(b) != 10 && len(b) != 19 && (len(b) < 21 || len(b) > 26) {
+		return time.Time{}, dateTimeError(b)
+	}
+
+	year, ok1 := parseDigits(b[0:4])
+	month, ok2 := parseDigits(b[5:7])
+	day, ok3 := parseDigits(b[8:10])
+	if !ok1 || !ok2 || !ok3 || b[4] != '-' || b[7] != '-' {
+		return time.Time{}, dateTimeError(b)
+	}
+
+	var hour, min, sec, nsec int
+	if len(b) > 10 {
+		var ok4, ok5, ok6 bool
+		hour, ok4 = parseDigits(b[11:13])
+		min, ok5 = parseDigits(b[14:16])
+		sec, ok6 = parseDigits(b[17:19])
+		if !ok4 || !ok5 || !ok6 || b[10] != ' ' || b[13] != ':' || b[16] != ':' {
+			return time.Time{}, dateTimeError(b)
+		}
+	}
+
+	if len(b) > 19 {
+		frac, ok := parseDigits(b[20:])
+		if !ok || b[19] != '.' {
+			return time.Time{}, dateTimeError(b)
+		}
+		nsec = frac * pow10[len(b)-20]
+	}
+
+	if year == 0 && month == 0 && day == 0 {
+		return time.Time{}, nil
+	}
+
+	if month < 1 || month > 12 || day < 1 || day > 31 || hour > 23 || min > 59 || sec > 59 {
+		return time.Time{}, dateTimeError(b)
+	}
+
+	return time.Date(year, time.Month(month), day, hour, min, sec, nsec, loc), nil
+}
+
+// location returns the location used to parse DATETIME values
+func (c *Conn) location() *time.Location {
+	if c == nil || c.Location == nil {
+		return time.UTC
+	}
+	return c.Location
+}
+
+func parseDigits(b []byte) (int, bool) {
+	n := 0
+	for _, ch := range b {
+		ch -= '0'
+		if ch > 9 {
+			return 0, false
+		}
+		n = n*10 + int(ch)
+	}
+	return n, true
+}
+
+func dateTimeError(b []byte) error {
+	return fmt.Errorf("mysqldriver: can't parse %q as DATETIME", b)
+}
@@ -0,0 +1,89 @@
+package mysqldriver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDateTimeDate(t *testing.T) {
+	tm, err := parseDateTime([]byte("2017-03-04"), time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, tm, time.Date(2017, 3, 4, 0, 0, 0, 0, time.UTC))
+}
+
+func TestParseDateTimeWithoutFraction(t *testing.T) {
+	tm, err := parseDateTime([]byte("2017-03-04 05:06:07"), time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, tm, time.Date(2017, 3, 4, 5, 6, 7, 0, time.UTC))
+}
+
+func TestParseDateTimeVariableFraction(t *testing.T) {
+	fractions := map[string]int{
+		"2017-03-04 05:06:07.1":      100000000,
+		"2017-03-04 05:06:07.12":     120000000,
+		"2017-03-04 05:06:07.123":    123000000,
+		"2017-03-04 05:06:07.1234":   123400000,
+		"2017-03-04 05:06:07.12345":  123450000,
+		"2017-03-04 05:06:07.123456": 123456000,
+		"2017-03-04 05:06:07.000001": 1000,
+	}
+	for value, nsec := range fractions {
+		tm, err := parseDateTime([]byte(value), time.UTC)
+		assert.NoError(t, err)
+		assert.Equal(t, tm, time.Date(2017, 3, 4, 5, 6, 7, nsec, time.UTC), value)
+	}
+}
+
+func TestParseDateTimeZeroDate(t *testing.T) {
+	for _, value := range []string{"0000-00-00", "0000-00-00 00:00:00", "0000-00-00 00:00:00.000000"} {
+		tm, err := parseDateTime([]byte(value), time.UTC)
+		assert.NoError(t, err)
+		assert.True(t, tm.IsZero(), value)
+	}
+}
+
+func TestParseDateTimeInvalid(t *testing.T) {
+	values := []string{
+		"",
+		"2017-03",
+		"2017/03/04",
+		"2017-03-04T05:06:07",
+		"2017-03-04 05:06:07.",
+		"2017-03-04 05:06:07.1234567",
+		"2017-03-04 05:06:07,123",
+		"2017-13-04 05:06:07",
+		"2017-03-04 25:06:07",
+		"abcd-03-04",
+	}
+	for _, value := range values {
+		_, err := parseDateTime([]byte(value), time.UTC)
+		assert.Error(t, err, value)
+	}
+	_, err := parseDateTime([]byte("2017-03-04 05:06:07.1234567"), time.UTC)
+	assert.EqualError(t, err, `mysqldriver: can't parse "2017-03-04 05:06:07.1234567" as DATETIME`)
+}
+
+func TestRowsTimeUsesConnLocation(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01},
+		columnDefinition("created_at"),
+		eofPacket,
+		[]byte{0x13, '2', '0', '1', '7', '-', '0', '3', '-', '0', '4', ' ', '0', '5', ':', '0', '6', ':', '0', '7'},
+		[]byte{0x13, '2', '0', '1', '7', '-', '0', '3', '-', '0', '4', ' ', '0', '5', ':', '0', '6', ':', '0', '7'},
+		eofPacket,
+	)
+	loc := time.FixedZone("UTC+3", 3*60*60)
+
+	rows, err := conn.Query("SELECT created_at FROM dogs")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Time(), time.Date(2017, 3, 4, 5, 6, 7, 0, time.UTC))
+
+	conn.Location = loc
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Row().Time("created_at"), time.Date(2017, 3, 4, 5, 6, 7, 0, loc))
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.LastError())
+}
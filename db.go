@@ -1,16 +1,71 @@
 package mysqldriver
 
 import (
+	"context"
 	"errors"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/pubnative/mysqlproto-go"
 )
 
 var ErrClosedDB = errors.New("mysqldriver: can't get connection from the closed DB")
 
 // DB manages pool of connection
 type DB struct {
-	OnDial func(conn *Conn) error // called when new connection is established
+	// OnDial is called every time when new connection is established
+	// including the connections which replace the broken ones
+	// (see ReconnectingConn). It's the place to initialize the session,
+	// e.g. set session variables. When OnDial returns an error,
+	// connection is closed and won't be used.
+	OnDial func(conn *Conn) error
+
+	// Options are used to establish new connections
+	Options Options
+
+	// PingAfterIdle makes GetConn ping connections which have been
+	// idle in the pool for longer. Server closes idle connections after
+	// wait_timeout, so stale connections are closed and replaced
+	// with the fresh ones instead of failing on the first query.
+	// Zero value disables the check.
+	PingAfterIdle time.Duration
+
+	// MaxOpen limits the number of connections established by the pool
+	// including connections which are in use. When the limit is reached,
+	// GetConn waits until one of them is returned by PutConn.
+	// Connections must be returned by PutConn even when they're
+	// closed or broken, otherwise they're still counted.
+	// It must be set before the first GetConn. Zero value means no limit.
+	MaxOpen int
+
+	// IdleTimeout starts background reaper which pings connections
+	// having been idle in the pool for longer and closes the ones which
+	// don't respond. Idle connections are checked every IdleTimeout/2,
+	// so it should be set below wait_timeout of the server to keep
+	// the idle connections alive. It must be set before the first
+	// PutConn. Zero value disables the reaper.
+	IdleTimeout time.Duration
+
+	// Reconnect configures how ReconnectingConn re-establishes
+	// the broken connections. Zero value reconnects once
+	// and retries only Query.
+	Reconnect ReconnectPolicy
+
+	// ResetOnPut makes PutConn reset the session state of connections
+	// (see func (Conn) Reset), so user variables, temporary tables
+	// and session variables don't leak to the next user of the connection.
+	// OnDial is called again after the reset to initialize the session.
+	// Connections which can't be reset, e.g. because the server
+	// is too old, are closed instead of returning to the pool.
+	// It costs a round trip to the server on every PutConn.
+	ResetOnPut bool
+
+	slots      chan struct{} // semaphore of MaxOpen connections
+	slotsOnce  sync.Once
+	reaperOnce sync.Once
+	done       chan struct{} // closed by Close to stop the reaper
 
 	conns    chan *Conn
 	username string
@@ -24,7 +79,9 @@ type DB struct {
 // NewDB initializes pool of connections but doesn't
 // establishes connection to DB.
 //
-// Pool size is fixed and can't be resized later.
+// Pool size is the maximum number of idle connections,
+// it's fixed and can't be resized later (see DB.MaxOpen
+// to limit the number of connections in use).
 // DataSource parameter has the following format:
 // [username[:password]@][protocol[(address)]]/dbname
 func NewDB(dataSource string, pool int, readTimeout time.Duration) *DB {
@@ -32,6 +89,7 @@ func NewDB(dataSource string, pool int, readTimeout time.Duration) *DB {
 	conns := make(chan *Conn, pool)
 	return &DB{
 		conns:    conns,
+		done:     make(chan struct{}),
 		username: usr,
 		password: pass,
 		protocol: proto,
@@ -43,56 +101,208 @@ func NewDB(dataSource string, pool int, readTimeout time.Duration) *DB {
 
 // GetConn gets connection from the pool if there is one or
 // establishes a new one.This method always returns the connection
-// regardless the pool size. When DB is closed, this method
-// returns ErrClosedDB error.
+// regardless the pool size unless MaxOpen is set. When DB is closed,
+// this method returns ErrClosedDB error.
 func (db *DB) GetConn() (*Conn, error) {
-	select {
-	case conn, more := <-db.conns:
-		if !more {
-			return nil, ErrClosedDB
+	for {
+		select {
+		case conn, more := <-db.conns:
+			if !more {
+				return nil, ErrClosedDB
+			}
+			if db.stale(conn) {
+				db.discard(conn)
+				continue
+			}
+			return conn, nil
+		default:
 		}
-		return conn, nil
-	default:
-		return db.dial()
+
+		slots := db.openSlots()
+		if slots == nil {
+			return db.dial()
+		}
+
+		// wait for the idle connection when MaxOpen is reached
+		select {
+		case conn, more := <-db.conns:
+			if !more {
+				return nil, ErrClosedDB
+			}
+			if db.stale(conn) {
+				db.discard(conn)
+				continue
+			}
+			return conn, nil
+		case slots <- struct{}{}:
+			conn, err := db.dial()
+			if err != nil {
+				<-slots
+			}
+			return conn, err
+		}
+	}
+}
+
+// stale reports whether connection is broken or it has been idle
+// for longer than PingAfterIdle and doesn't respond to ping
+func (db *DB) stale(conn *Conn) bool {
+	if !conn.valid || conn.closed {
+		return true
+	}
+	if db.PingAfterIdle <= 0 || time.Since(conn.idleSince) < db.PingAfterIdle {
+		return false
 	}
+	return conn.Ping() != nil
+}
+
+// openSlots returns semaphore of MaxOpen connections,
+// it's nil when the number of connections isn't limited
+func (db *DB) openSlots() chan struct{} {
+	db.slotsOnce.Do(func() {
+		if db.MaxOpen > 0 {
+			db.slots = make(chan struct{}, db.MaxOpen)
+		}
+	})
+	return db.slots
+}
+
+// discard closes connection which won't be reused
+// and frees its slot of MaxOpen connections
+func (db *DB) discard(conn *Conn) error {
+	if slots := db.openSlots(); slots != nil {
+		select {
+		case <-slots:
+		default:
+		}
+	}
+	return conn.Close()
+}
+
+// Exec gets connection from the pool, executes the statement
+// (see func (Conn) Exec) and returns connection back to the pool
+//
+//	okPacket, err := db.Exec("DELETE FROM dogs WHERE id = 1")
+func (db *DB) Exec(sql string) (mysqlproto.OKPacket, error) {
+	conn, err := db.GetConn()
+	if err != nil {
+		return mysqlproto.OKPacket{}, err
+	}
+	defer db.PutConn(conn)
+	return conn.Exec(sql)
 }
 
 // PutConn returns connection to the pool. When pool is reached,
 // connection is closed and won't be further reused.
 // If connection is already closed, PutConn will discard it
 // so it's safe to return closed connection to the pool.
-func (db *DB) PutConn(conn *Conn) (err error) {
-	defer func() {
-		if e := recover(); e != nil {
-			err = conn.Close()
-			return
-		}
-	}()
-
-	if !conn.valid {
-		// broken connection shouldn't be in a pool
-		return conn.Close()
+func (db *DB) PutConn(conn *Conn) error {
+	if !conn.valid || atomic.LoadInt32(&conn.busy) == 1 || conn.userChanged || conn.tx != nil ||
+		atomic.LoadInt32(&conn.interrupted) == 1 {
+		// broken connection, connection with unread result set,
+		// of another user, with unfinished transaction
+		// or interrupted one shouldn't be in a pool
+		return db.discard(conn)
 	}
 
 	if conn.closed {
+		db.discard(conn)
 		return nil
 	}
 
+	if db.ResetOnPut {
+		if err := db.reset(conn); err != nil {
+			return db.discard(conn)
+		}
+	}
+
 	conn.conn.ResetStats()
+	conn.idleSince = time.Now()
+
+	if db.IdleTimeout > 0 {
+		db.reaperOnce.Do(func() { go db.reaper() })
+	}
+	return db.idle(conn)
+}
+
+// reset restores the session state of the connection
+// and initializes the session again by OnDial
+func (db *DB) reset(conn *Conn) error {
+	if err := conn.Reset(); err != nil {
+		return err
+	}
+	if db.OnDial != nil {
+		return db.OnDial(conn)
+	}
+	return nil
+}
+
+// idle puts connection into the pool. When pool is full
+// or DB is closed, connection is closed.
+func (db *DB) idle(conn *Conn) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = db.discard(conn)
+			return
+		}
+	}()
 
 	select {
 	case db.conns <- conn:
 	default:
-		err = conn.Close()
+		err = db.discard(conn)
 	}
 
 	return
 }
 
+// reaper checks idle connections every IdleTimeout/2 until DB is closed
+func (db *DB) reaper() {
+	ticker := time.NewTicker(db.IdleTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			db.reap()
+		case <-db.done:
+			return
+		}
+	}
+}
+
+// reap pings connections which have been idle for longer
+// than IdleTimeout and discards the ones which don't respond
+func (db *DB) reap() {
+	for i := len(db.conns); i > 0; i-- {
+		var conn *Conn
+		select {
+		case c, more := <-db.conns:
+			if !more {
+				return
+			}
+			conn = c
+		default:
+			return
+		}
+
+		if time.Since(conn.idleSince) >= db.IdleTimeout {
+			if err := conn.Ping(); err != nil {
+				db.discard(conn)
+				continue
+			}
+			conn.idleSince = time.Now()
+		}
+		db.idle(conn)
+	}
+}
+
 // Close closes all connections in a pool and
 // doesn't allow to establish new ones to DB any more.
 // Returns slice of errors if any occurred.
 func (db *DB) Close() []error {
+	if db.done != nil {
+		close(db.done)
+	}
 	close(db.conns)
 	var errors []error
 	for {
@@ -109,12 +319,16 @@ func (db *DB) Close() []error {
 }
 
 func (db *DB) dial() (*Conn, error) {
-	conn, err := NewConn(db.username, db.password, db.protocol, db.address, db.database, db.readTimeout)
+	conn, err := NewConnOptions(context.Background(), db.username, db.password,
+		db.protocol, db.address, db.database, db.readTimeout, db.Options)
 	if err != nil {
 		return conn, err
 	}
 	if db.OnDial != nil {
-		err = db.OnDial(conn)
+		if err = db.OnDial(conn); err != nil {
+			conn.valid = false
+			conn.Close()
+		}
 	}
 	return conn, err
 }
@@ -31,6 +31,47 @@ func TestDBGetConnReturnsConnectionFromThePool(t *testing.T) {
 	assert.Len(t, db.conns, 0)
 }
 
+func TestDBGetConnCallsOnDial(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:3306)/test", 2, time.Duration(0))
+	var calls int
+	db.OnDial = func(conn *Conn) error {
+		calls++
+		_, err := conn.Exec("SET @initialized = 1")
+		return err
+	}
+
+	conn, err := db.GetConn()
+	assert.NoError(t, err)
+	assert.Equal(t, calls, 1)
+
+	rows, err := conn.Query("SELECT @initialized")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Int(), 1)
+	assert.False(t, rows.Next())
+
+	// connection from the pool isn't initialized again
+	assert.Nil(t, db.PutConn(conn))
+	_, err = db.GetConn()
+	assert.NoError(t, err)
+	assert.Equal(t, calls, 1)
+}
+
+func TestDBGetConnClosesConnectionWhenOnDialFails(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:3306)/test", 2, time.Duration(0))
+	db.OnDial = func(conn *Conn) error {
+		_, err := conn.Exec("SET @@unknown_variable = 1")
+		return err
+	}
+
+	conn, err := db.GetConn()
+	assert.NotNil(t, err)
+	assert.False(t, conn.valid)
+	assert.True(t, conn.closed)
+	assert.Nil(t, db.PutConn(conn))
+	assert.Len(t, db.conns, 0)
+}
+
 func TestDBGetConnReturnsErrorWhenDBIsClosed(t *testing.T) {
 	db := NewDB("root@tcp(127.0.0.1:3306)/test", 2, time.Duration(0))
 	errors := db.Close()
@@ -39,6 +80,37 @@ func TestDBGetConnReturnsErrorWhenDBIsClosed(t *testing.T) {
 	assert.Equal(t, err, ErrClosedDB)
 }
 
+func TestDBGetConnReplacesStaleConnection(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:3306)/test", 2, time.Duration(0))
+	db.PingAfterIdle = time.Minute
+
+	// server has closed the connection
+	s := &stream{}
+	stale := &Conn{conn: mysqlproto.Conn{mysqlproto.NewStream(s, time.Duration(0)), 0}, valid: true, idleSince: time.Now().Add(-time.Hour)}
+	db.conns <- stale
+
+	conn, err := db.GetConn()
+	assert.NoError(t, err)
+	assert.True(t, s.closed)
+	assert.True(t, conn != stale)
+	assert.True(t, conn.valid)
+	assert.Len(t, db.conns, 0)
+}
+
+func TestDBGetConnDoesNotPingRecentlyUsedConnection(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:3306)/test", 2, time.Duration(0))
+	db.PingAfterIdle = time.Minute
+
+	s := &stream{}
+	recent := &Conn{conn: mysqlproto.Conn{mysqlproto.NewStream(s, time.Duration(0)), 0}, valid: true, idleSince: time.Now()}
+	db.conns <- recent
+
+	conn, err := db.GetConn()
+	assert.NoError(t, err)
+	assert.True(t, conn == recent)
+	assert.False(t, s.closed)
+}
+
 func TestDBPutConnAddsConnectionToThePool(t *testing.T) {
 	db := NewDB("root@tcp(127.0.0.1:3306)/test", 2, time.Duration(0))
 	assert.Len(t, db.conns, 0)
@@ -67,7 +139,7 @@ func TestDBPutConnClosesConnectionWhenItIsInvalid(t *testing.T) {
 	assert.Nil(t, errors)
 
 	s := &stream{}
-	conn := &Conn{mysqlproto.Conn{mysqlproto.NewStream(s, time.Duration(0)), 0}, false, false}
+	conn := &Conn{conn: mysqlproto.Conn{mysqlproto.NewStream(s, time.Duration(0)), 0}, valid: false, closed: false}
 	db.PutConn(conn)
 	assert.True(t, s.closed)
 	assert.Len(t, db.conns, 0)
@@ -89,7 +161,49 @@ func TestDBPutConnClosesConnectionWhenDBIsClosed(t *testing.T) {
 	assert.Nil(t, errors)
 
 	s := &stream{}
-	conn := &Conn{mysqlproto.Conn{mysqlproto.NewStream(s, time.Duration(0)), 0}, true, false}
+	conn := &Conn{conn: mysqlproto.Conn{mysqlproto.NewStream(s, time.Duration(0)), 0}, valid: true, closed: false}
+	db.PutConn(conn)
+	assert.True(t, s.closed)
+	assert.Len(t, db.conns, 0)
+}
+
+func TestDBPutConnClosesConnectionWhenItIsBusy(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:3306)/test", 2, time.Duration(0))
+
+	s := &stream{}
+	conn := &Conn{conn: mysqlproto.Conn{mysqlproto.NewStream(s, time.Duration(0)), 0}, valid: true, busy: 1}
+	db.PutConn(conn)
+	assert.True(t, s.closed)
+	assert.Len(t, db.conns, 0)
+}
+
+func TestDBPutConnClosesConnectionWithChangedUser(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:3306)/test", 2, time.Duration(0))
+	db.ResetOnPut = true
+
+	s := &stream{}
+	conn := &Conn{conn: mysqlproto.Conn{mysqlproto.NewStream(s, time.Duration(0)), 0}, valid: true, userChanged: true}
+	db.PutConn(conn)
+	assert.True(t, s.closed)
+	assert.Len(t, db.conns, 0)
+}
+
+func TestDBPutConnClosesConnectionWithUnfinishedTransaction(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:3306)/test", 2, time.Duration(0))
+
+	s := &stream{}
+	conn := &Conn{conn: mysqlproto.Conn{mysqlproto.NewStream(s, time.Duration(0)), 0}, valid: true}
+	conn.tx = &Tx{conn: conn}
+	db.PutConn(conn)
+	assert.True(t, s.closed)
+	assert.Len(t, db.conns, 0)
+}
+
+func TestDBPutConnClosesInterruptedConnection(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:3306)/test", 2, time.Duration(0))
+
+	s := &stream{}
+	conn := &Conn{conn: mysqlproto.Conn{mysqlproto.NewStream(s, time.Duration(0)), 0}, valid: true, interrupted: 1}
 	db.PutConn(conn)
 	assert.True(t, s.closed)
 	assert.Len(t, db.conns, 0)
@@ -98,10 +212,10 @@ func TestDBPutConnClosesConnectionWhenDBIsClosed(t *testing.T) {
 func TestDBCloseClosesAllConnections(t *testing.T) {
 	db := NewDB("root@tcp(127.0.0.1:3306)/test", 2, time.Duration(0))
 	s1 := &stream{}
-	conn1 := &Conn{mysqlproto.Conn{mysqlproto.NewStream(s1, time.Duration(0)), 0}, true, false}
+	conn1 := &Conn{conn: mysqlproto.Conn{mysqlproto.NewStream(s1, time.Duration(0)), 0}, valid: true, closed: false}
 	db.PutConn(conn1)
 	s2 := &stream{}
-	conn2 := &Conn{mysqlproto.Conn{mysqlproto.NewStream(s2, time.Duration(0)), 0}, true, false}
+	conn2 := &Conn{conn: mysqlproto.Conn{mysqlproto.NewStream(s2, time.Duration(0)), 0}, valid: true, closed: false}
 	db.PutConn(conn2)
 
 	assert.Len(t, db.conns, 2)
@@ -115,6 +229,82 @@ func TestDBCloseClosesAllConnections(t *testing.T) {
 	assert.False(t, more)
 }
 
+func TestDBGetConnWaitsWhenMaxOpenIsReached(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:3306)/test", 2, time.Duration(0))
+	db.MaxOpen = 1
+	db.openSlots() <- struct{}{} // connection in use
+
+	got := make(chan *Conn)
+	go func() {
+		conn, err := db.GetConn()
+		assert.NoError(t, err)
+		got <- conn
+	}()
+
+	select {
+	case <-got:
+		t.Fatal("GetConn must wait for the connection")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	s := &stream{}
+	conn := &Conn{conn: mysqlproto.Conn{mysqlproto.NewStream(s, time.Duration(0)), 0}, valid: true}
+	assert.Nil(t, db.PutConn(conn))
+	assert.True(t, <-got == conn)
+	assert.Len(t, db.slots, 1)
+}
+
+func TestDBGetConnWaitingForMaxOpenReturnsErrorWhenDBIsClosed(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:3306)/test", 2, time.Duration(0))
+	db.MaxOpen = 1
+	db.openSlots() <- struct{}{}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		db.Close()
+	}()
+	_, err := db.GetConn()
+	assert.Equal(t, err, ErrClosedDB)
+}
+
+func TestDBPutConnFreesSlotOfDiscardedConnection(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:3306)/test", 2, time.Duration(0))
+	db.MaxOpen = 1
+	db.openSlots() <- struct{}{}
+
+	s := &stream{}
+	conn := &Conn{conn: mysqlproto.Conn{mysqlproto.NewStream(s, time.Duration(0)), 0}, valid: false}
+	db.PutConn(conn)
+	assert.True(t, s.closed)
+	assert.Len(t, db.slots, 0)
+}
+
+func TestDBReapDiscardsStaleIdleConnection(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:3306)/test", 2, time.Duration(0))
+	db.IdleTimeout = time.Minute
+
+	// server has closed the connection
+	s1 := &stream{}
+	stale := &Conn{conn: mysqlproto.Conn{mysqlproto.NewStream(s1, time.Duration(0)), 0}, valid: true, idleSince: time.Now().Add(-time.Hour)}
+	db.conns <- stale
+	s2 := &stream{}
+	recent := &Conn{conn: mysqlproto.Conn{mysqlproto.NewStream(s2, time.Duration(0)), 0}, valid: true, idleSince: time.Now()}
+	db.conns <- recent
+
+	db.reap()
+	assert.True(t, s1.closed)
+	assert.False(t, s2.closed)
+	assert.Len(t, db.conns, 1)
+	assert.True(t, <-db.conns == recent)
+}
+
+func TestDBExecReturnsConnectionToThePool(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:3306)/test", 2, time.Duration(0))
+	_, err := db.Exec("DO 1")
+	assert.NoError(t, err)
+	assert.Len(t, db.conns, 1)
+}
+
 func TestParseDataSourceFull(t *testing.T) {
 	source := "root:123@tcp(127.0.0.1:3306)/test"
 	usr, pass, proto, addr, dbname := parseDataSource(source)
@@ -163,3 +353,29 @@ func (m MockAddr) String() string { return "" }
 func ExampleNewDB() {
 	NewDB("root@tcp(127.0.0.1:3306)/test", 10, time.Duration(0))
 }
+
+func TestDBPutConnResetsSession(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:3306)/test", 1, time.Duration(0))
+	db.ResetOnPut = true
+	var initialized int
+	db.OnDial = func(conn *Conn) error {
+		initialized++
+		return nil
+	}
+
+	conn, s := newSessionConn("8.0.22", okPayload)
+	assert.NoError(t, db.PutConn(conn))
+	assert.Equal(t, s.written, []byte{0x01, 0x00, 0x00, 0x00, comResetConnection})
+	assert.Equal(t, initialized, 1)
+	assert.Len(t, db.conns, 1)
+}
+
+func TestDBPutConnDiscardsConnectionWhichCantBeReset(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:3306)/test", 1, time.Duration(0))
+	db.ResetOnPut = true
+
+	conn, _ := newSessionConn("5.6.51")
+	db.PutConn(conn)
+	assert.True(t, conn.closed)
+	assert.Len(t, db.conns, 0)
+}
@@ -0,0 +1,244 @@
+package mysqldriver
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// ErrDecimalScale is returned by DecimalScaled when value
+// has more significant decimal places than requested scale
+var ErrDecimalScale = errors.New("mysqldriver: decimal value exceeds the scale")
+
+// DecimalScaled returns value of DECIMAL column as an int64 scaled
+// by 10^scale and NULL indicator, e.g. "12.34" with scale 2 is 1234.
+// When value is NULL, second parameter is true.
+// Value with more significant decimal places than scale
+// isn't rounded and ErrDecimalScale is returned by LastError,
+// overflow is reported as strconv.ErrRange.
+//
+//	rows, _ := conn.Query("SELECT price FROM goods") // DECIMAL(10, 2)
+//	for rows.Next() {
+//		cents, _ := rows.DecimalScaled(2)
+//	}
+func (r *Rows) DecimalScaled(scale int) (int64, bool) {
+	value, null := r.NullBytes()
+	if null {
+		return 0, true
+	}
+
+	num, err := parseScaled(value, scale)
+	if err != nil {
+		r.errParse = err
+	}
+	return num, false
+}
+
+// DecimalScaled returns value of DECIMAL column as an int64
+// scaled by 10^scale and NULL indicator (see func (Rows) DecimalScaled)
+func (r Row) DecimalScaled(col string, scale int) (int64, bool) {
+	value, null := r.NullBytes(col)
+	if null {
+		return 0, true
+	}
+
+	num, err := parseScaled(value, scale)
+	if err != nil {
+		r.rows.errParse = err
+	}
+	return num, false
+}
+
+// Decimal returns value of DECIMAL column as a string.
+// NULL value is represented as empty string.
+// Text of the value is returned as it's sent by the server,
+// so it keeps all digits unlike Float64, which loses precision
+// of the values not representable by float64, e.g. DECIMAL(18, 4).
+// Value which isn't a valid decimal number is returned
+// as is and the error is returned by LastError.
+func (r *Rows) Decimal() string {
+	str, _ := r.NullDecimal()
+	return str
+}
+
+// NullDecimal returns value of DECIMAL column as a string
+// and NULL indicator. When value is NULL, second parameter is true.
+func (r *Rows) NullDecimal() (string, bool) {
+	value, null := r.NullBytes()
+	if null {
+		return "", true
+	}
+
+	value = r.conn.stripGrouping(value)
+	if err := validateDecimal(value); err != nil {
+		r.errParse = err
+	}
+	return string(value), false
+}
+
+// NullBigRat returns exact value of DECIMAL column as a *big.Rat
+// and NULL indicator. When value is NULL, first parameter is nil
+// and second parameter is true.
+//
+//	rows, _ := conn.Query("SELECT balance FROM accounts") // DECIMAL(18, 4)
+//	for rows.Next() {
+//		balance, null := rows.NullBigRat()
+//	}
+func (r *Rows) NullBigRat() (*big.Rat, bool) {
+	value, null := r.NullBytes()
+	if null {
+		return nil, true
+	}
+
+	num, err := parseBigRat(r.conn.stripGrouping(value))
+	if err != nil {
+		r.errParse = err
+	}
+	return num, false
+}
+
+// Decimal returns value of DECIMAL column as a string
+// (see func (Rows) Decimal)
+func (r Row) Decimal(col string) string {
+	str, _ := r.NullDecimal(col)
+	return str
+}
+
+// NullDecimal returns value of DECIMAL column as a string
+// and NULL indicator (see func (Rows) NullDecimal)
+func (r Row) NullDecimal(col string) (string, bool) {
+	value, null := r.NullBytes(col)
+	if null {
+		return "", true
+	}
+
+	value = r.rows.conn.stripGrouping(value)
+	if err := validateDecimal(value); err != nil {
+		r.rows.errParse = err
+	}
+	return string(value), false
+}
+
+// NullBigRat returns exact value of DECIMAL column as a *big.Rat
+// and NULL indicator (see func (Rows) NullBigRat)
+func (r Row) NullBigRat(col string) (*big.Rat, bool) {
+	value, null := r.NullBytes(col)
+	if null {
+		return nil, true
+	}
+
+	num, err := parseBigRat(r.rows.conn.stripGrouping(value))
+	if err != nil {
+		r.rows.errParse = err
+	}
+	return num, false
+}
+
+// validateDecimal checks that value is a decimal number
+// like "-12.34" as it's sent by the server, without exponent
+func validateDecimal(value []byte) error {
+	const fnDecimal = "Decimal"
+
+	s := value
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		s = s[1:]
+	}
+
+	digits, dot := 0, false
+	for _, ch := range s {
+		if ch == '.' && !dot {
+			dot = true
+			continue
+		}
+		if ch < '0' || ch > '9' {
+			return &strconv.NumError{Func: fnDecimal, Num: string(value), Err: strconv.ErrSyntax}
+		}
+		digits++
+	}
+	if digits == 0 {
+		return &strconv.NumError{Func: fnDecimal, Num: string(value), Err: strconv.ErrSyntax}
+	}
+	return nil
+}
+
+// parseBigRat parses decimal number into *big.Rat.
+// Only decimal notation is accepted, unlike big.Rat.SetString,
+// which also parses fractions like "1/3" and exponents.
+func parseBigRat(value []byte) (*big.Rat, error) {
+	if err := validateDecimal(value); err != nil {
+		return nil, err
+	}
+
+	num, ok := new(big.Rat).SetString(string(value))
+	if !ok {
+		return nil, &strconv.NumError{Func: "NullBigRat", Num: string(value), Err: strconv.ErrSyntax}
+	}
+	return num, nil
+}
+
+// parseScaled parses decimal number like "-12.34"
+// into an integer scaled by 10^scale without allocations
+func parseScaled(value []byte, scale int) (int64, error) {
+	const fnDecimalScaled = "DecimalScaled"
+
+	s := value
+	neg := false
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	if len(s) == 0 || scale < 0 {
+		return 0, &strconv.NumError{Func: fnDecimalScaled, Num: string(value), Err: strconv.ErrSyntax}
+	}
+
+	// accumulate negative number, so math.MinInt64 can be represented
+	var num int64
+	digits, fraction := 0, -1
+	for _, ch := range s {
+		if ch == '.' && fraction < 0 {
+			fraction = 0
+			continue
+		}
+		if ch < '0' || ch > '9' {
+			return 0, &strconv.NumError{Func: fnDecimalScaled, Num: string(value), Err: strconv.ErrSyntax}
+		}
+		digits++
+
+		if fraction >= 0 {
+			fraction++
+			if fraction > scale {
+				if ch != '0' {
+					return 0, ErrDecimalScale
+				}
+				continue
+			}
+		}
+
+		if num < (math.MinInt64+int64(ch-'0'))/10 {
+			return 0, &strconv.NumError{Func: fnDecimalScaled, Num: string(value), Err: strconv.ErrRange}
+		}
+		num = num*10 - int64(ch-'0')
+	}
+	if digits == 0 {
+		return 0, &strconv.NumError{Func: fnDecimalScaled, Num: string(value), Err: strconv.ErrSyntax}
+	}
+
+	if fraction < 0 {
+		fraction = 0
+	}
+	for ; fraction < scale; fraction++ {
+		if num < math.MinInt64/10 {
+			return 0, &strconv.NumError{Func: fnDecimalScaled, Num: string(value), Err: strconv.ErrRange}
+		}
+		num *= 10
+	}
+
+	if !neg {
+		if num == math.MinInt64 {
+			return 0, &strconv.NumError{Func: fnDecimalScaled, Num: string(value), Err: strconv.ErrRange}
+		}
+		num = -num
+	}
+	return num, nil
+}
@@ -0,0 +1,150 @@
+package mysqldriver
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScaled(t *testing.T) {
+	tests := []struct {
+		value string
+		scale int
+		num   int64
+	}{
+		{"12.34", 2, 1234},
+		{"12.3", 2, 1230},
+		{"12", 2, 1200},
+		{"-0.05", 2, -5},
+		{"+7.10", 1, 71},
+		{"1.500", 1, 15},
+		{".5", 1, 5},
+		{"9223372036854775807", 0, math.MaxInt64},
+		{"-922337203685477580.8", 1, math.MinInt64},
+	}
+
+	for _, test := range tests {
+		num, err := parseScaled([]byte(test.value), test.scale)
+		assert.NoError(t, err, test.value)
+		assert.Equal(t, num, test.num, test.value)
+	}
+}
+
+func TestParseScaledReturnsError(t *testing.T) {
+	_, err := parseScaled([]byte("12.345"), 2)
+	assert.Equal(t, err, ErrDecimalScale)
+
+	_, err = parseScaled([]byte("92233720368547758.08"), 2)
+	assert.Equal(t, err.(*strconv.NumError).Err, strconv.ErrRange)
+
+	_, err = parseScaled([]byte("9223372036854775808"), 0)
+	assert.Equal(t, err.(*strconv.NumError).Err, strconv.ErrRange)
+
+	for _, value := range []string{"", "-", ".", "1.2.3", "1e5", "abc"} {
+		_, err = parseScaled([]byte(value), 2)
+		assert.Equal(t, err.(*strconv.NumError).Err, strconv.ErrSyntax, value)
+	}
+}
+
+func TestRowsDecimalScaled(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01},
+		columnDefinition("price"),
+		eofPacket,
+		[]byte{0x05, '1', '2', '.', '3', '4'},
+		[]byte{0xfb},
+		[]byte{0x05, '1', '.', '2', '3', '4'},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT price FROM goods")
+	assert.NoError(t, err)
+
+	assert.True(t, rows.Next())
+	num, null := rows.DecimalScaled(2)
+	assert.Equal(t, num, int64(1234))
+	assert.False(t, null)
+
+	assert.True(t, rows.Next())
+	num, null = rows.DecimalScaled(2)
+	assert.Equal(t, num, int64(0))
+	assert.True(t, null)
+
+	assert.True(t, rows.Next())
+	rows.DecimalScaled(2)
+	assert.False(t, rows.Next())
+	assert.Equal(t, rows.LastError(), ErrDecimalScale)
+}
+
+func TestValidateDecimal(t *testing.T) {
+	for _, value := range []string{"12.34", "-0.0001", "+7", "99999999999999.9999", ".5"} {
+		assert.NoError(t, validateDecimal([]byte(value)), value)
+	}
+	for _, value := range []string{"", "-", ".", "1.2.3", "1e5", "1/3", "abc"} {
+		err := validateDecimal([]byte(value))
+		assert.Equal(t, err.(*strconv.NumError).Err, strconv.ErrSyntax, value)
+	}
+}
+
+func TestRowsDecimal(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01},
+		columnDefinition("balance"),
+		eofPacket,
+		[]byte{0x13, '9', '9', '9', '9', '9', '9', '9', '9', '9', '9', '9', '9', '9', '.', '0', '0', '0', '1', '5'},
+		[]byte{0xfb},
+		[]byte{0x03, 'a', 'b', 'c'},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT balance FROM accounts")
+	assert.NoError(t, err)
+
+	assert.True(t, rows.Next())
+	str, null := rows.NullDecimal()
+	assert.Equal(t, str, "9999999999999.00015")
+	assert.False(t, null)
+
+	assert.True(t, rows.Next())
+	str, null = rows.NullDecimal()
+	assert.Equal(t, str, "")
+	assert.True(t, null)
+
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Decimal(), "abc")
+	assert.False(t, rows.Next())
+	assert.Equal(t, rows.LastError().(*strconv.NumError).Err, strconv.ErrSyntax)
+}
+
+func TestRowsNullBigRat(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01},
+		columnDefinition("balance"),
+		eofPacket,
+		[]byte{0x0a, '-', '1', '2', '3', '4', '5', '.', '6', '7', '8'},
+		[]byte{0xfb},
+		[]byte{0x03, '1', '/', '3'},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT balance FROM accounts")
+	assert.NoError(t, err)
+
+	assert.True(t, rows.Next())
+	num, null := rows.NullBigRat()
+	assert.Equal(t, num.Cmp(big.NewRat(-12345678, 1000)), 0)
+	assert.False(t, null)
+
+	assert.True(t, rows.Next())
+	num, null = rows.NullBigRat()
+	assert.Nil(t, num)
+	assert.True(t, null)
+
+	assert.True(t, rows.Next())
+	rows.NullBigRat()
+	assert.False(t, rows.Next())
+	assert.Equal(t, rows.LastError().(*strconv.NumError).Err, strconv.ErrSyntax)
+}
@@ -0,0 +1,50 @@
+package mysqldriver
+
+import (
+	"errors"
+)
+
+// ErrNoStatementDigest is returned by LastStatementDigest when
+// performance_schema doesn't contain statements of the connection
+// or digest isn't computed, e.g. when statements_digest consumer
+// is disabled or performance_schema_max_digest_length is 0
+var ErrNoStatementDigest = errors.New("mysqldriver: statement digest isn't available")
+
+const lastStatementDigestSQL = `SELECT h.DIGEST
+FROM performance_schema.events_statements_history h
+JOIN performance_schema.threads t ON t.THREAD_ID = h.THREAD_ID
+WHERE t.PROCESSLIST_ID = CONNECTION_ID()
+ORDER BY h.EVENT_ID DESC
+LIMIT 1`
+
+// LastStatementDigest returns digest of the last statement performed
+// by the connection. Digest is a hash of the normalized statement
+// computed by the server, so statements which differ only by literals
+// have the same digest, e.g. "SELECT * FROM dogs WHERE id = 1"
+// and "SELECT * FROM dogs WHERE id = 2".
+// Statement which is being executed is in events_statements_current
+// table, so the last completed one is read from
+// events_statements_history the same way as LastQueryStats does.
+//
+//	conn.Exec("UPDATE dogs SET age = age + 1 WHERE id = 5")
+//	digest, _ := conn.LastStatementDigest()
+//	metrics.Count(digest)
+func (c *Conn) LastStatementDigest() (string, error) {
+	rows, err := c.Query(lastStatementDigestSQL)
+	if err != nil {
+		return "", err
+	}
+
+	var digest string
+	for rows.Next() {
+		digest = rows.String()
+	}
+	if err := rows.LastError(); err != nil {
+		return "", err
+	}
+
+	if digest == "" {
+		return "", ErrNoStatementDigest
+	}
+	return digest, nil
+}
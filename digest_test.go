@@ -0,0 +1,32 @@
+package mysqldriver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnLastStatementDigest(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec(`INSERT INTO people(firstname) VALUES ("bob")`)
+		assert.NoError(t, err)
+		insert, err := conn.LastStatementDigest()
+		assert.NoError(t, err)
+		assert.Len(t, insert, 64) // SHA-256 in hex
+
+		_, err = conn.Exec(`INSERT INTO people(firstname) VALUES ("alice")`)
+		assert.NoError(t, err)
+		digest, err := conn.LastStatementDigest()
+		assert.NoError(t, err)
+		assert.Equal(t, digest, insert)
+
+		rows, err := conn.Query("SELECT firstname FROM people")
+		assert.NoError(t, err)
+		for rows.Next() {
+		}
+		assert.NoError(t, rows.LastError())
+		digest, err = conn.LastStatementDigest()
+		assert.NoError(t, err)
+		assert.NotEqual(t, digest, insert)
+	})
+}
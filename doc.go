@@ -73,5 +73,46 @@ to prevent further reuse as it's in invalid state.
  	// it won't be reused by the pool.
  	conn.Close()
  }
+
+Reading numeric aggregates
+
+Aggregate functions like SUM() and AVG() return DECIMAL values
+even for integer columns, e.g. SUM() of INT column returns "42"
+and AVG() returns "8.5000". Integer functions (Int, Int64, etc.)
+accept DECIMAL values when their fractional part consists only
+of zeros, so "42.0000" is read as 42. Values with non-zero
+fractional part like "8.5000" can't be read as integers and
+cause parsing error which is returned by LastError().
+
+ rows, err := conn.Query("SELECT SUM(age), AVG(age) FROM people")
+ for rows.Next() {
+ 	sum := rows.Int64()   // SUM(age) = "130"
+ 	avg := rows.Float64() // AVG(age) = "43.3333"
+ }
+
+Float functions (Float32, Float64) can read any DECIMAL value
+but as any floating-point number it may lose precision.
+
+Using with database/sql
+
+Package registers "mysqlclient" driver, so connections can be
+used by sql.DB with the same data source as NewDB accepts.
+Values are returned as []byte and converted by Scan.
+
+ db, err := sql.Open("mysqlclient", "root:pass@tcp(127.0.0.1:3306)/test")
+ var name string
+ err = db.QueryRow("SELECT name FROM dogs WHERE id = ?", 1).Scan(&name)
+
+Encrypted connections
+
+Connection is encrypted with TLS when Options.TLSMode is set.
+TLSVerifyFull verifies the certificate of the server,
+TLSRequired only encrypts the traffic.
+
+ db := mysqldriver.NewDB("root:pass@tcp(db.example.com:3306)/test", 10)
+ db.Options = mysqldriver.Options{
+ 	TLSMode:   mysqldriver.TLSVerifyFull,
+ 	TLSConfig: &tls.Config{RootCAs: pool},
+ }
 */
 package mysqldriver
@@ -0,0 +1,282 @@
+package mysqldriver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/pubnative/mysqlproto-go"
+)
+
+var errNamedArgs = errors.New("mysqldriver: named arguments aren't supported")
+
+func init() {
+	sql.Register("mysqlclient", &Driver{})
+}
+
+// Driver implements database/sql/driver interface, so connections
+// can be used by sql.DB. It's registered as "mysqlclient" driver
+// and accepts the same data source as NewDB does:
+//
+//	db, err := sql.Open("mysqlclient", "root:pass@tcp(127.0.0.1:3306)/test")
+//
+// Queries with arguments are sent by replacing ? placeholders
+// with SQL literals (see func (Conn) QueryArgs), prepared
+// statements are sent in the binary protocol (see func (Conn) Prepare).
+// All values are returned as []byte or nil for NULL,
+// so they're converted by database/sql during Scan.
+type Driver struct {
+	// Options are used to establish every connection (see NewConnOptions)
+	Options Options
+
+	// ReadTimeout is applied to every packet read from the stream.
+	// Zero value means no timeout.
+	ReadTimeout time.Duration
+}
+
+// Open establishes a new connection to the DB
+func (d *Driver) Open(dataSource string) (driver.Conn, error) {
+	usr, pass, proto, addr, dbname := parseDataSource(dataSource)
+	conn, err := NewConnOptions(context.Background(), usr, pass, proto, addr, dbname, d.ReadTimeout, d.Options)
+	if err != nil {
+		if conn != nil {
+			conn.Close()
+		}
+		return nil, err
+	}
+	return &sqlConn{conn: conn}, nil
+}
+
+// sqlConn implements driver.Conn and optional interfaces of it
+type sqlConn struct {
+	conn *Conn
+}
+
+func (c *sqlConn) Prepare(query string) (driver.Stmt, error) {
+	if !c.conn.valid {
+		return nil, driver.ErrBadConn
+	}
+	stmt, err := c.conn.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlStmt{stmt: stmt}, nil
+}
+
+func (c *sqlConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *sqlConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *sqlConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if !c.conn.valid {
+		return nil, driver.ErrBadConn
+	}
+
+	var isolation IsolationLevel
+	switch sql.IsolationLevel(opts.Isolation) {
+	case sql.LevelDefault:
+	case sql.LevelReadUncommitted:
+		isolation = LevelReadUncommitted
+	case sql.LevelReadCommitted:
+		isolation = LevelReadCommitted
+	case sql.LevelRepeatableRead:
+		isolation = LevelRepeatableRead
+	case sql.LevelSerializable:
+		isolation = LevelSerializable
+	default:
+		return nil, errors.New("mysqldriver: unsupported isolation level " + sql.IsolationLevel(opts.Isolation).String())
+	}
+
+	return c.conn.BeginTxContext(ctx, TxOptions{Isolation: isolation, ReadOnly: opts.ReadOnly})
+}
+
+func (c *sqlConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if !c.conn.valid {
+		return nil, driver.ErrBadConn
+	}
+	query, err := c.interpolate(query, args)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := c.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlRows{rows: rows}, nil
+}
+
+func (c *sqlConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if !c.conn.valid {
+		return nil, driver.ErrBadConn
+	}
+	query, err := c.interpolate(query, args)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := c.conn.ExecContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return sqlResult{ok: ok}, nil
+}
+
+func (c *sqlConn) interpolate(query string, args []driver.NamedValue) (string, error) {
+	if len(args) == 0 {
+		return query, nil
+	}
+	values, err := namedValues(args)
+	if err != nil {
+		return "", err
+	}
+	return c.conn.interpolate(query, values)
+}
+
+func (c *sqlConn) Ping(ctx context.Context) error {
+	if !c.conn.valid {
+		return driver.ErrBadConn
+	}
+	return c.conn.PingContext(ctx)
+}
+
+// ResetSession is called by sql.DB before the connection is reused
+func (c *sqlConn) ResetSession(ctx context.Context) error {
+	if !c.IsValid() {
+		return driver.ErrBadConn
+	}
+	return nil
+}
+
+// IsValid reports whether sql.DB can return connection to the pool
+func (c *sqlConn) IsValid() bool {
+	return c.conn.valid && !c.conn.closed
+}
+
+// sqlStmt implements driver.Stmt
+type sqlStmt struct {
+	stmt *Stmt
+}
+
+func (s *sqlStmt) Close() error {
+	return s.stmt.Close()
+}
+
+func (s *sqlStmt) NumInput() int {
+	return len(s.stmt.Params())
+}
+
+func (s *sqlStmt) Exec(args []driver.Value) (driver.Result, error) {
+	ok, err := s.stmt.Exec(values(args)...)
+	if err != nil {
+		return nil, err
+	}
+	return sqlResult{ok: ok}, nil
+}
+
+func (s *sqlStmt) Query(args []driver.Value) (driver.Rows, error) {
+	rows, err := s.stmt.Query(values(args)...)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlRows{rows: rows}, nil
+}
+
+// sqlRows implements driver.Rows
+type sqlRows struct {
+	rows *Rows
+}
+
+func (r *sqlRows) Columns() []string {
+	return r.rows.ColumnNames()
+}
+
+// Close discards unread rows and result sets,
+// so connection can be used for the next query
+func (r *sqlRows) Close() error {
+	return r.rows.Close()
+}
+
+// HasNextResultSet implements driver.RowsNextResultSet
+func (r *sqlRows) HasNextResultSet() bool {
+	return r.rows.moreResults
+}
+
+// NextResultSet implements driver.RowsNextResultSet
+func (r *sqlRows) NextResultSet() error {
+	if !r.rows.NextResultSet() {
+		if err := r.rows.LastError(); err != nil {
+			return err
+		}
+		return io.EOF
+	}
+	return nil
+}
+
+// Next reads values of the next row into dest. Values refer
+// to the packet of the row and they're valid until the next call.
+func (r *sqlRows) Next(dest []driver.Value) error {
+	if !r.rows.Next() {
+		if err := r.rows.LastError(); err != nil {
+			return err
+		}
+		return io.EOF
+	}
+
+	for i := range dest {
+		value, null := r.rows.NullBytes()
+		if null {
+			dest[i] = nil
+		} else {
+			dest[i] = value
+		}
+	}
+	return r.rows.LastError()
+}
+
+// ColumnTypeDatabaseTypeName implements driver.RowsColumnTypeDatabaseTypeName
+func (r *sqlRows) ColumnTypeDatabaseTypeName(index int) string {
+	return r.rows.definitions[index].TypeName()
+}
+
+// ColumnTypeNullable implements driver.RowsColumnTypeNullable
+func (r *sqlRows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	return r.rows.definitions[index].Nullable(), true
+}
+
+// sqlResult implements driver.Result
+type sqlResult struct {
+	ok mysqlproto.OKPacket
+}
+
+func (r sqlResult) LastInsertId() (int64, error) {
+	return int64(r.ok.LastInsertID), nil
+}
+
+func (r sqlResult) RowsAffected() (int64, error) {
+	return int64(r.ok.AffectedRows), nil
+}
+
+func values(args []driver.Value) []interface{} {
+	result := make([]interface{}, len(args))
+	for i, arg := range args {
+		result[i] = arg
+	}
+	return result
+}
+
+func namedValues(args []driver.NamedValue) ([]interface{}, error) {
+	result := make([]interface{}, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errNamedArgs
+		}
+		result[i] = arg.Value
+	}
+	return result, nil
+}
@@ -0,0 +1,135 @@
+package mysqldriver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLRowsNextReadsValuesAsBytes(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x02}, columnDefinition("id"), columnDefinition("name"), eofPacket,
+		[]byte{0x01, '1', 0xfb},
+		[]byte{0x01, '2', 0x03, 'M', 'a', 'x'},
+		eofPacket,
+	)
+	sc := &sqlConn{conn: conn}
+
+	rows, err := sc.QueryContext(context.Background(), "SELECT id, name FROM dogs", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, rows.Columns(), []string{"id", "name"})
+
+	dest := make([]driver.Value, 2)
+	assert.NoError(t, rows.Next(dest))
+	assert.Equal(t, dest, []driver.Value{[]byte("1"), nil})
+	assert.NoError(t, rows.Next(dest))
+	assert.Equal(t, dest, []driver.Value{[]byte("2"), []byte("Max")})
+	assert.Equal(t, rows.Next(dest), io.EOF)
+	assert.NoError(t, rows.Close())
+}
+
+func TestSQLRowsColumnTypes(t *testing.T) {
+	conn := newPacketConn([]byte{0x01}, columnDefinition("name"), eofPacket, eofPacket)
+	sc := &sqlConn{conn: conn}
+
+	rows, err := sc.QueryContext(context.Background(), "SELECT name FROM dogs", nil)
+	assert.NoError(t, err)
+	typed := rows.(driver.RowsColumnTypeDatabaseTypeName)
+	assert.Equal(t, typed.ColumnTypeDatabaseTypeName(0), "VARCHAR")
+	nullable, ok := rows.(driver.RowsColumnTypeNullable).ColumnTypeNullable(0)
+	assert.True(t, ok)
+	assert.True(t, nullable)
+	assert.NoError(t, rows.Close())
+}
+
+func TestSQLRowsCloseDiscardsUnreadRows(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01}, columnDefinition("id"), eofPacket,
+		[]byte{0x01, '1'},
+		[]byte{0x01, '2'},
+		eofPacket,
+	)
+	sc := &sqlConn{conn: conn}
+
+	rows, err := sc.QueryContext(context.Background(), "SELECT id FROM dogs", nil)
+	assert.NoError(t, err)
+	assert.NoError(t, rows.Close())
+	assert.NoError(t, conn.acquire())
+}
+
+func TestSQLConnRejectsNamedArguments(t *testing.T) {
+	sc := &sqlConn{conn: newPacketConn()}
+	_, err := sc.QueryContext(context.Background(), "SELECT ?", []driver.NamedValue{{Name: "id", Ordinal: 1, Value: int64(1)}})
+	assert.Equal(t, err, errNamedArgs)
+}
+
+func TestSQLConnReturnsErrBadConnForInvalidConnection(t *testing.T) {
+	conn := newPacketConn()
+	conn.valid = false
+	sc := &sqlConn{conn: conn}
+
+	_, err := sc.QueryContext(context.Background(), "SELECT 1", nil)
+	assert.Equal(t, err, driver.ErrBadConn)
+	assert.False(t, sc.IsValid())
+}
+
+func TestSQLConnBeginTxWithCanceledContext(t *testing.T) {
+	conn := newPacketConn()
+	sc := &sqlConn{conn: conn}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := sc.BeginTx(ctx, driver.TxOptions{Isolation: driver.IsolationLevel(sql.LevelReadCommitted)})
+	assert.Equal(t, err, context.Canceled)
+	assert.Nil(t, conn.tx)
+	assert.True(t, conn.valid)
+}
+
+func TestDriverQueryWithArguments(t *testing.T) {
+	db, err := sql.Open("mysqlclient", "root@tcp(127.0.0.1:3306)/test")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	var name string
+	var age int
+	err = db.QueryRow("SELECT ?, ?", "Max", 3).Scan(&name, &age)
+	assert.NoError(t, err)
+	assert.Equal(t, name, "Max")
+	assert.Equal(t, age, 3)
+}
+
+func TestDriverPreparedStatementAndTransaction(t *testing.T) {
+	db, err := sql.Open("mysqlclient", "root@tcp(127.0.0.1:3306)/test")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	// temporary table is visible only to the session which created it
+	conn, err := db.Conn(context.Background())
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.ExecContext(context.Background(), "CREATE TEMPORARY TABLE driver_dogs (id INT AUTO_INCREMENT PRIMARY KEY, name VARCHAR(32)) ENGINE=InnoDB")
+	assert.NoError(t, err)
+
+	tx, err := conn.BeginTx(context.Background(), nil)
+	assert.NoError(t, err)
+	stmt, err := tx.Prepare("INSERT INTO driver_dogs(name) VALUES (?)")
+	assert.NoError(t, err)
+	result, err := stmt.Exec("Max")
+	assert.NoError(t, err)
+	id, _ := result.LastInsertId()
+	affected, _ := result.RowsAffected()
+	assert.Equal(t, id, int64(1))
+	assert.Equal(t, affected, int64(1))
+	assert.NoError(t, stmt.Close())
+	assert.NoError(t, tx.Commit())
+
+	var name sql.NullString
+	err = conn.QueryRowContext(context.Background(), "SELECT name FROM driver_dogs WHERE id = ?", 1).Scan(&name)
+	assert.NoError(t, err)
+	assert.Equal(t, name, sql.NullString{String: "Max", Valid: true})
+}
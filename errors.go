@@ -0,0 +1,126 @@
+package mysqldriver
+
+import (
+	"errors"
+	"io"
+	"net"
+
+	"github.com/pubnative/mysqlproto-go"
+)
+
+// MySQL server error codes
+// (see https://dev.mysql.com/doc/refman/8.0/en/server-error-reference.html)
+const (
+	errCodeServerShutdown           = 1053 // ER_SERVER_SHUTDOWN
+	errCodeDuplicateEntry           = 1062 // ER_DUP_ENTRY
+	errCodeParse                    = 1064 // ER_PARSE_ERROR
+	errCodeNetPacketTooLarge        = 1153 // ER_NET_PACKET_TOO_LARGE
+	errCodeLockWaitTimeout          = 1205 // ER_LOCK_WAIT_TIMEOUT
+	errCodeDeadlock                 = 1213 // ER_LOCK_DEADLOCK
+	errCodeOptionPreventsStatement  = 1290 // ER_OPTION_PREVENTS_STATEMENT
+	errCodeDuplicateEntryWithKey    = 1586 // ER_DUP_ENTRY_WITH_KEY_NAME
+	errCodeReadOnlyMode             = 1836 // ER_READ_ONLY_MODE
+	errCodeConnectionKilled         = 1927 // ER_CONNECTION_KILLED
+	errCodeClientInteractionTimeout = 4031 // ER_CLIENT_INTERACTION_TIMEOUT
+)
+
+// errorPacket returns ERRPacket sent by the server when err is
+// ERRPacket, a pointer to it or an error wrapping it
+func errorPacket(err error) (mysqlproto.ERRPacket, bool) {
+	switch e := err.(type) {
+	case mysqlproto.ERRPacket:
+		return e, true
+	case *mysqlproto.ERRPacket:
+		if e == nil {
+			return mysqlproto.ERRPacket{}, false
+		}
+		return *e, true
+	case *StatementError:
+		return errorPacket(e.Err)
+	}
+
+	var packet mysqlproto.ERRPacket
+	if errors.As(err, &packet) {
+		return packet, true
+	}
+	return mysqlproto.ERRPacket{}, false
+}
+
+// ErrorCode returns MySQL error code when err is ERRPacket
+// returned by the server, e.g. 1062 for the duplicate entry.
+// Codes don't depend on the language of the server messages.
+//
+//	if code, ok := mysqldriver.ErrorCode(err); ok && code == 1146 {
+//		// table doesn't exist
+//	}
+func ErrorCode(err error) (uint16, bool) {
+	packet, ok := errorPacket(err)
+	return packet.ErrorCode, ok
+}
+
+// SQLState returns SQLSTATE value of ERRPacket returned by the server,
+// e.g. "23000" for integrity constraint violation
+func SQLState(err error) (string, bool) {
+	packet, ok := errorPacket(err)
+	return packet.SQLState, ok
+}
+
+// IsDuplicateKey reports whether err is returned by the server
+// because the statement violates PRIMARY KEY or UNIQUE index, e.g.
+// to ignore the row which has been already inserted by another client.
+func IsDuplicateKey(err error) bool {
+	code, ok := ErrorCode(err)
+	return ok && (code == errCodeDuplicateEntry || code == errCodeDuplicateEntryWithKey)
+}
+
+// IsDeadlock reports whether err is returned by the server because
+// the transaction has been rolled back to resolve the deadlock.
+// The whole transaction should be retried in this case.
+func IsDeadlock(err error) bool {
+	code, ok := ErrorCode(err)
+	return ok && code == errCodeDeadlock
+}
+
+// IsLockWaitTimeout reports whether err is returned by the server
+// because the statement hasn't got the lock within innodb_lock_wait_timeout.
+// By default, only the statement is rolled back, not the transaction.
+func IsLockWaitTimeout(err error) bool {
+	code, ok := ErrorCode(err)
+	return ok && code == errCodeLockWaitTimeout
+}
+
+// IsReadOnlyError reports whether err is returned by the server
+// running in read-only mode, e.g. when write is sent to the replica
+// after failover. In this case the statement should be sent to the primary.
+//
+// Error 1290 (ER_OPTION_PREVENTS_STATEMENT) is returned by the server
+// started with --read-only or --super-read-only option,
+// error 1836 (ER_READ_ONLY_MODE) is returned when the server
+// is in read-only mode for other reasons.
+func IsReadOnlyError(err error) bool {
+	code, ok := ErrorCode(err)
+	return ok && (code == errCodeOptionPreventsStatement || code == errCodeReadOnlyMode)
+}
+
+// IsConnectionError reports whether err means the connection is broken
+// and the statement can be retried only on a new connection, e.g.
+// the server has been restarted or has closed the idle connection.
+// It's true for network errors, unexpected end of the stream and
+// errors which the server sends right before closing the connection:
+// 1053 (ER_SERVER_SHUTDOWN), 1927 (ER_CONNECTION_KILLED) and
+// 4031 (ER_CLIENT_INTERACTION_TIMEOUT).
+//
+// ErrQueryTimeout isn't a connection error though
+// the connection can't be used after it.
+func IsConnectionError(err error) bool {
+	if err == nil || err == ErrQueryTimeout {
+		return false
+	}
+	if code, ok := ErrorCode(err); ok {
+		return code == errCodeServerShutdown ||
+			code == errCodeConnectionKilled ||
+			code == errCodeClientInteractionTimeout
+	}
+	var netErr net.Error
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, &netErr)
+}
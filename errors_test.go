@@ -0,0 +1,85 @@
+package mysqldriver
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsReadOnlyError(t *testing.T) {
+	assert.True(t, IsReadOnlyError(mysqlproto.ERRPacket{ErrorCode: 1290}))
+	assert.True(t, IsReadOnlyError(mysqlproto.ERRPacket{ErrorCode: 1836}))
+	assert.True(t, IsReadOnlyError(&mysqlproto.ERRPacket{ErrorCode: 1290}))
+	assert.True(t, IsReadOnlyError(&StatementError{Err: mysqlproto.ERRPacket{ErrorCode: 1836}}))
+	assert.False(t, IsReadOnlyError(mysqlproto.ERRPacket{ErrorCode: 1062}))
+	assert.False(t, IsReadOnlyError(errors.New("read-only")))
+	assert.False(t, IsReadOnlyError(nil))
+}
+
+func TestErrorCode(t *testing.T) {
+	packet := mysqlproto.ERRPacket{ErrorCode: 1146, SQLState: "42S02", ErrorMessage: "Table 'test.dogs' doesn't exist"}
+	code, ok := ErrorCode(packet)
+	assert.True(t, ok)
+	assert.Equal(t, code, uint16(1146))
+	state, ok := SQLState(packet)
+	assert.True(t, ok)
+	assert.Equal(t, state, "42S02")
+
+	code, ok = ErrorCode(fmt.Errorf("select dogs: %w", packet))
+	assert.True(t, ok)
+	assert.Equal(t, code, uint16(1146))
+
+	code, ok = ErrorCode(&StatementError{Err: &packet})
+	assert.True(t, ok)
+	assert.Equal(t, code, uint16(1146))
+
+	_, ok = ErrorCode(errors.New("Table 'test.dogs' doesn't exist"))
+	assert.False(t, ok)
+	_, ok = SQLState((*mysqlproto.ERRPacket)(nil))
+	assert.False(t, ok)
+	_, ok = ErrorCode(nil)
+	assert.False(t, ok)
+}
+
+func TestErrorPredicates(t *testing.T) {
+	assert.True(t, IsDuplicateKey(mysqlproto.ERRPacket{ErrorCode: 1062}))
+	assert.True(t, IsDuplicateKey(mysqlproto.ERRPacket{ErrorCode: 1586}))
+	assert.False(t, IsDuplicateKey(mysqlproto.ERRPacket{ErrorCode: 1213}))
+	assert.True(t, IsDeadlock(&StatementError{Err: mysqlproto.ERRPacket{ErrorCode: 1213}}))
+	assert.False(t, IsDeadlock(mysqlproto.ERRPacket{ErrorCode: 1205}))
+	assert.True(t, IsLockWaitTimeout(mysqlproto.ERRPacket{ErrorCode: 1205}))
+	assert.False(t, IsLockWaitTimeout(errors.New("Lock wait timeout exceeded")))
+}
+
+func TestIsDuplicateKeyOfInsert(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec(`INSERT INTO people(id, firstname) VALUES (1, "bob")`)
+		assert.NoError(t, err)
+		_, err = conn.Exec(`INSERT INTO people(id, firstname) VALUES (1, "ben")`)
+		assert.True(t, IsDuplicateKey(err))
+		state, ok := SQLState(err)
+		assert.True(t, ok)
+		assert.Equal(t, state, "23000")
+	})
+}
+
+func TestIsConnectionError(t *testing.T) {
+	assert.True(t, IsConnectionError(io.EOF))
+	assert.True(t, IsConnectionError(io.ErrUnexpectedEOF))
+	assert.True(t, IsConnectionError(&net.OpError{Op: "read", Err: syscall.ECONNRESET}))
+	assert.True(t, IsConnectionError(timeoutError{}))
+	assert.True(t, IsConnectionError(fmt.Errorf("select dogs: %w", io.EOF)))
+	assert.True(t, IsConnectionError(mysqlproto.ERRPacket{ErrorCode: 1053}))
+	assert.True(t, IsConnectionError(mysqlproto.ERRPacket{ErrorCode: 1927}))
+	assert.True(t, IsConnectionError(&StatementError{Err: mysqlproto.ERRPacket{ErrorCode: 4031}}))
+	assert.False(t, IsConnectionError(mysqlproto.ERRPacket{ErrorCode: 1146}))
+	assert.False(t, IsConnectionError(ErrQueryTimeout))
+	assert.False(t, IsConnectionError(ErrTxDone))
+	assert.False(t, IsConnectionError(nil))
+}
@@ -0,0 +1,87 @@
+package mysqldriver
+
+import (
+	"strings"
+)
+
+// EscapeString escapes special characters of the string,
+// so it can be used inside of single-quoted string literal:
+//
+//	sql := "SELECT * FROM dogs WHERE name = '" + conn.EscapeString(name) + "'"
+//
+// When session has NO_BACKSLASH_ESCAPES sql_mode, backslash
+// is an ordinary character, so only quotes are escaped by doubling them.
+// The mode is tracked by the status flags sent by the server
+// after every statement, so changing sql_mode with Exec
+// or SetSQLMode is taken into account.
+func (c *Conn) EscapeString(str string) string {
+	return escapeString(str, c.noBackslashEscapes())
+}
+
+// SQLMode returns sql_mode of the session
+func (c *Conn) SQLMode() (string, error) {
+	rows, err := c.Query("SELECT @@SESSION.sql_mode")
+	if err != nil {
+		return "", err
+	}
+
+	var mode string
+	for rows.Next() {
+		mode = rows.String()
+	}
+	return mode, rows.LastError()
+}
+
+// SetSQLMode changes sql_mode of the session,
+// e.g. "STRICT_TRANS_TABLES,NO_BACKSLASH_ESCAPES"
+func (c *Conn) SetSQLMode(mode string) error {
+	_, err := c.Exec("SET SESSION sql_mode = " + quoteString(mode, c.noBackslashEscapes()))
+	return err
+}
+
+// noBackslashEscapes reports whether NO_BACKSLASH_ESCAPES mode is enabled
+func (c *Conn) noBackslashEscapes() bool {
+	return c.status&serverStatusNoBackslashEscapes != 0
+}
+
+func escapeString(str string, noBackslashEscapes bool) string {
+	if noBackslashEscapes {
+		return strings.Replace(str, "'", "''", -1)
+	}
+
+	buf := make([]byte, 0, len(str))
+	for i := 0; i < len(str); i++ {
+		switch ch := str[i]; ch {
+		case 0:
+			buf = append(buf, '\\', '0')
+		case '\n':
+			buf = append(buf, '\\', 'n')
+		case '\r':
+			buf = append(buf, '\\', 'r')
+		case '\t':
+			buf = append(buf, '\\', 't')
+		case '\x1a':
+			buf = append(buf, '\\', 'Z')
+		case '\'', '"', '\\':
+			buf = append(buf, '\\', ch)
+		default:
+			buf = append(buf, ch)
+		}
+	}
+	return string(buf)
+}
+
+// quoteString returns single-quoted string literal
+func quoteString(str string, noBackslashEscapes bool) string {
+	return "'" + escapeString(str, noBackslashEscapes) + "'"
+}
+
+// quoteIdentifier quotes each part of the qualified name
+// like "db.table" with backticks
+func quoteIdentifier(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = "`" + strings.Replace(part, "`", "``", -1) + "`"
+	}
+	return strings.Join(parts, ".")
+}
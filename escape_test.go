@@ -0,0 +1,57 @@
+package mysqldriver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeString(t *testing.T) {
+	assert.Equal(t, escapeString("", false), "")
+	assert.Equal(t, escapeString(`it's "ok"`, false), `it\'s \"ok\"`)
+	assert.Equal(t, escapeString("a\x00b\n\r\t\x1a\\", false), `a\0b\n\r\t\Z\\`)
+}
+
+func TestEscapeStringWithNoBackslashEscapes(t *testing.T) {
+	assert.Equal(t, escapeString(`it's \'ok\'`, true), `it''s \''ok\''`)
+	assert.Equal(t, escapeString("a\nb\\", true), "a\nb\\")
+}
+
+func TestQuoteString(t *testing.T) {
+	assert.Equal(t, quoteString("", false), "''")
+	assert.Equal(t, quoteString("it's", false), `'it\'s'`)
+	assert.Equal(t, quoteString(`it's\`, true), `'it''s\'`)
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	assert.Equal(t, quoteIdentifier("people"), "`people`")
+	assert.Equal(t, quoteIdentifier("test.people"), "`test`.`people`")
+	assert.Equal(t, quoteIdentifier("we`ird"), "`we``ird`")
+}
+
+func TestConnEscapeStringRespectsSQLMode(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		value := `it's a \ backslash`
+		assertRoundTrip := func() {
+			rows, err := conn.Query("SELECT '" + conn.EscapeString(value) + "'")
+			assert.NoError(t, err)
+			assert.True(t, rows.Next())
+			assert.Equal(t, rows.String(), value)
+			assert.False(t, rows.Next())
+		}
+
+		assert.False(t, conn.noBackslashEscapes())
+		assertRoundTrip()
+
+		assert.NoError(t, conn.SetSQLMode("NO_BACKSLASH_ESCAPES"))
+		assert.True(t, conn.noBackslashEscapes())
+		mode, err := conn.SQLMode()
+		assert.NoError(t, err)
+		assert.Equal(t, mode, "NO_BACKSLASH_ESCAPES")
+		assertRoundTrip()
+
+		assert.NoError(t, conn.SetSQLMode(""))
+		assert.False(t, conn.noBackslashEscapes())
+		assertRoundTrip()
+	})
+}
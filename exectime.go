@@ -0,0 +1,130 @@
+package mysqldriver
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// executionTimeHint returns the statement with MAX_EXECUTION_TIME
+// optimizer hint when the server supports it. MySQL limits only
+// SELECT statements, so other statements aren't changed.
+func (c *Conn) executionTimeHint(sql string) (string, bool) {
+	if !supportsExecutionTimeHint(c.handshake.ServerVersion) {
+		return "", false
+	}
+
+	trimmed := strings.TrimLeft(sql, " \t\r\n(")
+	if len(trimmed) < len("SELECT") || !strings.EqualFold(trimmed[:len("SELECT")], "SELECT") {
+		return sql, true
+	}
+
+	hint := "MAX_EXECUTION_TIME(" + strconv.FormatInt(executionTimeMillis(c.MaxExecutionTime), 10) + ")"
+	hinted, err := withHints(sql, []string{hint})
+	return hinted, err == nil
+}
+
+// queryWithSessionTimeout limits the execution time of the query
+// by the session variable, which is restored after all rows are read.
+// Rows are read into memory, so the variable is restored
+// before the connection is used by the caller.
+func (c *Conn) queryWithSessionTimeout(ctx context.Context, sql string) (*Rows, error) {
+	set, restore := c.executionTimeStatements()
+	if _, err := c.ExecContext(ctx, set); err != nil {
+		return nil, err
+	}
+
+	rows, err := c.queryContextBuffered(ctx, sql)
+
+	// previous value is restored even when the query fails
+	if _, errRestore := c.Exec(restore); errRestore != nil {
+		// session isn't in the state expected by the next user,
+		// so connection is closed when it's returned to the pool
+		c.valid = false
+	}
+	return rows, err
+}
+
+// queryContextBuffered performs the query the same way
+// as QueryContext does and reads all rows into memory
+func (c *Conn) queryContextBuffered(ctx context.Context, sql string) (*Rows, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	c.watchContext(ctx)
+
+	rows, err := c.query(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	// statement without result set is already released
+	if !rows.eof {
+		err := rows.bufferRows()
+		c.release()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rows.eof = false
+	rows.buffered = true
+	return rows, nil
+}
+
+// executionTimeStatements returns statements which set the session
+// variable limiting the execution time and restore its previous value.
+// MySQL measures max_execution_time in milliseconds,
+// while MariaDB measures max_statement_time in seconds.
+func (c *Conn) executionTimeStatements() (set, restore string) {
+	variable := "max_execution_time"
+	value := strconv.FormatInt(executionTimeMillis(c.MaxExecutionTime), 10)
+	if strings.Contains(c.handshake.ServerVersion, "MariaDB") {
+		variable = "max_statement_time"
+		value = strconv.FormatFloat(c.MaxExecutionTime.Seconds(), 'f', -1, 64)
+	}
+
+	set = "SET @mysqldriver_" + variable + " = @@SESSION." + variable + ", SESSION " + variable + " = " + value
+	restore = "SET SESSION " + variable + " = @mysqldriver_" + variable
+	return set, restore
+}
+
+// executionTimeMillis rounds the timeout up to milliseconds,
+// so the timeout shorter than a millisecond isn't disabled
+func executionTimeMillis(timeout time.Duration) int64 {
+	return int64((timeout + time.Millisecond - 1) / time.Millisecond)
+}
+
+// supportsExecutionTimeHint reports whether the server supports
+// MAX_EXECUTION_TIME optimizer hint. It's supported
+// by MySQL 5.7.8 and newer, but not by MariaDB.
+func supportsExecutionTimeHint(version string) bool {
+	if version == "" || strings.Contains(version, "MariaDB") {
+		return false
+	}
+	return versionAtLeast(version, 5, 7, 8)
+}
+
+// versionAtLeast compares server version like "8.0.22-log"
+// with the given major, minor and patch numbers
+func versionAtLeast(version string, want ...int) bool {
+	parts := strings.SplitN(version, ".", len(want))
+	for i, w := range want {
+		if i >= len(parts) {
+			return false
+		}
+		end := 0
+		for end < len(parts[i]) && parts[i][end] >= '0' && parts[i][end] <= '9' {
+			end++
+		}
+		num, err := strconv.Atoi(parts[i][:end])
+		if err != nil {
+			return false
+		}
+		if num != w {
+			return num > w
+		}
+	}
+	return true
+}
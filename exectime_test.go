@@ -0,0 +1,127 @@
+package mysqldriver
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// sessionConn records statements written to the connection
+// and responds with the given data
+type sessionConn struct {
+	readRecorder
+	written []byte
+}
+
+func (c *sessionConn) Write(data []byte) (int, error) {
+	c.written = append(c.written, data...)
+	return len(data), nil
+}
+
+func newSessionConn(version string, payloads ...[]byte) (*Conn, *sessionConn) {
+	data := framePackets(nil, payloads...)
+	s := &sessionConn{readRecorder: readRecorder{data: bytes.NewReader(data)}}
+	conn := &Conn{
+		conn:      mysqlproto.Conn{Stream: mysqlproto.NewStream(s, 0), CapabilityFlags: capabilityFlags},
+		valid:     true,
+		handshake: HandshakeInfo{ServerVersion: version},
+	}
+	return conn, s
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	assert.True(t, versionAtLeast("8.0.22", 5, 7, 8))
+	assert.True(t, versionAtLeast("5.7.8-log", 5, 7, 8))
+	assert.True(t, versionAtLeast("5.7.31", 5, 7, 8))
+	assert.False(t, versionAtLeast("5.7.7-rc", 5, 7, 8))
+	assert.False(t, versionAtLeast("5.6.51", 5, 7, 8))
+	assert.False(t, versionAtLeast("5.7", 5, 7, 8))
+	assert.False(t, versionAtLeast("unknown", 5, 7, 8))
+}
+
+func TestSupportsExecutionTimeHint(t *testing.T) {
+	assert.True(t, supportsExecutionTimeHint("8.0.22"))
+	assert.False(t, supportsExecutionTimeHint("5.5.5-10.6.4-MariaDB"))
+	assert.False(t, supportsExecutionTimeHint("5.6.51"))
+	assert.False(t, supportsExecutionTimeHint(""))
+}
+
+func TestExecutionTimeStatements(t *testing.T) {
+	conn := &Conn{MaxExecutionTime: 1500 * time.Millisecond, handshake: HandshakeInfo{ServerVersion: "5.6.51"}}
+	set, restore := conn.executionTimeStatements()
+	assert.Equal(t, set, "SET @mysqldriver_max_execution_time = @@SESSION.max_execution_time, SESSION max_execution_time = 1500")
+	assert.Equal(t, restore, "SET SESSION max_execution_time = @mysqldriver_max_execution_time")
+
+	conn.handshake.ServerVersion = "5.5.5-10.6.4-MariaDB"
+	set, restore = conn.executionTimeStatements()
+	assert.Equal(t, set, "SET @mysqldriver_max_statement_time = @@SESSION.max_statement_time, SESSION max_statement_time = 1.5")
+	assert.Equal(t, restore, "SET SESSION max_statement_time = @mysqldriver_max_statement_time")
+}
+
+func TestQueryContextMaxExecutionTimeUsesHint(t *testing.T) {
+	conn, s := newSessionConn("8.0.22",
+		[]byte{0x01}, columnDefinition("id"), eofPacket, []byte{0x01, '1'}, eofPacket)
+	conn.MaxExecutionTime = 100 * time.Microsecond
+
+	rows, err := conn.QueryContext(context.Background(), "SELECT id FROM dogs")
+	assert.NoError(t, err)
+	for rows.Next() {
+	}
+	assert.NoError(t, rows.LastError())
+	assert.True(t, bytes.Contains(s.written, []byte("SELECT /*+ MAX_EXECUTION_TIME(1) */ id FROM dogs")))
+	assert.False(t, bytes.Contains(s.written, []byte("SET ")))
+}
+
+func TestQueryContextMaxExecutionTimeRestoresSessionVariable(t *testing.T) {
+	okPacket := []byte{mysqlproto.OK_PACKET, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00}
+	conn, s := newSessionConn("5.5.5-10.6.4-MariaDB",
+		okPacket,
+		[]byte{0x01}, columnDefinition("id"), eofPacket, []byte{0x01, '1'}, eofPacket,
+		okPacket,
+	)
+	conn.MaxExecutionTime = 2 * time.Second
+
+	rows, err := conn.QueryContext(context.Background(), "SELECT id FROM dogs")
+	assert.NoError(t, err)
+	assert.True(t, bytes.Contains(s.written, []byte("SESSION max_statement_time = 2")))
+	assert.True(t, bytes.Contains(s.written, []byte("SET SESSION max_statement_time = @mysqldriver_max_statement_time")))
+	assert.True(t, conn.valid)
+
+	// rows are read before the variable is restored
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Int(), 1)
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.LastError())
+}
+
+func TestQueryContextMaxExecutionTimeRestoresSessionVariableOnError(t *testing.T) {
+	okPacket := []byte{mysqlproto.OK_PACKET, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00}
+	errPacket := append([]byte{mysqlproto.ERR_PACKET, 0xd0, 0x0b, '#'}, "HY000Query execution was interrupted"...)
+	conn, s := newSessionConn("5.6.51", okPacket, errPacket, okPacket)
+	conn.MaxExecutionTime = time.Second
+
+	_, err := conn.QueryContext(context.Background(), "SELECT SLEEP(2)")
+	_, ok := err.(mysqlproto.ERRPacket)
+	assert.True(t, ok)
+	assert.True(t, bytes.Contains(s.written, []byte("SET SESSION max_execution_time = @mysqldriver_max_execution_time")))
+	assert.True(t, conn.valid)
+}
+
+func TestQueryContextMaxExecutionTime(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		conn.MaxExecutionTime = 10 * time.Millisecond
+
+		// interrupted SLEEP returns 1 instead of 0
+		rows, err := conn.QueryContext(context.Background(), "SELECT SLEEP(1)")
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.Int(), 1)
+		assert.False(t, rows.Next())
+		assert.NoError(t, rows.LastError())
+		assert.True(t, conn.valid)
+	})
+}
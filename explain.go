@@ -0,0 +1,175 @@
+package mysqldriver
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var errMalformedExplain = errors.New("mysqldriver: can't parse EXPLAIN ANALYZE output")
+
+// ExplainNode is a single iterator of the query plan
+// returned by EXPLAIN ANALYZE. Time is measured in milliseconds.
+type ExplainNode struct {
+	Operation     string  // e.g. "Table scan on people"
+	EstimatedCost float64 // cost estimated by the optimizer
+	EstimatedRows float64 // number of rows estimated by the optimizer
+
+	Executed     bool    // false when iterator is marked as "never executed"
+	FirstRowTime float64 // average time to read the first row
+	Time         float64 // average time to read all rows
+	Rows         float64 // average number of rows returned per loop
+	Loops        int     // number of loops
+
+	Children []*ExplainNode
+}
+
+// ExplainAnalyze runs "EXPLAIN ANALYZE <sql>" and returns the tree
+// of iterators with timing information as it's reported by the server.
+// It's supported by MySQL 8.0.18 and newer.
+// The output can be parsed with ParseExplainAnalyze.
+//
+//	out, _ := conn.ExplainAnalyze("SELECT * FROM dogs WHERE age > 2")
+//	plan, _ := mysqldriver.ParseExplainAnalyze(out)
+//	fmt.Println(plan.Operation, plan.Rows, plan.EstimatedRows)
+func (c *Conn) ExplainAnalyze(sql string) (string, error) {
+	rows, err := c.Query("EXPLAIN ANALYZE " + sql)
+	if err != nil {
+		return "", err
+	}
+
+	var out []string
+	for rows.Next() {
+		out = append(out, rows.String())
+	}
+	if err := rows.LastError(); err != nil {
+		return "", err
+	}
+
+	return strings.Join(out, "\n"), nil
+}
+
+// ParseExplainAnalyze parses the tree format of EXPLAIN ANALYZE
+// output and returns the root iterator. Parsing is best-effort:
+// unknown attributes of iterators are skipped and lines
+// which don't start a new iterator are appended
+// to the operation of the previous one.
+func ParseExplainAnalyze(out string) (*ExplainNode, error) {
+	type entry struct {
+		indent int
+		line   string
+	}
+
+	var entries []entry
+	for _, line := range strings.Split(out, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "->") {
+			entries = append(entries, entry{
+				indent: len(line) - len(trimmed),
+				line:   strings.TrimSpace(trimmed[2:]),
+			})
+		} else if len(entries) > 0 {
+			entries[len(entries)-1].line += " " + strings.TrimSpace(trimmed)
+		} else {
+			return nil, errMalformedExplain
+		}
+	}
+
+	type level struct {
+		indent int
+		node   *ExplainNode
+	}
+
+	var root *ExplainNode
+	var stack []level
+	for _, e := range entries {
+		node := parseExplainLine(e.line)
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= e.indent {
+			stack = stack[:len(stack)-1]
+		}
+
+		if len(stack) == 0 {
+			if root != nil {
+				return nil, errMalformedExplain
+			}
+			root = node
+		} else {
+			parent := stack[len(stack)-1].node
+			parent.Children = append(parent.Children, node)
+		}
+		stack = append(stack, level{indent: e.indent, node: node})
+	}
+
+	if root == nil {
+		return nil, errMalformedExplain
+	}
+	return root, nil
+}
+
+// parseExplainLine parses a single iterator like
+//
+//	Table scan on t  (cost=0.55 rows=3) (actual time=0.03..0.04 rows=3 loops=1)
+func parseExplainLine(line string) *ExplainNode {
+	node := &ExplainNode{}
+
+	for strings.HasSuffix(line, ")") {
+		start := strings.LastIndex(line, " (")
+		if start < 0 {
+			break
+		}
+
+		attrs := line[start+2 : len(line)-1]
+		switch {
+		case attrs == "never executed":
+		case strings.HasPrefix(attrs, "actual "):
+			node.Executed = true
+			parseExplainAttrs(node, strings.TrimPrefix(attrs, "actual "), true)
+		case strings.HasPrefix(attrs, "cost=") || strings.HasPrefix(attrs, "rows="):
+			parseExplainAttrs(node, attrs, false)
+		default:
+			// parentheses are part of the operation, e.g. "Filter: (age > 2)"
+			node.Operation = line
+			return node
+		}
+		line = strings.TrimRight(line[:start], " ")
+	}
+
+	node.Operation = line
+	return node
+}
+
+func parseExplainAttrs(node *ExplainNode, attrs string, actual bool) {
+	for _, attr := range strings.Fields(attrs) {
+		eq := strings.IndexByte(attr, '=')
+		if eq < 0 {
+			continue
+		}
+		key, value := attr[:eq], attr[eq+1:]
+
+		switch key {
+		case "cost":
+			node.EstimatedCost, _ = strconv.ParseFloat(value, 64)
+		case "rows":
+			num, _ := strconv.ParseFloat(value, 64)
+			if actual {
+				node.Rows = num
+			} else {
+				node.EstimatedRows = num
+			}
+		case "time":
+			if dots := strings.Index(value, ".."); dots >= 0 {
+				node.FirstRowTime, _ = strconv.ParseFloat(value[:dots], 64)
+				node.Time, _ = strconv.ParseFloat(value[dots+2:], 64)
+			} else {
+				node.Time, _ = strconv.ParseFloat(value, 64)
+			}
+		case "loops":
+			node.Loops, _ = strconv.Atoi(value)
+		}
+	}
+}
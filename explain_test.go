@@ -0,0 +1,83 @@
+package mysqldriver
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExplainAnalyzeTree(t *testing.T) {
+	out := "-> Nested loop inner join  (cost=2.40 rows=3) (actual time=0.076..0.091 rows=2 loops=1)\n" +
+		"    -> Filter: (p.age > 2)  (cost=0.55 rows=1) (actual time=0.041..0.048 rows=2 loops=1)\n" +
+		"        -> Table scan on p  (cost=0.55 rows=3) (actual time=0.039..0.045 rows=3 loops=1)\n" +
+		"    -> Index lookup on c using PRIMARY (id=p.id)  (cost=0.35 rows=1) (never executed)\n"
+
+	root, err := ParseExplainAnalyze(out)
+	assert.NoError(t, err)
+	assert.Equal(t, root.Operation, "Nested loop inner join")
+	assert.Equal(t, root.EstimatedCost, 2.4)
+	assert.Equal(t, root.EstimatedRows, float64(3))
+	assert.True(t, root.Executed)
+	assert.Equal(t, root.FirstRowTime, 0.076)
+	assert.Equal(t, root.Time, 0.091)
+	assert.Equal(t, root.Rows, float64(2))
+	assert.Equal(t, root.Loops, 1)
+	assert.Len(t, root.Children, 2)
+
+	filter := root.Children[0]
+	assert.Equal(t, filter.Operation, "Filter: (p.age > 2)")
+	assert.Equal(t, filter.EstimatedRows, float64(1))
+	assert.Equal(t, filter.Rows, float64(2))
+	assert.Len(t, filter.Children, 1)
+	assert.Equal(t, filter.Children[0].Operation, "Table scan on p")
+	assert.Equal(t, filter.Children[0].Rows, float64(3))
+
+	lookup := root.Children[1]
+	assert.Equal(t, lookup.Operation, "Index lookup on c using PRIMARY (id=p.id)")
+	assert.Equal(t, lookup.EstimatedCost, 0.35)
+	assert.False(t, lookup.Executed)
+	assert.Len(t, lookup.Children, 0)
+}
+
+func TestParseExplainAnalyzeWithoutEstimates(t *testing.T) {
+	root, err := ParseExplainAnalyze("-> Rows fetched before execution  (actual time=0.000..0.000 rows=1 loops=1)")
+	assert.NoError(t, err)
+	assert.Equal(t, root.Operation, "Rows fetched before execution")
+	assert.Equal(t, root.EstimatedCost, float64(0))
+	assert.Equal(t, root.Rows, float64(1))
+	assert.True(t, root.Executed)
+}
+
+func TestParseExplainAnalyzeMultilineOperation(t *testing.T) {
+	out := "-> Filter: (p.note = 'a\nb')  (cost=0.55 rows=1) (actual time=0.041..0.048 rows=0 loops=1)"
+	root, err := ParseExplainAnalyze(out)
+	assert.NoError(t, err)
+	assert.Equal(t, root.Operation, "Filter: (p.note = 'a b')")
+	assert.Equal(t, root.Loops, 1)
+}
+
+func TestParseExplainAnalyzeMalformed(t *testing.T) {
+	_, err := ParseExplainAnalyze("")
+	assert.Equal(t, err, errMalformedExplain)
+	_, err = ParseExplainAnalyze("id select_type table")
+	assert.Equal(t, err, errMalformedExplain)
+	_, err = ParseExplainAnalyze("-> Table scan on a\n-> Table scan on b")
+	assert.Equal(t, err, errMalformedExplain)
+}
+
+func TestConnExplainAnalyze(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec(`INSERT INTO people(firstname, age) VALUES ("Bob", 3), ("Alice", 5)`)
+		assert.NoError(t, err)
+
+		out, err := conn.ExplainAnalyze("SELECT * FROM people WHERE age > 4")
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(out, "-> "))
+
+		root, err := ParseExplainAnalyze(out)
+		assert.NoError(t, err)
+		assert.True(t, root.Executed)
+		assert.Equal(t, root.Rows, float64(1))
+	})
+}
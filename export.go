@@ -0,0 +1,258 @@
+package mysqldriver
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+)
+
+// ExportKind is the file format used by Export
+type ExportKind int
+
+const (
+	ExportCSV       ExportKind = iota // comma separated values (see RFC 4180)
+	ExportTSV                         // tab separated values with escaped special characters
+	ExportJSONLines                   // JSON object per line (see https://jsonlines.org)
+)
+
+// ExportFormat describes how the result set is written by Export.
+// Values of binary columns are encoded with base64 in all formats.
+type ExportFormat struct {
+	Kind      ExportKind
+	Header    bool   // write column names as the first line. Ignored for JSON lines
+	Delimiter rune   // field delimiter of CSV. Comma is used by default
+	QuoteAll  bool   // quote all CSV fields, not only the ones which require it
+	Null      string // representation of NULL in CSV and TSV. NULL is written as an empty field by default
+}
+
+// Export writes all rows of the result set to the writer.
+// Rows are streamed one by one without buffering the result set.
+//
+//	rows, _ := conn.Query("SELECT id, name FROM dogs")
+//	err := rows.Export(os.Stdout, mysqldriver.ExportFormat{
+//		Kind:   mysqldriver.ExportCSV,
+//		Header: true,
+//		Null:   `\N`,
+//	})
+//
+// Rows are drained even when writing fails, so the connection
+// can be used for the next query.
+func (r *Rows) Export(w io.Writer, format ExportFormat) error {
+	exp := newExporter(w, format, r.Columns())
+
+	var err error
+	if format.Header {
+		err = exp.header()
+	}
+
+	values := make([][]byte, len(r.definitions))
+	nulls := make([]bool, len(r.definitions))
+	for err == nil && r.Next() {
+		for i := range values {
+			values[i], nulls[i] = r.NullBytes()
+		}
+		err = exp.row(values, nulls)
+	}
+
+	if err != nil {
+		r.discard()
+		return err
+	}
+	if err = r.LastError(); err != nil {
+		return err
+	}
+	return exp.w.Flush()
+}
+
+// ExportToFile creates file with the given path
+// and writes all rows of the result set into it (see func (Rows) Export)
+func (r *Rows) ExportToFile(path string, format ExportFormat) error {
+	file, err := os.Create(path)
+	if err != nil {
+		r.discard()
+		return err
+	}
+
+	if err = r.Export(file, format); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+type exporter struct {
+	w       *bufio.Writer
+	format  ExportFormat
+	names   []string
+	binary  []bool
+	numeric []bool
+}
+
+func newExporter(w io.Writer, format ExportFormat, columns []ColumnInfo) *exporter {
+	if format.Delimiter == 0 {
+		format.Delimiter = ','
+	}
+
+	exp := &exporter{
+		w:       bufio.NewWriter(w),
+		format:  format,
+		names:   make([]string, len(columns)),
+		binary:  make([]bool, len(columns)),
+		numeric: make([]bool, len(columns)),
+	}
+	for i, column := range columns {
+		exp.names[i] = column.Name
+		exp.binary[i] = isBinaryColumn(column)
+		exp.numeric[i] = isNumericColumn(column)
+	}
+	return exp
+}
+
+func (e *exporter) header() error {
+	if e.format.Kind == ExportJSONLines {
+		return nil
+	}
+
+	for i, name := range e.names {
+		if i > 0 {
+			e.w.WriteRune(e.delimiter())
+		}
+		e.field(name)
+	}
+	_, err := e.w.WriteString("\n")
+	return err
+}
+
+func (e *exporter) row(values [][]byte, nulls []bool) error {
+	if e.format.Kind == ExportJSONLines {
+		e.jsonRow(values, nulls)
+	} else {
+		for i, value := range values {
+			if i > 0 {
+				e.w.WriteRune(e.delimiter())
+			}
+			if nulls[i] {
+				e.w.WriteString(e.format.Null)
+			} else if e.binary[i] {
+				e.field(base64.StdEncoding.EncodeToString(value))
+			} else {
+				e.field(string(value))
+			}
+		}
+	}
+	_, err := e.w.WriteString("\n")
+	return err
+}
+
+func (e *exporter) delimiter() rune {
+	if e.format.Kind == ExportTSV {
+		return '\t'
+	}
+	return e.format.Delimiter
+}
+
+func (e *exporter) field(value string) {
+	if e.format.Kind == ExportTSV {
+		e.tsvField(value)
+	} else {
+		e.csvField(value)
+	}
+}
+
+func (e *exporter) csvField(value string) {
+	if !e.format.QuoteAll && !e.csvNeedsQuotes(value) {
+		e.w.WriteString(value)
+		return
+	}
+	e.w.WriteByte('"')
+	e.w.WriteString(strings.Replace(value, `"`, `""`, -1))
+	e.w.WriteByte('"')
+}
+
+func (e *exporter) csvNeedsQuotes(value string) bool {
+	if value == "" {
+		// distinguish empty string from NULL
+		return e.format.Null == ""
+	}
+	if value == e.format.Null || value[0] == ' ' || value[0] == '\t' {
+		return true
+	}
+	return strings.ContainsRune(value, e.format.Delimiter) || strings.ContainsAny(value, "\"\r\n")
+}
+
+// tsvField escapes special characters the same way
+// as SELECT ... INTO OUTFILE does it by default
+func (e *exporter) tsvField(value string) {
+	for i := 0; i < len(value); i++ {
+		switch ch := value[i]; ch {
+		case '\t':
+			e.w.WriteString(`\t`)
+		case '\n':
+			e.w.WriteString(`\n`)
+		case '\r':
+			e.w.WriteString(`\r`)
+		case '\\':
+			e.w.WriteString(`\\`)
+		case 0:
+			e.w.WriteString(`\0`)
+		default:
+			e.w.WriteByte(ch)
+		}
+	}
+}
+
+func (e *exporter) jsonRow(values [][]byte, nulls []bool) {
+	e.w.WriteByte('{')
+	for i, value := range values {
+		if i > 0 {
+			e.w.WriteByte(',')
+		}
+		e.jsonString(e.names[i])
+		e.w.WriteByte(':')
+		switch {
+		case nulls[i]:
+			e.w.WriteString("null")
+		case e.binary[i]:
+			e.jsonString(base64.StdEncoding.EncodeToString(value))
+		case e.numeric[i] && json.Valid(value):
+			e.w.Write(value)
+		default:
+			e.jsonString(string(value))
+		}
+	}
+	e.w.WriteByte('}')
+}
+
+func (e *exporter) jsonString(value string) {
+	// invalid UTF-8 is replaced with U+FFFD
+	data, _ := json.Marshal(value)
+	e.w.Write(data)
+}
+
+// binaryCharacterSet is the ID of the "binary" collation
+// used by BINARY, VARBINARY and BLOB columns
+const binaryCharacterSet = 63
+
+func isBinaryColumn(column ColumnInfo) bool {
+	if column.CharacterSet != binaryCharacterSet {
+		return false
+	}
+	switch column.Type {
+	case fieldTypeVarChar, fieldTypeVarString, fieldTypeString, fieldTypeTinyBLOB,
+		fieldTypeMediumBLOB, fieldTypeLongBLOB, fieldTypeBLOB, fieldTypeBit, fieldTypeGeometry:
+		return true
+	}
+	return false
+}
+
+func isNumericColumn(column ColumnInfo) bool {
+	switch column.Type {
+	case fieldTypeTiny, fieldTypeShort, fieldTypeInt24, fieldTypeLong, fieldTypeLongLong,
+		fieldTypeFloat, fieldTypeDouble, fieldTypeDecimal, fieldTypeNewDecimal, fieldTypeYear:
+		return true
+	}
+	return false
+}
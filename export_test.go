@@ -0,0 +1,95 @@
+package mysqldriver
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var exportColumns = []ColumnInfo{
+	{Name: "id", Type: fieldTypeLong, CharacterSet: binaryCharacterSet},
+	{Name: "name", Type: fieldTypeVarString, CharacterSet: 33},
+	{Name: "avatar", Type: fieldTypeBLOB, CharacterSet: binaryCharacterSet},
+}
+
+func exportRows(format ExportFormat, header bool, rows ...[]interface{}) string {
+	buf := &bytes.Buffer{}
+	exp := newExporter(buf, format, exportColumns)
+	if header {
+		exp.header()
+	}
+	for _, row := range rows {
+		values := make([][]byte, len(row))
+		nulls := make([]bool, len(row))
+		for i, value := range row {
+			if value == nil {
+				nulls[i] = true
+			} else {
+				values[i] = []byte(value.(string))
+			}
+		}
+		exp.row(values, nulls)
+	}
+	exp.w.Flush()
+	return buf.String()
+}
+
+func TestExportCSV(t *testing.T) {
+	out := exportRows(ExportFormat{Kind: ExportCSV}, true,
+		[]interface{}{"1", `Bob "the dog", Jr.`, "\x00\x01"},
+		[]interface{}{"2", "", nil},
+		[]interface{}{"3", nil, "a"},
+	)
+	assert.Equal(t, out, "id,name,avatar\n"+
+		"1,\"Bob \"\"the dog\"\", Jr.\",AAE=\n"+
+		"2,\"\",\n"+
+		"3,,YQ==\n")
+}
+
+func TestExportCSVWithOptions(t *testing.T) {
+	out := exportRows(ExportFormat{Kind: ExportCSV, Delimiter: ';', QuoteAll: true, Null: "NULL"}, false,
+		[]interface{}{"1", "a;b", nil},
+		[]interface{}{"2", "NULL", nil},
+	)
+	assert.Equal(t, out, "\"1\";\"a;b\";NULL\n\"2\";\"NULL\";NULL\n")
+}
+
+func TestExportTSV(t *testing.T) {
+	out := exportRows(ExportFormat{Kind: ExportTSV, Header: true, Null: `\N`}, true,
+		[]interface{}{"1", "tab\there\nand\\", nil},
+	)
+	assert.Equal(t, out, "id\tname\tavatar\n1\ttab\\there\\nand\\\\\t\\N\n")
+}
+
+func TestExportJSONLines(t *testing.T) {
+	out := exportRows(ExportFormat{Kind: ExportJSONLines}, true,
+		[]interface{}{"1", "Bob \"the dog\"", "\xff"},
+		[]interface{}{"2", nil, nil},
+	)
+	assert.Equal(t, out, `{"id":1,"name":"Bob \"the dog\"","avatar":"/w=="}`+"\n"+
+		`{"id":2,"name":null,"avatar":null}`+"\n")
+}
+
+func TestRowsExportToFile(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec(`INSERT INTO people(firstname, age) VALUES ("Bob", 3), (NULL, 5)`)
+		assert.NoError(t, err)
+
+		dir, err := ioutil.TempDir("", "mysqldriver")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "people.csv")
+
+		rows, err := conn.Query("SELECT firstname, age FROM people ORDER BY id")
+		assert.NoError(t, err)
+		assert.NoError(t, rows.ExportToFile(path, ExportFormat{Kind: ExportCSV, Header: true}))
+
+		data, err := ioutil.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, string(data), "firstname,age\nBob,3\n,5\n")
+	})
+}
@@ -0,0 +1,46 @@
+package mysqldriver
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var errMalformedGeometry = errors.New("mysqldriver: geometry value is shorter than SRID")
+
+// Geometry returns value of the spatial column as SRID
+// and geometry in Well-Known Binary format. MySQL stores
+// geometry as 4 bytes of SRID followed by WKB.
+// NULL value is represented as 0 and nil.
+// WKB references the buffer of the row like Bytes does,
+// so it must be copied to keep it after the next call of Next.
+//
+// To read geometry as GeoJSON instead, select it with
+// ST_AsGeoJSON function (see func (Rows) GeoJSON).
+func (r *Rows) Geometry() (uint32, []byte) {
+	value, null := r.NullBytes()
+	if null {
+		return 0, nil
+	}
+
+	if len(value) < 4 {
+		r.errParse = errMalformedGeometry
+		return 0, nil
+	}
+	return binary.LittleEndian.Uint32(value), value[4:]
+}
+
+// GeoJSON unmarshals the column selected with ST_AsGeoJSON
+// into dest the same way as JSON does
+//
+//	rows, _ := conn.Query("SELECT id, ST_AsGeoJSON(location) FROM shops")
+//	for rows.Next() {
+//		var point struct {
+//			Type        string     `json:"type"`
+//			Coordinates [2]float64 `json:"coordinates"`
+//		}
+//		id := rows.Int()
+//		err := rows.GeoJSON(&point)
+//	}
+func (r *Rows) GeoJSON(dest interface{}) error {
+	return r.JSON(dest)
+}
@@ -0,0 +1,86 @@
+package mysqldriver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type geoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+func TestRowsGeoJSON(t *testing.T) {
+	point := `{"type": "Point", "coordinates": [1.5, 2.0]}`
+	payload := append([]byte{byte(len(point))}, point...)
+	conn := newPacketConn(
+		[]byte{0x01},
+		columnDefinition("location"),
+		eofPacket,
+		payload,
+		[]byte{0xfb},
+		[]byte{0x01, '{'},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT ST_AsGeoJSON(location) FROM shops")
+	assert.NoError(t, err)
+
+	var value geoJSONPoint
+	assert.True(t, rows.Next())
+	assert.NoError(t, rows.GeoJSON(&value))
+	assert.Equal(t, value, geoJSONPoint{Type: "Point", Coordinates: [2]float64{1.5, 2}})
+
+	assert.True(t, rows.Next())
+	assert.NoError(t, rows.GeoJSON(&value))
+	assert.Equal(t, value.Type, "Point")
+
+	assert.True(t, rows.Next())
+	err = rows.GeoJSON(&value)
+	assert.Error(t, err)
+	assert.False(t, rows.Next())
+	assert.Equal(t, rows.LastError(), err)
+}
+
+func TestRowsGeometry(t *testing.T) {
+	wkb := []byte{0x01, 0x01, 0x00, 0x00, 0x00} // point header
+	value := append([]byte{0xe6, 0x10, 0x00, 0x00}, wkb...)
+	conn := newPacketConn(
+		[]byte{0x01},
+		columnDefinition("location"),
+		eofPacket,
+		append([]byte{byte(len(value))}, value...),
+		[]byte{0x02, 0x00, 0x00},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT location FROM shops")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	srid, geometry := rows.Geometry()
+	assert.Equal(t, srid, uint32(4326))
+	assert.Equal(t, geometry, wkb)
+
+	assert.True(t, rows.Next())
+	rows.Geometry()
+	assert.False(t, rows.Next())
+	assert.Equal(t, rows.LastError(), errMalformedGeometry)
+}
+
+func TestQueryGeoJSON(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		rows, err := conn.Query("SELECT ST_AsGeoJSON(ST_GeomFromText('POINT(1 2)')), ST_GeomFromText('POINT(1 2)', 4326)")
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+
+		var point geoJSONPoint
+		assert.NoError(t, rows.GeoJSON(&point))
+		assert.Equal(t, point, geoJSONPoint{Type: "Point", Coordinates: [2]float64{1, 2}})
+		srid, wkb := rows.Geometry()
+		assert.Equal(t, srid, uint32(4326))
+		assert.Equal(t, len(wkb), 21)
+		assert.False(t, rows.Next())
+		assert.NoError(t, rows.LastError())
+	})
+}
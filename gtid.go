@@ -0,0 +1,123 @@
+package mysqldriver
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrConsistencyTimeout is returned by WaitForConsistencyToken when
+// the server hasn't applied transactions of the token within the timeout
+var ErrConsistencyTimeout = errors.New("mysqldriver: consistency token isn't applied within the timeout")
+
+const (
+	serverSessionStateChanged uint16 = 0x4000 // SERVER_SESSION_STATE_CHANGED
+	sessionTrackGTIDs         byte   = 0x03   // SESSION_TRACK_GTIDS
+)
+
+// ConsistencyToken returns GTID set of the transactions committed by
+// the connection as it's reported in the session state of OK_PACKET.
+// Server reports it only when session_track_gtids is enabled, e.g.
+// by Options.TrackGTIDs. The token is kept until the server reports
+// another one. Empty value means the token hasn't been reported.
+//
+// The token can be passed to WaitForConsistencyToken of another
+// connection, e.g. to the replica of Group Replication cluster,
+// to read the data written by this connection (causal reads).
+//
+//	primary.Exec("INSERT INTO dogs(name) VALUES ('Max')")
+//	token := primary.ConsistencyToken()
+//	if err := replica.WaitForConsistencyToken(token, time.Second); err != nil {
+//		// replica is lagging behind, read from the primary
+//	}
+//	rows, _ := replica.Query("SELECT name FROM dogs")
+func (c *Conn) ConsistencyToken() string {
+	return c.consistencyToken
+}
+
+// WaitForConsistencyToken waits until the server applies all transactions
+// of the token returned by ConsistencyToken of another connection.
+// It returns ErrConsistencyTimeout when the timeout is exceeded.
+// Zero timeout means waiting without limit.
+// It's supported by MySQL 5.7.5 and newer.
+func (c *Conn) WaitForConsistencyToken(token string, timeout time.Duration) error {
+	if token == "" {
+		return nil
+	}
+
+	sql, err := c.interpolate("SELECT WAIT_FOR_EXECUTED_GTID_SET(?, ?)", []interface{}{token, timeout.Seconds()})
+	if err != nil {
+		return err
+	}
+
+	rows, err := c.Query(sql)
+	if err != nil {
+		return err
+	}
+	var timedOut bool
+	for rows.Next() {
+		timedOut = rows.Int() == 1
+	}
+	if err := rows.LastError(); err != nil {
+		return err
+	}
+	if timedOut {
+		return ErrConsistencyTimeout
+	}
+	return nil
+}
+
+// trackSessionState reads GTID set from the session state
+// of OK_PACKET (see https://dev.mysql.com/doc/internals/en/packet-OK_Packet.html).
+// Malformed session state is ignored as it isn't required
+// to read the result of the statement.
+func (c *Conn) trackSessionState(payload []byte) {
+	if token, ok := parseGTIDs(payload); ok {
+		c.consistencyToken = token
+	}
+}
+
+func parseGTIDs(payload []byte) (string, bool) {
+	// header(1), affected_rows, last_insert_id
+	offset := 1
+	var err error
+	for i := 0; i < 2; i++ {
+		if _, offset, err = readLengthEncodedInteger(payload, offset); err != nil {
+			return "", false
+		}
+	}
+
+	status, offset, err := readUint16(payload, offset)
+	if err != nil || status&serverSessionStateChanged == 0 {
+		return "", false
+	}
+	offset += 2 // warnings
+
+	// info isn't required when the session state follows it
+	if _, offset, err = readLengthEncodedString(payload, offset); err != nil {
+		return "", false
+	}
+	changes, _, err := readLengthEncodedString(payload, offset)
+	if err != nil {
+		return "", false
+	}
+
+	for pos := 0; pos < len(changes); {
+		kind := changes[pos]
+		data, next, err := readLengthEncodedString(changes, pos+1)
+		if err != nil {
+			return "", false
+		}
+		pos = next
+
+		if kind != sessionTrackGTIDs {
+			continue
+		}
+		// encoding specification(1), GTID set
+		gtids, _, err := readLengthEncodedString(data, 1)
+		if err != nil {
+			return "", false
+		}
+		return string(gtids), true
+	}
+	return "", false
+}
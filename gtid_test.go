@@ -0,0 +1,69 @@
+package mysqldriver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// okPacketWithGTIDs returns OK_PACKET with SESSION_TRACK_GTIDS
+// preceded by another session state change
+func okPacketWithGTIDs(gtids string) []byte {
+	data := append([]byte{0x00, byte(len(gtids))}, gtids...)
+	changes := []byte{0x00, 0x04, 0x03, 'a', 'b', 'c'} // SESSION_TRACK_SYSTEM_VARIABLES
+	changes = append(changes, sessionTrackGTIDs, byte(len(data)))
+	changes = append(changes, data...)
+
+	payload := []byte{mysqlproto.OK_PACKET, 0x01, 0x00, 0x02, 0x40, 0x00, 0x00, 0x00}
+	payload = append(payload, byte(len(changes)))
+	return append(payload, changes...)
+}
+
+func TestParseGTIDs(t *testing.T) {
+	gtids, ok := parseGTIDs(okPacketWithGTIDs("3e11fa47-71ca-11e1-9e33-c80aa9429562:23"))
+	assert.True(t, ok)
+	assert.Equal(t, gtids, "3e11fa47-71ca-11e1-9e33-c80aa9429562:23")
+}
+
+func TestParseGTIDsWithoutSessionState(t *testing.T) {
+	_, ok := parseGTIDs([]byte{mysqlproto.OK_PACKET, 0x01, 0x00, 0x02, 0x00, 0x00, 0x00})
+	assert.False(t, ok)
+}
+
+func TestParseGTIDsOfMalformedSessionState(t *testing.T) {
+	payload := okPacketWithGTIDs("uuid:1")
+	_, ok := parseGTIDs(payload[:len(payload)-3])
+	assert.False(t, ok)
+}
+
+func TestConnConsistencyTokenIsKeptUntilReplaced(t *testing.T) {
+	conn := newPacketConn(
+		okPacketWithGTIDs("uuid:1-5"),
+		[]byte{mysqlproto.OK_PACKET, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00},
+	)
+	assert.Equal(t, conn.ConsistencyToken(), "")
+
+	_, err := conn.Exec("INSERT INTO dogs(name) VALUES ('Max')")
+	assert.NoError(t, err)
+	assert.Equal(t, conn.ConsistencyToken(), "uuid:1-5")
+
+	_, err = conn.Exec("SET @a = 1")
+	assert.NoError(t, err)
+	assert.Equal(t, conn.ConsistencyToken(), "uuid:1-5")
+}
+
+func TestWaitForConsistencyToken(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		rows, err := conn.Query("SELECT @@GLOBAL.gtid_executed")
+		assert.NoError(t, err)
+		var executed string
+		for rows.Next() {
+			executed = rows.String()
+		}
+		assert.NoError(t, rows.LastError())
+
+		assert.NoError(t, conn.WaitForConsistencyToken(executed, time.Second))
+	})
+}
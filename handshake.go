@@ -0,0 +1,177 @@
+package mysqldriver
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Capabilities is a bitmask of the capability flags
+// (see https://dev.mysql.com/doc/dev/mysql-server/latest/group__group__cs__capabilities__flags.html)
+type Capabilities uint32
+
+const (
+	CapabilityLongPassword               Capabilities = 1 << 0
+	CapabilityFoundRows                  Capabilities = 1 << 1
+	CapabilityLongFlag                   Capabilities = 1 << 2
+	CapabilityConnectWithDB              Capabilities = 1 << 3
+	CapabilityNoSchema                   Capabilities = 1 << 4
+	CapabilityCompress                   Capabilities = 1 << 5
+	CapabilityODBC                       Capabilities = 1 << 6
+	CapabilityLocalFiles                 Capabilities = 1 << 7
+	CapabilityIgnoreSpace                Capabilities = 1 << 8
+	CapabilityProtocol41                 Capabilities = 1 << 9
+	CapabilityInteractive                Capabilities = 1 << 10
+	CapabilitySSL                        Capabilities = 1 << 11
+	CapabilityIgnoreSigpipe              Capabilities = 1 << 12
+	CapabilityTransactions               Capabilities = 1 << 13
+	CapabilityReserved                   Capabilities = 1 << 14
+	CapabilitySecureConnection           Capabilities = 1 << 15
+	CapabilityMultiStatements            Capabilities = 1 << 16
+	CapabilityMultiResults               Capabilities = 1 << 17
+	CapabilityPSMultiResults             Capabilities = 1 << 18
+	CapabilityPluginAuth                 Capabilities = 1 << 19
+	CapabilityConnectAttrs               Capabilities = 1 << 20
+	CapabilityPluginAuthLenencClientData Capabilities = 1 << 21
+	CapabilityCanHandleExpiredPasswords  Capabilities = 1 << 22
+	CapabilitySessionTrack               Capabilities = 1 << 23
+	CapabilityDeprecateEOF               Capabilities = 1 << 24
+	CapabilityOptionalResultsetMetadata  Capabilities = 1 << 25
+	CapabilityZstdCompression            Capabilities = 1 << 26
+	CapabilityQueryAttributes            Capabilities = 1 << 27
+	CapabilityMultiFactorAuthentication  Capabilities = 1 << 28
+)
+
+// MariaDB extended capabilities are sent instead of the last 4 bytes
+// of the reserved fields of the handshake packets when the server
+// doesn't set CapabilityLongPassword, known as CLIENT_MYSQL by MariaDB
+// (see https://mariadb.com/kb/en/connection/#initial-handshake-packet)
+const (
+	mariaDBExtendedMetadata uint32 = 1 << 3 // MARIADB_CLIENT_EXTENDED_METADATA
+
+	// mariaDBClientCapabilities are extended capabilities used by the driver
+	mariaDBClientCapabilities = mariaDBExtendedMetadata
+)
+
+// Has reports whether all given flags are set
+func (c Capabilities) Has(flag Capabilities) bool {
+	return c&flag == flag
+}
+
+// HandshakeInfo contains information sent by the server
+// in the initial handshake packet
+// (see https://dev.mysql.com/doc/internals/en/connection-phase-packets.html#packet-Protocol::HandshakeV10)
+type HandshakeInfo struct {
+	ProtocolVersion byte
+	ServerVersion   string       // e.g. "8.0.22"
+	ConnectionID    uint32       // thread ID of the connection
+	Capabilities    Capabilities // capabilities of the server
+	Collation       byte         // default collation ID of the server
+	StatusFlags     uint16       // initial status of the server
+	AuthPlugin      string       // default authentication plugin, e.g. "caching_sha2_password"
+	Scramble        []byte       // auth plugin data, random seed of the session
+
+	// MariaDBCapabilities are extended capabilities of MariaDB server,
+	// it's zero for MySQL server
+	MariaDBCapabilities uint32
+}
+
+// HandshakeInfo returns information about the server
+// received during establishing of the connection.
+// Capabilities used by the connection are the intersection of
+// the server capabilities and the capabilities of the driver
+// (see func (Conn) Capabilities).
+func (c *Conn) HandshakeInfo() HandshakeInfo {
+	return c.handshake
+}
+
+// ServerVersion returns the version of the server
+// sent in the initial handshake, e.g. "8.0.22" or "10.5.8-MariaDB"
+func (c *Conn) ServerVersion() string {
+	return c.handshake.ServerVersion
+}
+
+// ThreadID returns the connection ID assigned by the server. It's
+// the same as CONNECTION_ID() of the session and the ID used by
+// KILL statement and by the Id column of SHOW PROCESSLIST.
+func (c *Conn) ThreadID() uint32 {
+	return c.handshake.ConnectionID
+}
+
+// HandshakePacket returns payload of the initial handshake packet
+// as it's received from the server. Together with HandshakeInfo
+// it allows recording of the session, e.g. to replay
+// the handshake deterministically by a mock server
+// (see mysqltest.WriteHandshake).
+func (c *Conn) HandshakePacket() []byte {
+	return append([]byte(nil), c.handshakePacket...)
+}
+
+// Capabilities returns capabilities used by the connection
+func (c *Conn) Capabilities() Capabilities {
+	return Capabilities(c.conn.CapabilityFlags)
+}
+
+// parseHandshake parses Protocol::HandshakeV10 payload
+func parseHandshake(payload []byte) (HandshakeInfo, error) {
+	var info HandshakeInfo
+	if len(payload) < 1 {
+		return info, errMalformedPacket
+	}
+	info.ProtocolVersion = payload[0]
+
+	end := bytes.IndexByte(payload[1:], 0)
+	if end < 0 {
+		return info, errMalformedPacket
+	}
+	info.ServerVersion = string(payload[1 : 1+end])
+	offset := 1 + end + 1
+
+	// connection_id(4), auth_plugin_data_part_1(8), filler(1), capability_flags_1(2)
+	if offset+15 > len(payload) {
+		return info, errMalformedPacket
+	}
+	info.ConnectionID = binary.LittleEndian.Uint32(payload[offset:])
+	info.Scramble = append([]byte(nil), payload[offset+4:offset+12]...)
+	info.Capabilities = Capabilities(binary.LittleEndian.Uint16(payload[offset+13:]))
+	offset += 15
+
+	// character_set(1), status_flags(2), capability_flags_2(2), auth_plugin_data_len(1), reserved(10)
+	if offset == len(payload) {
+		return info, nil
+	}
+	if offset+16 > len(payload) {
+		return info, errMalformedPacket
+	}
+	info.Collation = payload[offset]
+	info.StatusFlags = binary.LittleEndian.Uint16(payload[offset+1:])
+	info.Capabilities |= Capabilities(binary.LittleEndian.Uint16(payload[offset+3:])) << 16
+	authDataLen := int(payload[offset+5])
+	if !info.Capabilities.Has(CapabilityLongPassword) {
+		info.MariaDBCapabilities = binary.LittleEndian.Uint32(payload[offset+12:])
+	}
+	offset += 16
+
+	if info.Capabilities.Has(CapabilitySecureConnection) {
+		// auth_plugin_data_part_2 is at least 13 bytes
+		length := authDataLen - 8
+		if length < 13 {
+			length = 13
+		}
+		if offset+length > len(payload) {
+			return info, errMalformedPacket
+		}
+		// the last byte of the seed is a NUL terminator
+		info.Scramble = append(info.Scramble, bytes.TrimRight(payload[offset:offset+length], "\x00")...)
+		offset += length
+	}
+
+	if info.Capabilities.Has(CapabilityPluginAuth) && offset < len(payload) {
+		name := payload[offset:]
+		if end := bytes.IndexByte(name, 0); end >= 0 {
+			name = name[:end]
+		}
+		info.AuthPlugin = string(name)
+	}
+
+	return info, nil
+}
@@ -0,0 +1,139 @@
+package mysqldriver
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var handshakePayload = []byte{
+	0x0a,                               // protocol version
+	'8', '.', '0', '.', '2', '2', 0x00, // server version
+	0x15, 0x00, 0x00, 0x00, // connection id
+	0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, // auth-plugin-data-part-1
+	0x00,       // filler
+	0xff, 0xff, // capability flags (lower)
+	0xff,       // character set
+	0x02, 0x00, // status flags
+	0xff, 0xdd, // capability flags (upper)
+	0x15,                                                       // auth plugin data length
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // reserved
+	0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x00, // auth-plugin-data-part-2
+	'c', 'a', 'c', 'h', 'i', 'n', 'g', '_', 's', 'h', 'a', '2', '_', 'p', 'a', 's', 's', 'w', 'o', 'r', 'd', 0x00,
+}
+
+func TestParseHandshake(t *testing.T) {
+	info, err := parseHandshake(handshakePayload)
+	assert.NoError(t, err)
+	assert.Equal(t, info, HandshakeInfo{
+		ProtocolVersion: 10,
+		ServerVersion:   "8.0.22",
+		ConnectionID:    21,
+		Capabilities:    Capabilities(0xddffffff),
+		Collation:       255,
+		StatusFlags:     2,
+		AuthPlugin:      "caching_sha2_password",
+		Scramble: []byte{
+			0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+			0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c,
+		},
+	})
+	assert.True(t, info.Capabilities.Has(CapabilityPluginAuth|CapabilitySessionTrack))
+	assert.False(t, info.Capabilities.Has(CapabilityOptionalResultsetMetadata))
+}
+
+// handshake of MariaDB 10.7 which doesn't set CLIENT_MYSQL
+// and sends extended capabilities in the reserved bytes
+var mariaDBHandshakePayload = []byte{
+	0x0a, // protocol version
+	'5', '.', '5', '.', '5', '-', '1', '0', '.', '7', '.', '3', '-', 'M', 'a', 'r', 'i', 'a', 'D', 'B', 0x00,
+	0x04, 0x00, 0x00, 0x00, // connection id
+	0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, // auth-plugin-data-part-1
+	0x00,       // filler
+	0xfe, 0xf7, // capability flags (lower)
+	0x2d,       // character set
+	0x02, 0x00, // status flags
+	0xff, 0x81, // capability flags (upper)
+	0x15,                               // auth plugin data length
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // reserved
+	0x1d, 0x00, 0x00, 0x00, // MariaDB extended capabilities
+	0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x00, // auth-plugin-data-part-2
+	'm', 'y', 's', 'q', 'l', '_', 'n', 'a', 't', 'i', 'v', 'e', '_', 'p', 'a', 's', 's', 'w', 'o', 'r', 'd', 0x00,
+}
+
+func TestParseHandshakeOfMariaDB(t *testing.T) {
+	info, err := parseHandshake(mariaDBHandshakePayload)
+	assert.NoError(t, err)
+	assert.Equal(t, info.ServerVersion, "5.5.5-10.7.3-MariaDB")
+	assert.False(t, info.Capabilities.Has(CapabilityLongPassword))
+	assert.Equal(t, info.MariaDBCapabilities, uint32(0x1d))
+	assert.Equal(t, info.AuthPlugin, "mysql_native_password")
+
+	// MySQL sets CLIENT_MYSQL and the bytes are reserved
+	info, err = parseHandshake(handshakePayload)
+	assert.NoError(t, err)
+	assert.Equal(t, info.MariaDBCapabilities, uint32(0))
+}
+
+func TestParseHandshakeMalformed(t *testing.T) {
+	_, err := parseHandshake(nil)
+	assert.Equal(t, err, errMalformedPacket)
+	_, err = parseHandshake(handshakePayload[:5])
+	assert.Equal(t, err, errMalformedPacket)
+	_, err = parseHandshake(handshakePayload[:30])
+	assert.Equal(t, err, errMalformedPacket)
+}
+
+type readRecorder struct {
+	stream
+	data *bytes.Reader
+}
+
+func (r *readRecorder) Read(b []byte) (int, error) {
+	if len(b) > 3 {
+		b = b[:3] // read by small chunks
+	}
+	return r.data.Read(b)
+}
+
+func TestNetConnCapturesFirstPacket(t *testing.T) {
+	data := []byte{0x02, 0x00, 0x00, 0x00, 0x0a, 0x0b, 0x01, 0x00, 0x00, 0x01, 0x0c}
+	conn := &netConn{Conn: &readRecorder{data: bytes.NewReader(data)}, capture: true}
+
+	buf := make([]byte, 16)
+	conn.Read(buf)
+	assert.Nil(t, conn.firstPacket())
+	conn.Read(buf)
+	assert.Equal(t, conn.firstPacket(), []byte{0x0a, 0x0b})
+	conn.Read(buf)
+	assert.Equal(t, conn.firstPacket(), []byte{0x0a, 0x0b})
+}
+
+func TestConnServerVersionAndThreadID(t *testing.T) {
+	info, err := parseHandshake(handshakePayload)
+	assert.NoError(t, err)
+	conn := &Conn{handshake: info}
+	assert.Equal(t, conn.ServerVersion(), "8.0.22")
+	assert.Equal(t, conn.ThreadID(), uint32(21))
+}
+
+func TestConnHandshakeInfo(t *testing.T) {
+	conn, err := NewConn("root", "", "tcp", "127.0.0.1:3306", "test", time.Duration(0))
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	info := conn.HandshakeInfo()
+	assert.Equal(t, info.ProtocolVersion, byte(10))
+	assert.NotEmpty(t, info.ServerVersion)
+	assert.True(t, info.Capabilities.Has(CapabilityProtocol41))
+	assert.True(t, conn.Capabilities().Has(CapabilityProtocol41))
+	assert.Equal(t, conn.ServerVersion(), info.ServerVersion)
+
+	rows, err := conn.Query("SELECT CONNECTION_ID()")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Int64(), int64(conn.ThreadID()))
+	assert.False(t, rows.Next())
+}
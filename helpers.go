@@ -1,6 +1,7 @@
 package mysqldriver
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/pubnative/mysqlproto-go"
@@ -21,3 +22,51 @@ func handleOK(payload []byte, capabilityFlags uint32) error {
 
 	return fmt.Errorf("mysqldriver: unknown error occured. Payload: %x", payload)
 }
+
+func parseExecResult(payload []byte, capabilityFlags uint32) (mysqlproto.OKPacket, error) {
+	if payload[0] == mysqlproto.OK_PACKET {
+		pkt, err := mysqlproto.ParseOKPacket(payload, capabilityFlags)
+		return pkt, err
+	} else {
+		pkt, err := mysqlproto.ParseERRPacket(payload, capabilityFlags)
+		if err == nil {
+			return mysqlproto.OKPacket{}, pkt
+		} else {
+			return mysqlproto.OKPacket{}, err
+		}
+	}
+}
+
+// trimZeroFraction removes fractional part of the number
+// when it consists only of zeros, so DECIMAL values like "42.0000"
+// can be read as integers. Numbers with non-zero fractional part
+// are returned as they are, so parsing them as integers fails.
+func trimZeroFraction(num []byte) []byte {
+	dot := bytes.IndexByte(num, '.')
+	if dot < 0 {
+		return num
+	}
+	for _, ch := range num[dot+1:] {
+		if ch != '0' {
+			return num
+		}
+	}
+	return num[:dot]
+}
+
+// stripGrouping removes thousands separators of the number
+// like "1,234.56" when StripGrouping of the connection is set.
+// Value isn't modified because it references the packet.
+func (c *Conn) stripGrouping(num []byte) []byte {
+	if c == nil || !c.StripGrouping || bytes.IndexByte(num, ',') < 0 {
+		return num
+	}
+
+	stripped := make([]byte, 0, len(num))
+	for _, ch := range num {
+		if ch != ',' {
+			stripped = append(stripped, ch)
+		}
+	}
+	return stripped
+}
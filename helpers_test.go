@@ -32,3 +32,23 @@ func TestHandleOKPacketContainsBrokenErrorPayout(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Equal(t, err.Error(), "mysqldriver: unknown error occured. Payload: 01")
 }
+
+func TestTrimZeroFraction(t *testing.T) {
+	assert.Equal(t, trimZeroFraction([]byte("42")), []byte("42"))
+	assert.Equal(t, trimZeroFraction([]byte("42.0000")), []byte("42"))
+	assert.Equal(t, trimZeroFraction([]byte("-42.0")), []byte("-42"))
+	assert.Equal(t, trimZeroFraction([]byte("42.")), []byte("42"))
+	assert.Equal(t, trimZeroFraction([]byte("42.0100")), []byte("42.0100"))
+	assert.Equal(t, trimZeroFraction([]byte("4.5")), []byte("4.5"))
+}
+
+func TestConnStripGrouping(t *testing.T) {
+	conn := &Conn{}
+	assert.Equal(t, conn.stripGrouping([]byte("1,234.56")), []byte("1,234.56"))
+
+	conn.StripGrouping = true
+	value := []byte("-1,234,567.89")
+	assert.Equal(t, conn.stripGrouping(value), []byte("-1234567.89"))
+	assert.Equal(t, value, []byte("-1,234,567.89"))
+	assert.Equal(t, conn.stripGrouping([]byte("42")), []byte("42"))
+}
@@ -0,0 +1,65 @@
+package mysqldriver
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrHintsNotSupported is returned by QueryWithHints when statement
+// doesn't start with SELECT, INSERT, REPLACE, UPDATE or DELETE keyword
+var ErrHintsNotSupported = errors.New("mysqldriver: optimizer hints can follow only SELECT, INSERT, REPLACE, UPDATE or DELETE keyword")
+
+// ErrMalformedHint is returned by QueryWithHints when hint
+// contains "*/" which terminates the comment of hints
+var ErrMalformedHint = errors.New("mysqldriver: optimizer hint can't contain */")
+
+var hintStatements = []string{"SELECT", "INSERT", "REPLACE", "UPDATE", "DELETE"}
+
+// QueryWithHints performs the query the same way as Query does
+// with optimizer hints placed right after the leading keyword
+// of the statement (see https://dev.mysql.com/doc/refman/8.0/en/optimizer-hints.html)
+//
+//	// SELECT /*+ MAX_EXECUTION_TIME(1000) INDEX(dogs idx_age) */ name FROM dogs WHERE age > 2
+//	rows, err := conn.QueryWithHints("SELECT name FROM dogs WHERE age > 2",
+//		"MAX_EXECUTION_TIME(1000)", "INDEX(dogs idx_age)")
+func (c *Conn) QueryWithHints(sql string, hints ...string) (*Rows, error) {
+	sql, err := withHints(sql, hints)
+	if err != nil {
+		return nil, err
+	}
+	return c.Query(sql)
+}
+
+// withHints inserts comment of optimizer hints after the leading keyword
+func withHints(sql string, hints []string) (string, error) {
+	for _, hint := range hints {
+		if strings.Contains(hint, "*/") {
+			return "", ErrMalformedHint
+		}
+	}
+
+	start := len(sql) - len(strings.TrimLeft(sql, " \t\r\n("))
+	for _, keyword := range hintStatements {
+		end := start + len(keyword)
+		if len(sql) < end || !strings.EqualFold(sql[start:end], keyword) {
+			continue
+		}
+		if len(sql) > end && !isHintDelimiter(sql[end]) {
+			continue
+		}
+		if len(hints) == 0 {
+			return sql, nil
+		}
+		return sql[:end] + " /*+ " + strings.Join(hints, " ") + " */" + sql[end:], nil
+	}
+
+	return "", ErrHintsNotSupported
+}
+
+func isHintDelimiter(ch byte) bool {
+	switch ch {
+	case ' ', '\t', '\r', '\n', '(', '*', '`', '\'', '"':
+		return true
+	}
+	return false
+}
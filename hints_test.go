@@ -0,0 +1,49 @@
+package mysqldriver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithHints(t *testing.T) {
+	sql, err := withHints("SELECT name FROM dogs", []string{"MAX_EXECUTION_TIME(1000)", "INDEX(dogs idx_age)"})
+	assert.NoError(t, err)
+	assert.Equal(t, sql, "SELECT /*+ MAX_EXECUTION_TIME(1000) INDEX(dogs idx_age) */ name FROM dogs")
+
+	sql, err = withHints("  (select * FROM dogs)", []string{"NO_ICP(dogs)"})
+	assert.NoError(t, err)
+	assert.Equal(t, sql, "  (select /*+ NO_ICP(dogs) */ * FROM dogs)")
+
+	sql, err = withHints("DELETE FROM dogs", []string{"JOIN_ORDER(a, b)"})
+	assert.NoError(t, err)
+	assert.Equal(t, sql, "DELETE /*+ JOIN_ORDER(a, b) */ FROM dogs")
+
+	sql, err = withHints("SELECT 1", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, "SELECT 1")
+
+	_, err = withHints("SELECTED FROM dogs", []string{"NO_ICP(dogs)"})
+	assert.Equal(t, err, ErrHintsNotSupported)
+	_, err = withHints("SHOW TABLES", []string{"NO_ICP(dogs)"})
+	assert.Equal(t, err, ErrHintsNotSupported)
+	_, err = withHints("SELECT 1", []string{"*/ DROP TABLE dogs /*"})
+	assert.Equal(t, err, ErrMalformedHint)
+}
+
+func TestConnQueryWithHints(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec(`INSERT INTO people(firstname) VALUES ("Bob")`)
+		assert.NoError(t, err)
+
+		rows, err := conn.QueryWithHints("SELECT firstname FROM people", "MAX_EXECUTION_TIME(1000)")
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.String(), "Bob")
+		assert.False(t, rows.Next())
+		assert.NoError(t, rows.LastError())
+
+		_, err = conn.QueryWithHints("SHOW TABLES", "MAX_EXECUTION_TIME(1000)")
+		assert.Equal(t, err, ErrHintsNotSupported)
+	})
+}
@@ -0,0 +1,46 @@
+package mysqldriver
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrInterrupted is returned by Rows.LastError
+// when reading rows is aborted by Rows.Interrupt
+var ErrInterrupted = errors.New("mysqldriver: reading rows is interrupted")
+
+// Interrupt aborts reading of the result set. It's safe to call it
+// from another go-routine: Next blocked on reading the next row
+// returns false and LastError returns ErrInterrupted.
+//
+// All reads and writes of the connection fail after that,
+// so connection becomes invalid and it's closed when it's returned
+// to the pool. It affects the connection even when all rows
+// have already been read, so Interrupt must not be called after
+// the connection is used for the next query. ReconnectingConn
+// replaces the interrupted connection by a new one.
+//
+//	rows, _ := conn.Query("SELECT * FROM huge_table")
+//	go func() {
+//		<-ctrlC
+//		rows.Interrupt()
+//	}()
+//	for rows.Next() {
+//	}
+//	rows.LastError() // ErrInterrupted
+func (r *Rows) Interrupt() {
+	if r.conn == nil || r.conn.netConn == nil {
+		return
+	}
+	atomic.StoreInt32(&r.conn.interrupted, 1)
+	r.conn.netConn.interrupt()
+}
+
+// interruptError returns ErrInterrupted when
+// connection has been interrupted by Rows.Interrupt
+func (c *Conn) interruptError() error {
+	if atomic.LoadInt32(&c.interrupted) == 1 {
+		return ErrInterrupted
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package mysqldriver
+
+import (
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRowsInterrupt(t *testing.T) {
+	// server never sends the end of the result set
+	conn := newPipeConn([]byte{0x01}, columnDefinition("id"), eofPacket, []byte{0x01, '1'})
+
+	rows, err := conn.Query("SELECT id FROM people")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Int(), 1)
+
+	time.AfterFunc(10*time.Millisecond, rows.Interrupt)
+	assert.False(t, rows.Next())
+	assert.Equal(t, rows.LastError(), ErrInterrupted)
+	assert.False(t, conn.valid)
+	assert.Equal(t, atomic.LoadInt32(&conn.busy), int32(0))
+
+	_, err = conn.Query("SELECT 1")
+	assert.Equal(t, err, ErrInterrupted)
+}
+
+func TestRowsInterruptWithoutConnection(t *testing.T) {
+	rows := &Rows{}
+	rows.Interrupt()
+	assert.Nil(t, rows.conn)
+}
+
+func TestReconnectingConnReplacesInterruptedConnection(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:1)/test", 1, time.Duration(0))
+	interrupted, _ := newSessionConn("8.0.22")
+	atomic.StoreInt32(&interrupted.interrupted, 1)
+	conn := &ReconnectingConn{Conn: interrupted, db: db}
+
+	// nothing listens the port, so the new connection can't be established
+	_, err := conn.Query("SELECT 1")
+	_, ok := err.(*net.OpError)
+	assert.True(t, ok)
+	assert.False(t, interrupted.valid)
+}
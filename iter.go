@@ -0,0 +1,56 @@
+//go:build go1.23
+
+package mysqldriver
+
+import (
+	"iter"
+)
+
+// All returns iterator over the rows of the result set
+// to be used with range-over-func loop:
+//
+//	rows, _ := conn.Query("SELECT name FROM dogs")
+//	for row := range rows.All() {
+//		row.String() // dog's name
+//	}
+//	if err := rows.LastError(); err != nil {
+//		// handle error
+//	}
+//
+// When the loop is stopped by break or return,
+// the rest of the rows are discarded, so the connection
+// can be used for the next query right away.
+func (r *Rows) All() iter.Seq[*Rows] {
+	return func(yield func(*Rows) bool) {
+		for r.Next() {
+			if !yield(r) {
+				r.discard()
+				return
+			}
+		}
+	}
+}
+
+// AllWithError returns the same iterator as All does
+// except that error of the result set is yielded
+// as the last element of the sequence with nil row:
+//
+//	for row, err := range rows.AllWithError() {
+//		if err != nil {
+//			return err
+//		}
+//		row.String() // dog's name
+//	}
+func (r *Rows) AllWithError() iter.Seq2[*Rows, error] {
+	return func(yield func(*Rows, error) bool) {
+		for r.Next() {
+			if !yield(r, nil) {
+				r.discard()
+				return
+			}
+		}
+		if err := r.LastError(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
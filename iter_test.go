@@ -0,0 +1,68 @@
+//go:build go1.23
+
+package mysqldriver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRowsAll(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec(`INSERT INTO people(firstname) VALUES ("Bob"), ("Alice"), ("Max")`)
+		assert.NoError(t, err)
+
+		rows, err := conn.Query("SELECT firstname FROM people ORDER BY id")
+		assert.NoError(t, err)
+
+		var names []string
+		for row := range rows.All() {
+			names = append(names, row.String())
+		}
+		assert.Equal(t, names, []string{"Bob", "Alice", "Max"})
+		assert.NoError(t, rows.LastError())
+	})
+}
+
+func TestRowsAllDrainsRowsOnBreak(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec(`INSERT INTO people(firstname) VALUES ("Bob"), ("Alice"), ("Max")`)
+		assert.NoError(t, err)
+
+		rows, err := conn.Query("SELECT firstname FROM people ORDER BY id")
+		assert.NoError(t, err)
+		for row := range rows.All() {
+			assert.Equal(t, row.String(), "Bob")
+			break
+		}
+
+		rows, err = conn.Query("SELECT COUNT(*) FROM people")
+		assert.NoError(t, err)
+		for row := range rows.All() {
+			assert.Equal(t, row.Int(), 3)
+		}
+	})
+}
+
+func TestRowsAllWithError(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec(`INSERT INTO people(firstname) VALUES ("1"), ("Bob")`)
+		assert.NoError(t, err)
+
+		rows, err := conn.Query("SELECT firstname FROM people ORDER BY id")
+		assert.NoError(t, err)
+
+		var nums []int
+		var errs []error
+		for row, err := range rows.AllWithError() {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			nums = append(nums, row.Int())
+		}
+		assert.Equal(t, nums, []int{1, 0})
+		assert.Len(t, errs, 1)
+	})
+}
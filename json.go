@@ -0,0 +1,42 @@
+package mysqldriver
+
+import (
+	"encoding/json"
+)
+
+// JSON unmarshals value of the JSON column into dest
+// using json.Unmarshal. dest isn't changed when value is NULL,
+// while JSON null literal is unmarshalled as usual.
+// Unmarshalling error is returned right away
+// and it's also returned by LastError.
+//
+//	rows, _ := conn.Query("SELECT id, attributes FROM dogs")
+//	for rows.Next() {
+//		var attributes map[string]interface{}
+//		id := rows.Int()
+//		err := rows.JSON(&attributes)
+//	}
+func (r *Rows) JSON(dest interface{}) error {
+	value, null := r.NullBytes()
+	if null {
+		return nil
+	}
+
+	if err := json.Unmarshal(value, dest); err != nil {
+		r.errParse = err
+		return err
+	}
+	return nil
+}
+
+// NullJSON returns value of the JSON column without decoding
+// and NULL indicator. When value is NULL, second parameter is true.
+// Returned value is a copy, so it can be unmarshalled
+// after reading the next rows.
+func (r *Rows) NullJSON() (json.RawMessage, bool) {
+	value, null := r.NullBytes()
+	if null {
+		return nil, true
+	}
+	return append(json.RawMessage(nil), value...), false
+}
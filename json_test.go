@@ -0,0 +1,73 @@
+package mysqldriver
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRowsJSON(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01},
+		columnDefinition("attributes"),
+		eofPacket,
+		[]byte{0x0c, '{', '"', 'a', 'g', 'e', '"', ':', ' ', '3', '}', ' ', ' '},
+		[]byte{0xfb},
+		[]byte{0x04, 'n', 'u', 'l', 'l'},
+		[]byte{0x01, '{'},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT attributes FROM dogs")
+	assert.NoError(t, err)
+
+	var attributes map[string]int
+	assert.True(t, rows.Next())
+	assert.NoError(t, rows.JSON(&attributes))
+	assert.Equal(t, attributes, map[string]int{"age": 3})
+
+	// SQL NULL doesn't change dest
+	assert.True(t, rows.Next())
+	assert.NoError(t, rows.JSON(&attributes))
+	assert.Equal(t, attributes, map[string]int{"age": 3})
+
+	// JSON null resets the map
+	assert.True(t, rows.Next())
+	assert.NoError(t, rows.JSON(&attributes))
+	assert.Nil(t, attributes)
+
+	assert.True(t, rows.Next())
+	err = rows.JSON(&attributes)
+	_, ok := err.(*json.SyntaxError)
+	assert.True(t, ok)
+	assert.False(t, rows.Next())
+	assert.Equal(t, rows.LastError(), err)
+}
+
+func TestRowsNullJSON(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01},
+		columnDefinition("attributes"),
+		eofPacket,
+		[]byte{0x02, '[', ']'},
+		[]byte{0xfb},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT attributes FROM dogs")
+	assert.NoError(t, err)
+
+	assert.True(t, rows.Next())
+	value, null := rows.NullJSON()
+	assert.Equal(t, value, json.RawMessage("[]"))
+	assert.False(t, null)
+
+	assert.True(t, rows.Next())
+	value, null = rows.NullJSON()
+	assert.Nil(t, value)
+	assert.True(t, null)
+
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.LastError())
+}
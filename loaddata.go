@@ -0,0 +1,268 @@
+package mysqldriver
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pubnative/mysqlproto-go"
+)
+
+// localInfilePacket is sent by the server in response to
+// LOAD DATA LOCAL INFILE statement to request the file content
+// (see https://dev.mysql.com/doc/internals/en/com-query-response.html#local-infile-request)
+const localInfilePacket = 0xfb
+
+// loadDataChunkSize is the size of the packets
+// used to stream content of LOAD DATA LOCAL INFILE
+const loadDataChunkSize = 1 << 16
+
+// loadDataFileName is the file name used in LOAD DATA LOCAL INFILE
+// statements created by LoadData. Server requests this file
+// but content of the reader is sent instead.
+const loadDataFileName = "Reader::mysqldriver"
+
+// ErrLocalInfile is returned by Exec when server requests a local file
+// for LOAD DATA LOCAL INFILE statement and LocalFiles of the connection
+// isn't set. Use LoadData or set LocalFiles instead.
+var ErrLocalInfile = errors.New("mysqldriver: LOAD DATA LOCAL INFILE is only supported by LoadData")
+
+// ErrLocalFileNotAllowed is returned by the handler created
+// by AllowLocalFiles when server requests a file which isn't allowed
+var ErrLocalFileNotAllowed = errors.New("mysqldriver: local file isn't allowed")
+
+// LocalFileHandler returns content of the file requested by the server
+// for LOAD DATA LOCAL INFILE statement performed by Exec. Name is
+// the file name of the statement. Malicious server can request any
+// file regardless of the statement, so handler must check the name.
+// The file is refused when handler returns an error, in this case
+// Exec returns the error. Reader implementing io.Closer
+// is closed after the content is sent.
+type LocalFileHandler func(name string) (io.Reader, error)
+
+// AllowLocalFiles returns LocalFileHandler which opens only
+// the given files. Other files are refused with ErrLocalFileNotAllowed.
+//
+//	conn.LocalFiles = mysqldriver.AllowLocalFiles("/data/dogs.csv")
+//	_, err := conn.Exec("LOAD DATA LOCAL INFILE '/data/dogs.csv' INTO TABLE dogs")
+func AllowLocalFiles(paths ...string) LocalFileHandler {
+	allowed := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		allowed[filepath.Clean(path)] = true
+	}
+
+	return func(name string) (io.Reader, error) {
+		if !allowed[filepath.Clean(name)] {
+			return nil, ErrLocalFileNotAllowed
+		}
+		return os.Open(name)
+	}
+}
+
+// LoadDataOptions describes format of the data loaded by LoadData.
+// Empty values are omitted from the statement, so server defaults
+// are used which are tab separated fields and lines terminated by "\n"
+// (see https://dev.mysql.com/doc/refman/8.0/en/load-data.html)
+type LoadDataOptions struct {
+	Columns []string // columns to load values into. All columns of the table by default
+
+	FieldsTerminatedBy string
+	FieldsEnclosedBy   string
+	OptionallyEnclosed bool // enclosing character is used only for string values
+	FieldsEscapedBy    string
+	LinesStartingBy    string
+	LinesTerminatedBy  string
+	IgnoreLines        int // number of lines to skip, e.g. for CSV header
+
+	Replace bool // replace rows with the same unique key
+	Ignore  bool // skip rows with the same unique key
+}
+
+// LoadData streams content of the reader to the server
+// using LOAD DATA LOCAL INFILE statement. It's much faster
+// to import big amount of data this way than doing separate INSERTs.
+//
+//	file, _ := os.Open("dogs.csv")
+//	okPacket, err := conn.LoadData("dogs", file, mysqldriver.LoadDataOptions{
+//		Columns:            []string{"name", "age"},
+//		FieldsTerminatedBy: ",",
+//		IgnoreLines:        1,
+//	})
+//	fmt.Println(okPacket.AffectedRows) // number of imported rows
+//
+// Server must allow local files with local_infile system variable.
+// Error reading from the reader is returned after the server
+// completes the statement with the data which is already sent.
+// The statement is performed the same way as by Exec: it's limited
+// by QueryTimeout and the table is qualified with DefaultSchema.
+func (c *Conn) LoadData(table string, r io.Reader, opts LoadDataOptions) (mysqlproto.OKPacket, error) {
+	if err := c.acquire(); err != nil {
+		return mysqlproto.OKPacket{}, err
+	}
+	defer c.release()
+
+	if c.DefaultSchema != "" {
+		table = qualifyTable(table, c.DefaultSchema)
+	}
+	sql := loadDataStatement(table, opts, c.noBackslashEscapes())
+
+	packet, err := c.writeQuery(sql)
+	if err != nil {
+		return mysqlproto.OKPacket{}, err
+	}
+
+	if packet.Payload[0] != localInfilePacket {
+		return c.execResult(packet.Payload, len(sql))
+	}
+	return c.loadLocalInfile(r, packet.SequenceID+1)
+}
+
+// execLocalInfile answers the request of the local file
+// sent by the server to Exec. File is sent when LocalFiles
+// of the connection allows it, otherwise it's refused
+// by sending an empty content.
+func (c *Conn) execLocalInfile(name string, seq byte) (mysqlproto.OKPacket, error) {
+	if c.LocalFiles == nil {
+		if _, err := c.loadLocalInfile(nil, seq); err != nil {
+			return mysqlproto.OKPacket{}, err
+		}
+		return mysqlproto.OKPacket{}, ErrLocalInfile
+	}
+
+	r, errFile := c.LocalFiles(name)
+	if errFile != nil {
+		if _, err := c.loadLocalInfile(nil, seq); err != nil {
+			return mysqlproto.OKPacket{}, err
+		}
+		return mysqlproto.OKPacket{}, errFile
+	}
+	if closer, ok := r.(io.Closer); ok {
+		defer closer.Close()
+	}
+	return c.loadLocalInfile(r, seq)
+}
+
+// loadLocalInfile streams content of the reader requested by the server
+// and reads the result of LOAD DATA LOCAL INFILE statement
+func (c *Conn) loadLocalInfile(r io.Reader, seq byte) (mysqlproto.OKPacket, error) {
+	errRead, err := c.sendLocalInfile(r, seq)
+	if err != nil {
+		c.valid = false
+		return mysqlproto.OKPacket{}, c.timeoutError(err)
+	}
+
+	packet, err := c.conn.NextPacket()
+	if err != nil {
+		c.valid = false
+		return mysqlproto.OKPacket{}, c.timeoutError(err)
+	}
+
+	pkt, err := parseExecResult(packet.Payload, c.conn.CapabilityFlags)
+	if err == nil {
+		c.handleOKPacket(pkt)
+		c.trackSessionState(packet.Payload)
+	}
+	if err == nil && errRead != nil {
+		return pkt, errRead
+	}
+	return pkt, err
+}
+
+// sendLocalInfile streams content of the reader in packets
+// starting with the given sequence ID and terminates it
+// with an empty packet. The first returned error is the
+// error of reading from the reader, the second one is
+// the error of writing to the connection.
+func (c *Conn) sendLocalInfile(r io.Reader, seq byte) (error, error) {
+	buf := make([]byte, 4+loadDataChunkSize)
+	var errRead error
+	for r != nil {
+		n, err := r.Read(buf[4:])
+		if n > 0 {
+			if err := c.writePacket(buf, n, seq); err != nil {
+				return nil, err
+			}
+			seq++
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			errRead = err
+			break
+		}
+	}
+
+	// empty packet finishes the content of the file
+	return errRead, c.writePacket(buf, 0, seq)
+}
+
+// writePacket writes first n bytes of the payload which is stored
+// in buf after 4 bytes reserved for the packet header
+func (c *Conn) writePacket(buf []byte, n int, seq byte) error {
+	buf[0] = byte(n)
+	buf[1] = byte(n >> 8)
+	buf[2] = byte(n >> 16)
+	buf[3] = seq
+	_, err := c.conn.Write(buf[:4+n])
+	return err
+}
+
+// loadDataStatement creates LOAD DATA LOCAL INFILE statement.
+// String literals are escaped according to NO_BACKSLASH_ESCAPES mode.
+func loadDataStatement(table string, opts LoadDataOptions, noBackslashEscapes bool) string {
+	quote := func(str string) string {
+		return quoteString(str, noBackslashEscapes)
+	}
+
+	sql := "LOAD DATA LOCAL INFILE " + quote(loadDataFileName)
+	if opts.Replace {
+		sql += " REPLACE"
+	} else if opts.Ignore {
+		sql += " IGNORE"
+	}
+	sql += " INTO TABLE " + quoteIdentifier(table)
+
+	if opts.FieldsTerminatedBy != "" || opts.FieldsEnclosedBy != "" || opts.FieldsEscapedBy != "" {
+		sql += " FIELDS"
+		if opts.FieldsTerminatedBy != "" {
+			sql += " TERMINATED BY " + quote(opts.FieldsTerminatedBy)
+		}
+		if opts.FieldsEnclosedBy != "" {
+			if opts.OptionallyEnclosed {
+				sql += " OPTIONALLY"
+			}
+			sql += " ENCLOSED BY " + quote(opts.FieldsEnclosedBy)
+		}
+		if opts.FieldsEscapedBy != "" {
+			sql += " ESCAPED BY " + quote(opts.FieldsEscapedBy)
+		}
+	}
+
+	if opts.LinesStartingBy != "" || opts.LinesTerminatedBy != "" {
+		sql += " LINES"
+		if opts.LinesStartingBy != "" {
+			sql += " STARTING BY " + quote(opts.LinesStartingBy)
+		}
+		if opts.LinesTerminatedBy != "" {
+			sql += " TERMINATED BY " + quote(opts.LinesTerminatedBy)
+		}
+	}
+
+	if opts.IgnoreLines > 0 {
+		sql += " IGNORE " + strconv.Itoa(opts.IgnoreLines) + " LINES"
+	}
+
+	if len(opts.Columns) > 0 {
+		columns := make([]string, len(opts.Columns))
+		for i, column := range opts.Columns {
+			columns[i] = quoteIdentifier(column)
+		}
+		sql += " (" + strings.Join(columns, ", ") + ")"
+	}
+
+	return sql
+}
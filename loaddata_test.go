@@ -0,0 +1,223 @@
+package mysqldriver
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadDataStatementDefaults(t *testing.T) {
+	sql := loadDataStatement("people", LoadDataOptions{}, false)
+	assert.Equal(t, sql, "LOAD DATA LOCAL INFILE 'Reader::mysqldriver' INTO TABLE `people`")
+}
+
+func TestLoadDataStatementWithOptions(t *testing.T) {
+	sql := loadDataStatement("test.people", LoadDataOptions{
+		Columns:            []string{"firstname", "age`"},
+		FieldsTerminatedBy: ",",
+		FieldsEnclosedBy:   `"`,
+		OptionallyEnclosed: true,
+		FieldsEscapedBy:    `\`,
+		LinesStartingBy:    "'",
+		LinesTerminatedBy:  "\r\n",
+		IgnoreLines:        1,
+		Replace:            true,
+	}, false)
+	assert.Equal(t, sql, "LOAD DATA LOCAL INFILE 'Reader::mysqldriver' REPLACE INTO TABLE `test`.`people`"+
+		` FIELDS TERMINATED BY ',' OPTIONALLY ENCLOSED BY '\"' ESCAPED BY '\\'`+
+		` LINES STARTING BY '\'' TERMINATED BY '\r\n'`+
+		" IGNORE 1 LINES (`firstname`, `age```)")
+}
+
+func TestLoadDataStatementWithNoBackslashEscapes(t *testing.T) {
+	sql := loadDataStatement("people", LoadDataOptions{FieldsEnclosedBy: "'", FieldsEscapedBy: `\`}, true)
+	assert.Equal(t, sql, "LOAD DATA LOCAL INFILE 'Reader::mysqldriver' INTO TABLE `people`"+
+		` FIELDS ENCLOSED BY '''' ESCAPED BY '\'`)
+}
+
+type writeRecorder struct {
+	stream
+	written []byte
+}
+
+func (w *writeRecorder) Write(data []byte) (int, error) {
+	w.written = append(w.written, data...)
+	return len(data), nil
+}
+
+type failingReader struct{ data string }
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.data == "" {
+		return 0, errors.New("read failed")
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestSendLocalInfile(t *testing.T) {
+	w := &writeRecorder{}
+	conn := &Conn{conn: mysqlproto.Conn{Stream: mysqlproto.NewStream(w, time.Duration(0))}}
+
+	errRead, err := conn.sendLocalInfile(strings.NewReader("1,Bob\n"), 2)
+	assert.NoError(t, err)
+	assert.NoError(t, errRead)
+	assert.Equal(t, w.written, []byte{
+		0x06, 0x00, 0x00, 0x02, '1', ',', 'B', 'o', 'b', '\n',
+		0x00, 0x00, 0x00, 0x03,
+	})
+}
+
+func TestSendLocalInfileSplitsContentIntoPackets(t *testing.T) {
+	w := &writeRecorder{}
+	conn := &Conn{conn: mysqlproto.Conn{Stream: mysqlproto.NewStream(w, time.Duration(0))}}
+
+	data := strings.Repeat("a", loadDataChunkSize+1)
+	_, err := conn.sendLocalInfile(strings.NewReader(data), 1)
+	assert.NoError(t, err)
+	assert.Len(t, w.written, 4+loadDataChunkSize+4+1+4)
+	assert.Equal(t, w.written[:4], []byte{0x00, 0x00, 0x01, 0x01})
+	assert.Equal(t, w.written[4+loadDataChunkSize:][:5], []byte{0x01, 0x00, 0x00, 0x02, 'a'})
+	assert.Equal(t, w.written[len(w.written)-4:], []byte{0x00, 0x00, 0x00, 0x03})
+}
+
+func TestSendLocalInfileFinishesTransferWhenReaderFails(t *testing.T) {
+	w := &writeRecorder{}
+	conn := &Conn{conn: mysqlproto.Conn{Stream: mysqlproto.NewStream(w, time.Duration(0))}}
+
+	errRead, err := conn.sendLocalInfile(&failingReader{data: "1"}, 2)
+	assert.NoError(t, err)
+	assert.EqualError(t, errRead, "read failed")
+	assert.Equal(t, w.written, []byte{0x01, 0x00, 0x00, 0x02, '1', 0x00, 0x00, 0x00, 0x03})
+}
+
+func TestConnLoadData(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec("SET GLOBAL local_infile = 1")
+		assert.NoError(t, err)
+
+		data := "firstname,age\nBob,3\n\"Alice, Jr.\",5\n"
+		pkt, err := conn.LoadData("people", strings.NewReader(data), LoadDataOptions{
+			Columns:            []string{"firstname", "age"},
+			FieldsTerminatedBy: ",",
+			FieldsEnclosedBy:   `"`,
+			OptionallyEnclosed: true,
+			IgnoreLines:        1,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, pkt.AffectedRows, uint64(2))
+
+		rows, err := conn.Query("SELECT firstname, age FROM people ORDER BY id")
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.String(), "Bob")
+		assert.Equal(t, rows.Int(), 3)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.String(), "Alice, Jr.")
+		assert.Equal(t, rows.Int(), 5)
+		assert.False(t, rows.Next())
+	})
+}
+
+func TestConnLoadDataQualifiesTable(t *testing.T) {
+	conn, s := newSessionConn("8.0.22", []byte{0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00})
+	conn.DefaultSchema = "tenant"
+
+	_, err := conn.LoadData("dogs", strings.NewReader(""), LoadDataOptions{})
+	assert.NoError(t, err)
+	assert.Contains(t, string(s.written), "INTO TABLE `tenant`.`dogs`")
+	assert.True(t, conn.valid)
+
+	conn, s = newSessionConn("8.0.22", []byte{0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00})
+	conn.DefaultSchema = "tenant"
+	_, err = conn.LoadData("archive.dogs", strings.NewReader(""), LoadDataOptions{})
+	assert.NoError(t, err)
+	assert.Contains(t, string(s.written), "INTO TABLE `archive`.`dogs`")
+}
+
+func TestConnLoadDataChecksPacketSize(t *testing.T) {
+	conn, s := newSessionConn("8.0.22")
+	conn.maxAllowedPacket = 16
+
+	_, err := conn.LoadData("dogs", strings.NewReader(""), LoadDataOptions{})
+	_, ok := err.(*PacketTooLargeError)
+	assert.True(t, ok)
+	assert.Empty(t, s.written)
+	assert.True(t, conn.valid)
+}
+
+func TestConnExecRefusesLocalInfile(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec("SET GLOBAL local_infile = 1")
+		assert.NoError(t, err)
+
+		_, err = conn.Exec("LOAD DATA LOCAL INFILE '/etc/passwd' INTO TABLE people")
+		assert.Equal(t, err, ErrLocalInfile)
+
+		// connection is still usable
+		rows, err := conn.Query("SELECT COUNT(*) FROM people")
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.Int(), 0)
+		assert.False(t, rows.Next())
+	})
+}
+
+func TestConnExecSendsLocalFile(t *testing.T) {
+	request := append([]byte{localInfilePacket}, "dogs.csv"...)
+	conn, s := newSessionConn("8.0.22", request, []byte{mysqlproto.OK_PACKET, 0x02, 0x00, 0x02, 0x00, 0x00, 0x00})
+	var requested string
+	conn.LocalFiles = func(name string) (io.Reader, error) {
+		requested = name
+		return strings.NewReader("1,Bob\n"), nil
+	}
+
+	_, err := conn.Exec("LOAD DATA LOCAL INFILE 'dogs.csv' INTO TABLE dogs")
+	assert.NoError(t, err)
+	assert.Equal(t, requested, "dogs.csv")
+	assert.True(t, bytes.HasSuffix(s.written, []byte{
+		0x06, 0x00, 0x00, 0x02, '1', ',', 'B', 'o', 'b', '\n',
+		0x00, 0x00, 0x00, 0x03,
+	}))
+	assert.True(t, conn.valid)
+}
+
+func TestConnExecRefusesLocalFileOfHandler(t *testing.T) {
+	request := append([]byte{localInfilePacket}, "/etc/passwd"...)
+	conn, s := newSessionConn("8.0.22", request, []byte{mysqlproto.OK_PACKET, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00})
+	conn.LocalFiles = AllowLocalFiles("dogs.csv")
+
+	_, err := conn.Exec("LOAD DATA LOCAL INFILE 'dogs.csv' INTO TABLE dogs")
+	assert.Equal(t, err, ErrLocalFileNotAllowed)
+	assert.True(t, bytes.HasSuffix(s.written, []byte{0x00, 0x00, 0x00, 0x02}))
+	assert.True(t, conn.valid)
+}
+
+func TestAllowLocalFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mysqldriver")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "dogs.csv")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("1,Bob\n"), 0600))
+
+	handler := AllowLocalFiles(path)
+	r, err := handler(filepath.Join(dir, ".", "dogs.csv"))
+	assert.NoError(t, err)
+	data, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, string(data), "1,Bob\n")
+	r.(io.Closer).Close()
+
+	_, err = handler(filepath.Join(dir, "cats.csv"))
+	assert.Equal(t, err, ErrLocalFileNotAllowed)
+}
@@ -0,0 +1,54 @@
+package mysqldriver
+
+import (
+	"unicode/utf8"
+)
+
+// WasMaxLength reports whether the last value read by one of
+// the accessors has the max length of its column, e.g. 10 characters
+// of VARCHAR(10) column. It may indicate that the value
+// has been truncated when it was written.
+//
+// MaxLength of the column is measured in bytes, so the number
+// of characters is calculated using max size of the character
+// of the column collation. It's known for binary, latin1,
+// utf8mb3 and utf8mb4 collations, values of other collations
+// are compared by the number of bytes.
+//
+//	rows, _ := conn.Query("SELECT name FROM dogs")
+//	for rows.Next() {
+//		name := rows.String()
+//		if rows.WasMaxLength() {
+//			log.Println("name may be truncated:", name)
+//		}
+//	}
+func (r *Rows) WasMaxLength() bool {
+	if r.readColumns == 0 {
+		return false
+	}
+
+	column := r.definitions[r.readColumns-1]
+	value := r.columns[column.Name]
+	if value.null || column.MaxLength == 0 {
+		return false
+	}
+
+	size := collationMaxLen(column.CharacterSet)
+	if size == 1 {
+		return uint32(len(value.data)) == column.MaxLength
+	}
+	return uint32(utf8.RuneCount(value.data)*size) == column.MaxLength
+}
+
+// collationMaxLen returns max size of the character in bytes
+// (see SELECT ID, MAXLEN FROM information_schema.COLLATIONS
+// JOIN information_schema.CHARACTER_SETS USING (CHARACTER_SET_NAME))
+func collationMaxLen(id uint16) int {
+	switch {
+	case id == 33 || id == 76 || id == 83 || id >= 192 && id <= 215 || id == 223:
+		return 3 // utf8mb3
+	case id == 45 || id == 46 || id >= 224 && id <= 247 || id >= 255 && id <= 323:
+		return 4 // utf8mb4
+	}
+	return 1
+}
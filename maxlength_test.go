@@ -0,0 +1,57 @@
+package mysqldriver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func columnDefinitionWithLength(name string, collation uint16, maxLength byte) []byte {
+	payload := columnDefinition(name)
+	payload[len(payload)-12] = byte(collation)
+	payload[len(payload)-11] = byte(collation >> 8)
+	payload[len(payload)-10] = maxLength
+	return payload
+}
+
+func TestRowsWasMaxLength(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x03},
+		columnDefinitionWithLength("name", 45, 20), // VARCHAR(5) utf8mb4
+		columnDefinitionWithLength("code", binaryCharacterSet, 3),
+		columnDefinitionWithLength("city", 8, 4), // latin1
+		eofPacket,
+		[]byte{0x06, 'h', 0xc3, 0xa9, 'l', 'l', 'o', 0x03, 'a', 'b', 'c', 0xfb},
+		[]byte{0x03, 'a', 'b', 'c', 0x02, 'a', 'b', 0x04, 'r', 'o', 'm', 'e'},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT name, code, city FROM people")
+	assert.NoError(t, err)
+
+	assert.True(t, rows.Next())
+	assert.False(t, rows.WasMaxLength())
+	assert.Equal(t, rows.String(), "héllo")
+	assert.True(t, rows.WasMaxLength())
+	rows.Bytes()
+	assert.True(t, rows.WasMaxLength())
+	rows.Bytes()
+	assert.False(t, rows.WasMaxLength()) // NULL
+
+	assert.True(t, rows.Next())
+	rows.Bytes()
+	assert.False(t, rows.WasMaxLength())
+	rows.Bytes()
+	assert.False(t, rows.WasMaxLength())
+	rows.Bytes()
+	assert.True(t, rows.WasMaxLength())
+	assert.False(t, rows.Next())
+}
+
+func TestCollationMaxLen(t *testing.T) {
+	assert.Equal(t, collationMaxLen(binaryCharacterSet), 1)
+	assert.Equal(t, collationMaxLen(8), 1)   // latin1_swedish_ci
+	assert.Equal(t, collationMaxLen(33), 3)  // utf8mb3_general_ci
+	assert.Equal(t, collationMaxLen(45), 4)  // utf8mb4_general_ci
+	assert.Equal(t, collationMaxLen(255), 4) // utf8mb4_0900_ai_ci
+}
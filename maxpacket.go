@@ -0,0 +1,72 @@
+package mysqldriver
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrPacketTooLarge is returned when the statement exceeds
+// max_allowed_packet of the server. Returned error is
+// *PacketTooLargeError, compare it using errors.Is.
+var ErrPacketTooLarge = errors.New("mysqldriver: packet is bigger than max_allowed_packet")
+
+// PacketTooLargeError contains the size of the statement
+// which exceeds max_allowed_packet of the server
+type PacketTooLargeError struct {
+	Size       int // size of the packet
+	MaxAllowed int // max_allowed_packet, 0 when it's unknown
+}
+
+func (e *PacketTooLargeError) Error() string {
+	if e.MaxAllowed == 0 {
+		return fmt.Sprintf("%s: %d bytes", ErrPacketTooLarge, e.Size)
+	}
+	return fmt.Sprintf("%s: %d bytes, max allowed %d bytes", ErrPacketTooLarge, e.Size, e.MaxAllowed)
+}
+
+func (e *PacketTooLargeError) Is(target error) bool {
+	return target == ErrPacketTooLarge
+}
+
+// MaxAllowedPacket returns max_allowed_packet of the server
+// read after connecting when Options.CheckPacketSize is set.
+// It's the max size of the statement including the command byte,
+// so it can be used to size batches of bulk inserts.
+// Zero value means the limit is unknown.
+func (c *Conn) MaxAllowedPacket() uint64 {
+	return uint64(c.maxAllowedPacket)
+}
+
+// readMaxAllowedPacket stores max_allowed_packet of the server,
+// so too large statements are refused before sending
+func (c *Conn) readMaxAllowedPacket() error {
+	rows, err := c.Query("SELECT @@max_allowed_packet")
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		c.maxAllowedPacket = rows.Int()
+	}
+	return rows.LastError()
+}
+
+// checkPacketSize returns PacketTooLargeError when command
+// with the payload of the given size exceeds max_allowed_packet
+func (c *Conn) checkPacketSize(size int) error {
+	// command byte is a part of the packet
+	if c.maxAllowedPacket > 0 && size+1 > c.maxAllowedPacket {
+		return &PacketTooLargeError{Size: size + 1, MaxAllowed: c.maxAllowedPacket}
+	}
+	return nil
+}
+
+// packetTooLargeError replaces ER_NET_PACKET_TOO_LARGE error
+// with PacketTooLargeError. Server closes the connection
+// after sending this error, so connection becomes invalid.
+func (c *Conn) packetTooLargeError(err error, size int) error {
+	if code, ok := ErrorCode(err); ok && code == errCodeNetPacketTooLarge {
+		c.valid = false
+		return &PacketTooLargeError{Size: size + 1, MaxAllowed: c.maxAllowedPacket}
+	}
+	return err
+}
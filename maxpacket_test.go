@@ -0,0 +1,62 @@
+package mysqldriver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnCheckPacketSize(t *testing.T) {
+	conn, err := NewConnOptions(context.Background(), "root", "", "tcp", "127.0.0.1:3306", "test", time.Duration(0), Options{CheckPacketSize: true})
+	assert.NoError(t, err)
+	assert.True(t, conn.maxAllowedPacket > 0)
+
+	rows, err := conn.Query("SELECT @@max_allowed_packet")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	assert.Equal(t, conn.MaxAllowedPacket(), uint64(rows.Int64()))
+	assert.False(t, rows.Next())
+
+	sql := "SELECT '" + strings.Repeat("a", conn.maxAllowedPacket) + "'"
+	_, err = conn.Query(sql)
+	assert.Equal(t, err, &PacketTooLargeError{Size: len(sql) + 1, MaxAllowed: conn.maxAllowedPacket})
+	assert.True(t, errors.Is(err, ErrPacketTooLarge))
+	_, err = conn.Exec(sql)
+	assert.True(t, errors.Is(err, ErrPacketTooLarge))
+	assert.True(t, conn.valid)
+
+	rows, err = conn.Query("SELECT 1")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Int(), 1)
+	assert.False(t, rows.Next())
+}
+
+func TestConnPacketTooLargeError(t *testing.T) {
+	conn := &Conn{valid: true}
+	err := conn.packetTooLargeError(mysqlproto.ERRPacket{ErrorCode: errCodeNetPacketTooLarge}, 10)
+	assert.Equal(t, err, &PacketTooLargeError{Size: 11})
+	assert.Equal(t, err.Error(), "mysqldriver: packet is bigger than max_allowed_packet: 11 bytes")
+	assert.False(t, conn.valid)
+
+	conn = &Conn{valid: true, maxAllowedPacket: 8}
+	err = conn.packetTooLargeError(mysqlproto.ERRPacket{ErrorCode: errCodeNetPacketTooLarge}, 10)
+	assert.Equal(t, err.Error(), "mysqldriver: packet is bigger than max_allowed_packet: 11 bytes, max allowed 8 bytes")
+
+	conn = &Conn{valid: true}
+	other := mysqlproto.ERRPacket{ErrorCode: errCodeParse}
+	assert.Equal(t, conn.packetTooLargeError(other, 10), other)
+	assert.True(t, conn.valid)
+}
+
+func TestConnMaxAllowedPacketIsUnknownByDefault(t *testing.T) {
+	conn, err := NewConn("root", "", "tcp", "127.0.0.1:3306", "test", time.Duration(0))
+	assert.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, conn.MaxAllowedPacket(), uint64(0))
+}
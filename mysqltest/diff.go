@@ -0,0 +1,218 @@
+package mysqltest
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/pubnative/mysqldriver-go"
+)
+
+// Cell is a single value of the row
+type Cell struct {
+	Value string
+	Null  bool
+}
+
+func (c Cell) String() string {
+	if c.Null {
+		return "NULL"
+	}
+	return strconv.Quote(c.Value)
+}
+
+// RowDiff describes the row which differs in two result sets
+type RowDiff struct {
+	Index   int      // index of the row in the first result set, or in the second one when row is missing in the first
+	Key     []Cell   // values of the key columns. Empty when rows are compared by position
+	Columns []string // columns with different values. Empty when row is missing in one of the result sets
+	A       []Cell   // values of the row in the first result set. Nil when row is missing
+	B       []Cell   // values of the row in the second result set. Nil when row is missing
+}
+
+func (d RowDiff) String() string {
+	row := "row #" + strconv.Itoa(d.Index)
+	if len(d.Key) > 0 {
+		row = "row " + formatCells(d.Key)
+	}
+
+	switch {
+	case d.A == nil:
+		return row + " is missing in the first result set: " + formatCells(d.B)
+	case d.B == nil:
+		return row + " is missing in the second result set: " + formatCells(d.A)
+	default:
+		return row + " differs in columns " + strings.Join(d.Columns, ", ") +
+			": " + formatCells(d.A) + " != " + formatCells(d.B)
+	}
+}
+
+// DiffResultSets reads both result sets till the end
+// and returns the rows which differ. NULL and empty string
+// are treated as different values.
+//
+// Rows are compared by their position unless key columns are given.
+// In this case rows with the same values of the key columns
+// are compared, so order of the rows doesn't matter.
+// Key values must be unique.
+func DiffResultSets(a, b *mysqldriver.Rows, keys ...string) ([]RowDiff, error) {
+	columns, err := columnNames(a, b)
+	if err != nil {
+		return nil, err
+	}
+
+	rowsA, err := readRows(a)
+	if err != nil {
+		return nil, err
+	}
+	rowsB, err := readRows(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 {
+		return diffByPosition(columns, rowsA, rowsB), nil
+	}
+	return diffByKey(columns, keys, rowsA, rowsB)
+}
+
+func columnNames(a, b *mysqldriver.Rows) ([]string, error) {
+	columnsA, columnsB := a.Columns(), b.Columns()
+	if len(columnsA) != len(columnsB) {
+		return nil, errors.New("mysqltest: result sets have different number of columns")
+	}
+
+	names := make([]string, len(columnsA))
+	for i := range columnsA {
+		if columnsA[i].Name != columnsB[i].Name {
+			return nil, errors.New("mysqltest: result sets have different columns: " +
+				columnsA[i].Name + " and " + columnsB[i].Name)
+		}
+		names[i] = columnsA[i].Name
+	}
+	return names, nil
+}
+
+func readRows(rows *mysqldriver.Rows) ([][]Cell, error) {
+	columns := len(rows.Columns())
+	var result [][]Cell
+	for rows.Next() {
+		row := make([]Cell, columns)
+		for i := range row {
+			row[i].Value, row[i].Null = rows.NullString()
+		}
+		result = append(result, row)
+	}
+	return result, rows.LastError()
+}
+
+func diffByPosition(columns []string, a, b [][]Cell) []RowDiff {
+	var diffs []RowDiff
+	for i := 0; i < len(a) || i < len(b); i++ {
+		switch {
+		case i >= len(a):
+			diffs = append(diffs, RowDiff{Index: i, B: b[i]})
+		case i >= len(b):
+			diffs = append(diffs, RowDiff{Index: i, A: a[i]})
+		default:
+			if diff := diffCells(columns, a[i], b[i]); len(diff) > 0 {
+				diffs = append(diffs, RowDiff{Index: i, Columns: diff, A: a[i], B: b[i]})
+			}
+		}
+	}
+	return diffs
+}
+
+func diffByKey(columns, keys []string, a, b [][]Cell) ([]RowDiff, error) {
+	indexes := make([]int, len(keys))
+	for i, key := range keys {
+		indexes[i] = -1
+		for j, column := range columns {
+			if column == key {
+				indexes[i] = j
+			}
+		}
+		if indexes[i] < 0 {
+			return nil, errors.New("mysqltest: key column " + key + " doesn't exist")
+		}
+	}
+
+	key := func(row []Cell) []Cell {
+		values := make([]Cell, len(indexes))
+		for i, index := range indexes {
+			values[i] = row[index]
+		}
+		return values
+	}
+
+	mapB := make(map[string]int, len(b))
+	for i, row := range b {
+		k := keyString(key(row))
+		if _, ok := mapB[k]; ok {
+			return nil, errors.New("mysqltest: duplicate key " + formatCells(key(row)))
+		}
+		mapB[k] = i
+	}
+
+	var diffs []RowDiff
+	seenA := make(map[string]bool, len(a))
+	for i, row := range a {
+		k := keyString(key(row))
+		if seenA[k] {
+			return nil, errors.New("mysqltest: duplicate key " + formatCells(key(row)))
+		}
+		seenA[k] = true
+
+		j, ok := mapB[k]
+		if !ok {
+			diffs = append(diffs, RowDiff{Index: i, Key: key(row), A: row})
+			continue
+		}
+		if diff := diffCells(columns, row, b[j]); len(diff) > 0 {
+			diffs = append(diffs, RowDiff{Index: i, Key: key(row), Columns: diff, A: row, B: b[j]})
+		}
+	}
+
+	for i, row := range b {
+		if !seenA[keyString(key(row))] {
+			diffs = append(diffs, RowDiff{Index: i, Key: key(row), B: row})
+		}
+	}
+
+	return diffs, nil
+}
+
+func diffCells(columns []string, a, b []Cell) []string {
+	var diff []string
+	for i := range a {
+		if a[i] != b[i] {
+			diff = append(diff, columns[i])
+		}
+	}
+	return diff
+}
+
+// keyString encodes values of the key, so NULL and
+// empty string produce different keys
+func keyString(cells []Cell) string {
+	var key []byte
+	for _, cell := range cells {
+		if cell.Null {
+			key = append(key, 'N')
+		} else {
+			key = append(key, 'V')
+			key = strconv.AppendInt(key, int64(len(cell.Value)), 10)
+			key = append(key, ':')
+			key = append(key, cell.Value...)
+		}
+	}
+	return string(key)
+}
+
+func formatCells(cells []Cell) string {
+	values := make([]string, len(cells))
+	for i, cell := range cells {
+		values[i] = cell.String()
+	}
+	return "(" + strings.Join(values, ", ") + ")"
+}
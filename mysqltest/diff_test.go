@@ -0,0 +1,83 @@
+package mysqltest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pubnative/mysqldriver-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func v(value string) Cell { return Cell{Value: value} }
+
+var null = Cell{Null: true}
+
+func TestDiffByPosition(t *testing.T) {
+	columns := []string{"id", "name"}
+	a := [][]Cell{{v("1"), v("Bob")}, {v("2"), v("")}, {v("3"), v("Max")}}
+	b := [][]Cell{{v("1"), v("Bob")}, {v("2"), null}}
+
+	diffs := diffByPosition(columns, a, b)
+	assert.Equal(t, diffs, []RowDiff{
+		{Index: 1, Columns: []string{"name"}, A: a[1], B: b[1]},
+		{Index: 2, A: a[2]},
+	})
+	assert.Equal(t, diffs[0].String(), `row #1 differs in columns name: ("2", "") != ("2", NULL)`)
+	assert.Equal(t, diffs[1].String(), `row #2 is missing in the second result set: ("3", "Max")`)
+}
+
+func TestDiffByKey(t *testing.T) {
+	columns := []string{"id", "name"}
+	a := [][]Cell{{v("1"), v("Bob")}, {v("2"), v("Alice")}, {v("3"), v("Max")}}
+	b := [][]Cell{{v("4"), v("Rex")}, {v("3"), v("Max")}, {v("1"), v("Bobby")}}
+
+	diffs, err := diffByKey(columns, []string{"id"}, a, b)
+	assert.NoError(t, err)
+	assert.Equal(t, diffs, []RowDiff{
+		{Index: 0, Key: []Cell{v("1")}, Columns: []string{"name"}, A: a[0], B: b[2]},
+		{Index: 1, Key: []Cell{v("2")}, A: a[1]},
+		{Index: 0, Key: []Cell{v("4")}, B: b[0]},
+	})
+	assert.Equal(t, diffs[2].String(), `row ("4") is missing in the first result set: ("4", "Rex")`)
+}
+
+func TestDiffByKeyDistinguishesNullAndEmptyKeys(t *testing.T) {
+	columns := []string{"code", "name"}
+	a := [][]Cell{{null, v("none")}, {v(""), v("empty")}}
+	b := [][]Cell{{v(""), v("empty")}, {null, v("none")}}
+
+	diffs, err := diffByKey(columns, []string{"code"}, a, b)
+	assert.NoError(t, err)
+	assert.Len(t, diffs, 0)
+}
+
+func TestDiffByKeyErrors(t *testing.T) {
+	columns := []string{"id", "name"}
+	a := [][]Cell{{v("1"), v("Bob")}, {v("1"), v("Alice")}}
+
+	_, err := diffByKey(columns, []string{"id"}, a, nil)
+	assert.EqualError(t, err, `mysqltest: duplicate key ("1")`)
+	_, err = diffByKey(columns, []string{"age"}, a, nil)
+	assert.EqualError(t, err, "mysqltest: key column age doesn't exist")
+}
+
+func TestDiffResultSets(t *testing.T) {
+	db := mysqldriver.NewDB("root@tcp(127.0.0.1:3306)/test", 2, time.Duration(0))
+	defer db.Close()
+	connA, err := db.GetConn()
+	assert.NoError(t, err)
+	connB, err := db.GetConn()
+	assert.NoError(t, err)
+
+	a, err := connA.Query(`SELECT 1 AS id, "Bob" AS name UNION ALL SELECT 2, ""`)
+	assert.NoError(t, err)
+	b, err := connB.Query(`SELECT 2 AS id, NULL AS name UNION ALL SELECT 1, "Bob"`)
+	assert.NoError(t, err)
+
+	diffs, err := DiffResultSets(a, b, "id")
+	assert.NoError(t, err)
+	assert.Len(t, diffs, 1)
+	assert.Equal(t, diffs[0].Columns, []string{"name"})
+	assert.Equal(t, diffs[0].A[1], v(""))
+	assert.Equal(t, diffs[0].B[1], null)
+}
@@ -0,0 +1,20 @@
+/*
+Package mysqltest contains helpers for testing code
+which uses mysqldriver package.
+
+DiffResultSets reads two result sets and reports rows which differ.
+It's useful to verify that the data isn't changed, e.g. by migration:
+
+	before, _ := conn.Query("SELECT id, name FROM dogs")
+	// read the result set before the migration
+	...
+	diffs, err := mysqltest.DiffResultSets(before, after, "id")
+	for _, diff := range diffs {
+		fmt.Println(diff)
+	}
+
+RecordHandshake and WriteHandshake allow a mock server to replay
+the initial handshake of a recorded session, so the connection id
+and the scramble are the same as in the recording.
+*/
+package mysqltest
@@ -0,0 +1,41 @@
+package mysqltest
+
+import (
+	"io"
+
+	"github.com/pubnative/mysqldriver-go"
+)
+
+// Handshake is the initial handshake of the recorded session
+type Handshake struct {
+	ConnectionID uint32 // thread ID of the session
+	Scramble     []byte // random seed used to authenticate the client
+	Payload      []byte // payload of the handshake packet as it's sent by the server
+}
+
+// RecordHandshake returns the handshake received by the connection,
+// so it can be stored and replayed later by WriteHandshake
+func RecordHandshake(conn *mysqldriver.Conn) Handshake {
+	info := conn.HandshakeInfo()
+	return Handshake{
+		ConnectionID: info.ConnectionID,
+		Scramble:     info.Scramble,
+		Payload:      conn.HandshakePacket(),
+	}
+}
+
+// WriteHandshake sends the recorded handshake packet to the client
+// the same way as the server did. It's used by a mock server
+// to replay the session with the same connection id and scramble.
+//
+//	client, server := net.Pipe()
+//	go mysqltest.WriteHandshake(server, recorded)
+func WriteHandshake(w io.Writer, handshake Handshake) error {
+	length := len(handshake.Payload)
+	packet := make([]byte, 4, 4+length)
+	packet[0], packet[1], packet[2] = byte(length), byte(length>>8), byte(length>>16)
+	packet = append(packet, handshake.Payload...) // sequence id is 0
+
+	_, err := w.Write(packet)
+	return err
+}
@@ -0,0 +1,32 @@
+package mysqltest
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/pubnative/mysqldriver-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteHandshake(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteHandshake(&buf, Handshake{Payload: []byte{0x0a, '8', 0x00}})
+	assert.NoError(t, err)
+	assert.Equal(t, buf.Bytes(), []byte{0x03, 0x00, 0x00, 0x00, 0x0a, '8', 0x00})
+}
+
+func TestRecordHandshake(t *testing.T) {
+	conn, err := mysqldriver.NewConn("root", "", "tcp", "127.0.0.1:3306", "test", time.Duration(0))
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	handshake := RecordHandshake(conn)
+	assert.Equal(t, handshake.ConnectionID, conn.HandshakeInfo().ConnectionID)
+	assert.Equal(t, len(handshake.Scramble), 20)
+	assert.Equal(t, handshake.Payload[0], byte(10))
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteHandshake(&buf, handshake))
+	assert.Equal(t, buf.Bytes()[4:], handshake.Payload)
+}
@@ -0,0 +1,203 @@
+package mysqldriver
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// ErrQueryTimeout is returned when query isn't completed within
+// QueryTimeout of the connection. Connection can't be used after that.
+var ErrQueryTimeout = errors.New("mysqldriver: query timeout exceeded")
+
+// netConn wraps network connection used by the protocol stream.
+// It allows to set the deadline for the whole query
+// which can't be extended by the read timeout of the stream.
+type netConn struct {
+	net.Conn
+
+	// deadlines are set by the stream and by QueryContext
+	// when its context is canceled in another go-routine
+	mu            sync.Mutex
+	readDeadline  time.Time // deadlines requested by the stream
+	writeDeadline time.Time
+	queryDeadline time.Time
+	interrupted   bool // deadlines can't be changed after interrupt
+
+	bytesRead    uint64 // updated atomically
+	bytesWritten uint64
+
+	// the first packet sent by the server is captured
+	// to provide HandshakeInfo after connecting
+	capture bool
+	first   []byte
+
+	// sequence IDs are verified when Options.StrictSequence is set
+	strictSequence bool
+	reads, writes  packetScanner
+	nextSeq        byte // expected sequence ID of the next packet
+	pipelined      int  // number of pipelined responses following the current one
+
+	// packets are compressed after the handshake
+	// when Options.Compress is set
+	compression      *compression
+	compressionLevel int              // level of the written packets, zero for default
+	zstdCodec        CompressionCodec // requests zstd when it's set
+
+	// every write is limited by Options.WriteTimeout
+	writeTimeout time.Duration
+}
+
+func (c *netConn) Read(b []byte) (int, error) {
+	var n int
+	var err error
+	if c.compression != nil {
+		n, err = c.compression.Read(b)
+	} else {
+		n, err = wire{c}.Read(b)
+	}
+	if c.strictSequence {
+		if errSeq := c.checkSequence(b[:n]); errSeq != nil {
+			return 0, errSeq
+		}
+	}
+	if c.capture {
+		c.first = append(c.first, b[:n]...)
+		if len(c.first) >= 4 && len(c.first)-4 >= packetLength(c.first) {
+			c.capture = false
+		}
+	}
+	return n, err
+}
+
+func (c *netConn) Write(b []byte) (int, error) {
+	if c.writeTimeout > 0 {
+		if err := c.SetWriteDeadline(time.Now().Add(c.writeTimeout)); err != nil {
+			return 0, err
+		}
+	}
+
+	var n int
+	var err error
+	if c.compression != nil {
+		n, err = c.compression.Write(b)
+	} else {
+		n, err = wire{c}.Write(b)
+	}
+	if c.strictSequence {
+		c.trackSequence(b[:n])
+	}
+	return n, err
+}
+
+// firstPacket returns payload of the first packet sent by the server.
+// It returns nil when packet hasn't been read completely.
+func (c *netConn) firstPacket() []byte {
+	if c.capture || len(c.first) < 4 {
+		return nil
+	}
+	return c.first[4 : 4+packetLength(c.first)]
+}
+
+func (c *netConn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.SetWriteDeadline(t)
+}
+
+func (c *netConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.interrupted {
+		return nil
+	}
+	c.readDeadline = t
+	return c.Conn.SetReadDeadline(earliest(t, c.queryDeadline))
+}
+
+func (c *netConn) SetWriteDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.interrupted {
+		return nil
+	}
+	c.writeDeadline = t
+	return c.Conn.SetWriteDeadline(earliest(t, c.queryDeadline))
+}
+
+// setQueryDeadline limits both reads and writes by the deadline.
+// Zero value removes the limit.
+func (c *netConn) setQueryDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.interrupted {
+		return nil
+	}
+	c.queryDeadline = t
+	if err := c.Conn.SetReadDeadline(earliest(c.readDeadline, t)); err != nil {
+		return err
+	}
+	return c.Conn.SetWriteDeadline(earliest(c.writeDeadline, t))
+}
+
+// interrupt aborts pending and all future reads and writes
+func (c *netConn) interrupt() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interrupted = true
+	now := time.Now()
+	c.Conn.SetReadDeadline(now)
+	c.Conn.SetWriteDeadline(now)
+}
+
+// queryDeadlineExceeded reports whether err is caused by the query deadline
+func (c *netConn) queryDeadlineExceeded(err error) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout() &&
+		!c.queryDeadline.IsZero() && !time.Now().Before(c.queryDeadline)
+}
+
+// earliest returns the earliest of non-zero times
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
+
+// startQueryTimeout sets the deadline of the query
+// when QueryTimeout of the connection is configured
+func (c *Conn) startQueryTimeout() error {
+	if c.QueryTimeout <= 0 || c.netConn == nil {
+		return nil
+	}
+	return c.netConn.setQueryDeadline(time.Now().Add(c.QueryTimeout))
+}
+
+// stopQueryTimeout removes the deadline of the query
+func (c *Conn) stopQueryTimeout() {
+	if c.netConn != nil && !c.netConn.queryDeadline.IsZero() {
+		c.netConn.setQueryDeadline(time.Time{})
+	}
+}
+
+// timeoutError replaces network timeout error caused
+// by QueryTimeout with ErrQueryTimeout, the error
+// caused by canceled context of QueryContext with ctx.Err()
+// and the error caused by Rows.Interrupt with ErrInterrupted
+func (c *Conn) timeoutError(err error) error {
+	if ctxErr := c.contextError(); ctxErr != nil {
+		return ctxErr
+	}
+	if intErr := c.interruptError(); intErr != nil {
+		return intErr
+	}
+	if c.netConn != nil && c.netConn.queryDeadlineExceeded(err) {
+		return ErrQueryTimeout
+	}
+	return err
+}
@@ -0,0 +1,157 @@
+package mysqldriver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+type deadlineRecorder struct {
+	stream
+	read, write time.Time
+}
+
+func (d *deadlineRecorder) SetReadDeadline(t time.Time) error  { d.read = t; return nil }
+func (d *deadlineRecorder) SetWriteDeadline(t time.Time) error { d.write = t; return nil }
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+var _ net.Error = timeoutError{}
+
+func TestNetConnQueryDeadlineLimitsStreamDeadlines(t *testing.T) {
+	rec := &deadlineRecorder{}
+	conn := &netConn{Conn: rec}
+	now := time.Now()
+
+	assert.NoError(t, conn.SetReadDeadline(now.Add(time.Minute)))
+	assert.Equal(t, rec.read, now.Add(time.Minute))
+
+	assert.NoError(t, conn.setQueryDeadline(now.Add(time.Second)))
+	assert.Equal(t, rec.read, now.Add(time.Second))
+	assert.Equal(t, rec.write, now.Add(time.Second))
+
+	// stream can't extend the query deadline
+	assert.NoError(t, conn.SetDeadline(now.Add(time.Hour)))
+	assert.Equal(t, rec.read, now.Add(time.Second))
+	assert.Equal(t, rec.write, now.Add(time.Second))
+
+	// but it can shorten it
+	assert.NoError(t, conn.SetReadDeadline(now.Add(time.Millisecond)))
+	assert.Equal(t, rec.read, now.Add(time.Millisecond))
+
+	// deadlines of the stream are restored
+	assert.NoError(t, conn.setQueryDeadline(time.Time{}))
+	assert.Equal(t, rec.read, now.Add(time.Millisecond))
+	assert.Equal(t, rec.write, now.Add(time.Hour))
+}
+
+func TestConnTimeoutError(t *testing.T) {
+	conn := &Conn{netConn: &netConn{Conn: &deadlineRecorder{}}}
+	assert.Equal(t, conn.timeoutError(timeoutError{}), timeoutError{})
+
+	conn.netConn.setQueryDeadline(time.Now().Add(-time.Second))
+	assert.Equal(t, conn.timeoutError(timeoutError{}), ErrQueryTimeout)
+
+	err := net.UnknownNetworkError("unknown")
+	assert.Equal(t, conn.timeoutError(err), err)
+}
+
+func TestNetConnWriteTimeout(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	conn := &netConn{Conn: client, writeTimeout: 10 * time.Millisecond}
+	c := &Conn{conn: mysqlproto.Conn{Stream: mysqlproto.NewStream(conn, 0)}, netConn: conn, valid: true}
+
+	// nobody reads from the server side of the pipe
+	_, err := c.Exec("DELETE FROM dogs")
+	netErr, ok := err.(net.Error)
+	assert.True(t, ok)
+	assert.True(t, netErr.Timeout())
+	assert.False(t, c.valid)
+}
+
+func TestNewConnOptionsConnectTimeout(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		// server accepts the connection, but doesn't send the handshake
+		conn, err := listener.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(time.Second)
+		}
+	}()
+
+	start := time.Now()
+	_, err = NewConnOptions(context.Background(), "root", "", "tcp", listener.Addr().String(), "test", time.Minute,
+		Options{ConnectTimeout: 50 * time.Millisecond})
+	netErr, ok := err.(net.Error)
+	assert.True(t, ok)
+	assert.True(t, netErr.Timeout())
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestConnQueryTimeout(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:3306)/test", 1, time.Duration(0))
+	conn, err := db.GetConn()
+	assert.NoError(t, err)
+
+	conn.QueryTimeout = 100 * time.Millisecond
+	rows, err := conn.Query("SELECT 1 UNION ALL SELECT SLEEP(1)")
+	if err == nil {
+		for rows.Next() {
+		}
+		err = rows.LastError()
+	}
+	assert.Equal(t, err, ErrQueryTimeout)
+	assert.False(t, conn.valid)
+	assert.Nil(t, db.PutConn(conn))
+	assert.True(t, conn.closed)
+}
+
+func TestConnQueryTimeoutIsResetAfterQuery(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		conn.QueryTimeout = 200 * time.Millisecond
+		for i := 0; i < 3; i++ {
+			rows, err := conn.Query("SELECT SLEEP(0.1)")
+			assert.NoError(t, err)
+			for rows.Next() {
+			}
+			assert.NoError(t, rows.LastError())
+		}
+
+		_, err := conn.Exec("DO SLEEP(0.1)")
+		assert.NoError(t, err)
+		assert.True(t, conn.valid)
+	})
+}
+
+func TestConnBytesSentAndReceived(t *testing.T) {
+	payloads := [][]byte{{0x01}, columnDefinition("id"), eofPacket, {0x01, '1'}, eofPacket}
+	conn := newPipeConn(payloads...)
+	assert.Equal(t, conn.BytesSent(), uint64(0))
+	assert.Equal(t, conn.BytesReceived(), uint64(0))
+
+	rows, err := conn.Query("SELECT id FROM people")
+	assert.NoError(t, err)
+	for rows.Next() {
+	}
+	assert.NoError(t, rows.LastError())
+
+	var received int
+	for _, payload := range payloads {
+		received += 4 + len(payload)
+	}
+	sent := len(mysqlproto.ComQueryRequest([]byte("SELECT id FROM people")))
+	assert.Equal(t, conn.BytesSent(), uint64(sent))
+	assert.Equal(t, conn.BytesReceived(), uint64(received))
+}
@@ -0,0 +1,64 @@
+package mysqldriver
+
+// OutParams returns values of OUT and INOUT parameters of the stored
+// procedure called by the last execution of the statement. Server sends
+// them in the result set following the result sets of the procedure
+// which is flagged with SERVER_PS_OUT_PARAMS, the driver reads it
+// instead of returning it as the next result set. Values are in the
+// order of the parameters and converted the same way as Any does.
+// It's nil until all result sets are read or when the statement
+// isn't CALL of the procedure with OUT or INOUT parameters.
+//
+//	stmt, _ := conn.Prepare("CALL dogs_count(?, ?)") // IN age INT, OUT total INT
+//	_, err := stmt.Exec(3, nil)
+//	stmt.OutParams() // [int64(42)]
+//
+// Parameters are returned only for CALL performed by the prepared
+// statement, Query of the connection doesn't support them.
+func (s *Stmt) OutParams() []interface{} {
+	return s.outParams
+}
+
+// readOutParams reads the row of the result set
+// of OUT parameters sent by the server after
+// the result sets of the procedure
+func (s *Stmt) readOutParams(rows *Rows) error {
+	rows.binary = &binaryDecoder{}
+	for {
+		packet, err := rows.readRow()
+		if err != nil {
+			return rows.conn.readError(err)
+		}
+		if packet == nil {
+			return nil
+		}
+
+		rows.setRow(packet)
+		params := make([]interface{}, len(rows.definitions))
+		for i := range params {
+			params[i] = rows.Any()
+		}
+		if rows.errParse != nil {
+			return rows.errParse
+		}
+		s.outParams = params
+	}
+}
+
+// readResultSet reads the next result set of the statement.
+// Result set of OUT parameters of the prepared statement is read
+// into the statement and the result following it is returned instead.
+func (r *Rows) readResultSet() (*Rows, error) {
+	for {
+		next, err := r.conn.readResultSet()
+		if err != nil || next.eof || r.stmt == nil || r.conn.status&serverPSOutParams == 0 {
+			return next, err
+		}
+		if err := r.stmt.readOutParams(next); err != nil {
+			return nil, err
+		}
+		if !next.moreResults {
+			return &Rows{conn: r.conn, eof: true}, nil
+		}
+	}
+}
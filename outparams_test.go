@@ -0,0 +1,121 @@
+package mysqldriver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// outParamDefinition is the column of OUT parameter "total INT"
+// sent by MySQL 8.0 in the result set of the OUT parameters
+var outParamDefinition = []byte{
+	0x03, 'd', 'e', 'f',
+	0x00, 0x00, 0x00,
+	0x05, 't', 'o', 't', 'a', 'l',
+	0x00,
+	0x0c,
+	0x3f, 0x00, // binary
+	0x0b, 0x00, 0x00, 0x00, // max length
+	fieldTypeLong,
+	0x00, 0x00,
+	0x00,
+	0x00, 0x00, // filler
+}
+
+// outParamsEOF terminates the result set of the OUT parameters:
+// SERVER_PS_OUT_PARAMS, SERVER_MORE_RESULTS_EXISTS, SERVER_STATUS_AUTOCOMMIT
+var outParamsEOF = []byte{0xfe, 0x00, 0x00, 0x0a, 0x10}
+
+// moreResultsEOF terminates the result set followed by another one
+var moreResultsEOF = []byte{0xfe, 0x00, 0x00, 0x0a, 0x00}
+
+func TestStmtExecReadsOutParams(t *testing.T) {
+	// CALL dogs_count(?, ?) with IN age INT, OUT total INT
+	conn := newPacketConn(
+		[]byte{0x01},
+		outParamDefinition,
+		outParamsEOF,
+		[]byte{0x00, 0x00, 0x2a, 0x00, 0x00, 0x00},
+		outParamsEOF,
+		okPayload,
+	)
+	stmt := &Stmt{conn: conn, id: 1, params: make([]ColumnInfo, 2)}
+
+	_, err := stmt.Exec(3, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, stmt.OutParams(), []interface{}{int64(42)})
+	assert.Equal(t, conn.acquire(), nil)
+}
+
+func TestStmtQueryReadsOutParamsAfterResultSets(t *testing.T) {
+	// procedure selects the names and counts them into OUT parameter
+	conn := newPacketConn(
+		[]byte{0x01},
+		columnDefinition("name"),
+		eofPacket,
+		[]byte{0x00, 0x00, 0x03, 'b', 'o', 'b'},
+		moreResultsEOF,
+		[]byte{0x01},
+		outParamDefinition,
+		outParamsEOF,
+		[]byte{0x00, 0x04}, // total is NULL
+		outParamsEOF,
+		okPayload,
+	)
+	stmt := &Stmt{conn: conn, id: 1}
+
+	rows, err := stmt.Query()
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.String(), "bob")
+	assert.False(t, rows.Next())
+	assert.Nil(t, stmt.OutParams())
+
+	assert.False(t, rows.NextResultSet())
+	assert.NoError(t, rows.LastError())
+	assert.Equal(t, stmt.OutParams(), []interface{}{nil})
+	assert.Equal(t, conn.acquire(), nil)
+}
+
+func TestStmtExecDiscardsResultSetsAndReadsOutParams(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01},
+		columnDefinition("name"),
+		eofPacket,
+		[]byte{0x00, 0x00, 0x03, 'b', 'o', 'b'},
+		moreResultsEOF,
+		[]byte{0x01},
+		outParamDefinition,
+		outParamsEOF,
+		[]byte{0x00, 0x00, 0x01, 0x00, 0x00, 0x00},
+		outParamsEOF,
+		okPayload,
+	)
+	stmt := &Stmt{conn: conn, id: 1}
+
+	_, err := stmt.Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, stmt.OutParams(), []interface{}{int64(1)})
+	assert.Equal(t, conn.acquire(), nil)
+}
+
+func TestStmtOutParamsOfProcedure(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec("DROP PROCEDURE IF EXISTS people_count")
+		assert.NoError(t, err)
+		_, err = conn.Exec("CREATE PROCEDURE people_count(IN min_age INT, OUT total INT) " +
+			"SELECT COUNT(*) INTO total FROM people WHERE age >= min_age")
+		assert.NoError(t, err)
+		defer conn.Exec("DROP PROCEDURE people_count")
+		_, err = conn.Exec("INSERT INTO people(firstname, age) VALUES ('bob', 20), ('ben', 30)")
+		assert.NoError(t, err)
+
+		stmt, err := conn.Prepare("CALL people_count(?, ?)")
+		assert.NoError(t, err)
+		defer stmt.Close()
+
+		_, err = stmt.Exec(25, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, stmt.OutParams(), []interface{}{int64(1)})
+	})
+}
@@ -0,0 +1,139 @@
+package mysqldriver
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/pubnative/mysqlproto-go"
+)
+
+// Command codes
+// (see https://dev.mysql.com/doc/internals/en/text-protocol.html)
+const (
+	comQuit            byte = 0x01
+	comDebug           byte = 0x0d
+	comPing            byte = 0x0e
+	comChangeUser      byte = 0x11
+	comBinlogDump      byte = 0x12
+	comRegisterSlave   byte = 0x15
+	comStmtPrepare     byte = 0x16
+	comStmtExecute     byte = 0x17
+	comStmtClose       byte = 0x19
+	comStmtFetch       byte = 0x1c
+	comResetConnection byte = 0x1f
+)
+
+var errMalformedPacket = errors.New("mysqldriver: malformed packet")
+
+// commandPacket creates a packet of the command
+// which always starts new sequence of packets
+func commandPacket(command byte, payload []byte) []byte {
+	length := 1 + len(payload)
+	packet := make([]byte, 4, 4+length)
+	packet[0] = byte(length)
+	packet[1] = byte(length >> 8)
+	packet[2] = byte(length >> 16)
+	packet[3] = 0 // sequence ID
+	packet = append(packet, command)
+	return append(packet, payload...)
+}
+
+// packetLength returns payload length from the packet header
+func packetLength(header []byte) int {
+	return int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+}
+
+// isEOFPacket reports whether payload is EOF_PACKET. EOF_PACKET has the same
+// header as the length encoded integer of 8 bytes, so length is checked too.
+func isEOFPacket(payload []byte) bool {
+	return len(payload) > 0 && len(payload) < 9 && payload[0] == mysqlproto.EOF_PACKET
+}
+
+// readLengthEncodedInteger reads integer at the offset and returns
+// the offset of the next value
+// (see https://dev.mysql.com/doc/internals/en/integer.html#length-encoded-integer)
+func readLengthEncodedInteger(data []byte, offset int) (uint64, int, error) {
+	if offset >= len(data) {
+		return 0, 0, errMalformedPacket
+	}
+
+	var size int
+	switch data[offset] {
+	case 0xfc:
+		size = 2
+	case 0xfd:
+		size = 3
+	case 0xfe:
+		size = 8
+	case 0xfb, 0xff:
+		return 0, 0, errMalformedPacket
+	default:
+		return uint64(data[offset]), offset + 1, nil
+	}
+
+	if offset+1+size > len(data) {
+		return 0, 0, errMalformedPacket
+	}
+
+	var num uint64
+	for i := size; i > 0; i-- {
+		num = num<<8 | uint64(data[offset+i])
+	}
+	return num, offset + 1 + size, nil
+}
+
+// appendLengthEncodedInteger appends integer encoded
+// the same way as readLengthEncodedInteger reads it
+func appendLengthEncodedInteger(data []byte, num uint64) []byte {
+	switch {
+	case num < 0xfb:
+		return append(data, byte(num))
+	case num <= 0xffff:
+		return append(data, 0xfc, byte(num), byte(num>>8))
+	case num <= 0xffffff:
+		return append(data, 0xfd, byte(num), byte(num>>8), byte(num>>16))
+	default:
+		data = append(data, 0xfe)
+		for i := uint(0); i < 64; i += 8 {
+			data = append(data, byte(num>>i))
+		}
+		return data
+	}
+}
+
+// appendLengthEncodedString appends string encoded
+// the same way as readLengthEncodedString reads it
+func appendLengthEncodedString(data []byte, value string) []byte {
+	data = appendLengthEncodedInteger(data, uint64(len(value)))
+	return append(data, value...)
+}
+
+// readLengthEncodedString reads string at the offset and returns
+// the offset of the next value. The returned value references
+// the data slice without copying
+// (see https://dev.mysql.com/doc/internals/en/string.html#length-encoded-string)
+func readLengthEncodedString(data []byte, offset int) ([]byte, int, error) {
+	length, offset, err := readLengthEncodedInteger(data, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	if uint64(len(data)-offset) < length {
+		return nil, 0, errMalformedPacket
+	}
+	end := offset + int(length)
+	return data[offset:end], end, nil
+}
+
+func readUint16(data []byte, offset int) (uint16, int, error) {
+	if offset+2 > len(data) {
+		return 0, 0, errMalformedPacket
+	}
+	return binary.LittleEndian.Uint16(data[offset:]), offset + 2, nil
+}
+
+func readUint32(data []byte, offset int) (uint32, int, error) {
+	if offset+4 > len(data) {
+		return 0, 0, errMalformedPacket
+	}
+	return binary.LittleEndian.Uint32(data[offset:]), offset + 4, nil
+}
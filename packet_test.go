@@ -0,0 +1,74 @@
+package mysqldriver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandPacket(t *testing.T) {
+	assert.Equal(t, commandPacket(comStmtClose, []byte{0x01, 0x00, 0x00, 0x00}),
+		[]byte{0x05, 0x00, 0x00, 0x00, 0x19, 0x01, 0x00, 0x00, 0x00})
+}
+
+func TestIsEOFPacket(t *testing.T) {
+	assert.True(t, isEOFPacket([]byte{0xfe, 0x00, 0x00, 0x02, 0x00}))
+	assert.False(t, isEOFPacket([]byte{0xfe, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}))
+	assert.False(t, isEOFPacket([]byte{0x00}))
+	assert.False(t, isEOFPacket(nil))
+}
+
+func TestReadLengthEncodedInteger(t *testing.T) {
+	cases := []struct {
+		data   []byte
+		num    uint64
+		offset int
+	}{
+		{[]byte{0xfa}, 250, 1},
+		{[]byte{0xfc, 0xfb, 0x00}, 251, 3},
+		{[]byte{0xfd, 0x01, 0x02, 0x03}, 0x030201, 4},
+		{[]byte{0xfe, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}, 0x0807060504030201, 9},
+	}
+
+	for _, c := range cases {
+		num, offset, err := readLengthEncodedInteger(c.data, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, num, c.num)
+		assert.Equal(t, offset, c.offset)
+	}
+
+	_, _, err := readLengthEncodedInteger([]byte{0xfc, 0x01}, 0)
+	assert.Equal(t, err, errMalformedPacket)
+	_, _, err = readLengthEncodedInteger([]byte{0xfb}, 0)
+	assert.Equal(t, err, errMalformedPacket)
+	_, _, err = readLengthEncodedInteger([]byte{}, 0)
+	assert.Equal(t, err, errMalformedPacket)
+}
+
+func TestReadLengthEncodedString(t *testing.T) {
+	data := []byte{0x03, 'd', 'e', 'f', 0x00, 0x02, 'i', 'd'}
+	value, offset, err := readLengthEncodedString(data, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, string(value), "def")
+	value, offset, err = readLengthEncodedString(data, offset)
+	assert.NoError(t, err)
+	assert.Equal(t, string(value), "")
+	value, offset, err = readLengthEncodedString(data, offset)
+	assert.NoError(t, err)
+	assert.Equal(t, string(value), "id")
+	assert.Equal(t, offset, len(data))
+
+	_, _, err = readLengthEncodedString([]byte{0x03, 'd'}, 0)
+	assert.Equal(t, err, errMalformedPacket)
+}
+
+func TestAppendLengthEncodedInteger(t *testing.T) {
+	for _, num := range []uint64{0, 0xfa, 0xfb, 0xffff, 0x10000, 0xffffff, 0x1000000, 1<<64 - 1} {
+		data := appendLengthEncodedInteger(nil, num)
+		read, offset, err := readLengthEncodedInteger(data, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, read, num)
+		assert.Equal(t, offset, len(data))
+	}
+	assert.Equal(t, appendLengthEncodedString([]byte{0x01}, "ab"), []byte{0x01, 0x02, 'a', 'b'})
+}
@@ -0,0 +1,162 @@
+package mysqldriver
+
+import (
+	"errors"
+
+	"github.com/pubnative/mysqlproto-go"
+)
+
+// ErrEmptyPipeline is returned by Pipeline.Execute
+// when no statements are added to the pipeline
+var ErrEmptyPipeline = errors.New("mysqldriver: pipeline has no statements")
+
+// Pipeline sends several statements to the server without waiting
+// for the response of each of them, so the round-trip time is paid
+// once for all statements instead of once per statement.
+// Unlike multi-statements, every statement is a separate COM_QUERY
+// command with its own result set and error.
+//
+//	p := conn.Pipeline()
+//	p.Add("UPDATE dogs SET age = age + 1")
+//	p.Add("SELECT name FROM dogs")
+//	results, err := p.Execute()
+//	if err != nil {
+//		// statements couldn't be sent
+//	}
+//	for _, res := range results {
+//		if res.Err != nil {
+//			// statement failed
+//		}
+//	}
+//
+// Requests are written before any response is read, so Pipeline
+// is meant for batches of small statements. Total size of the batch
+// shouldn't exceed the socket buffers, otherwise both the server
+// and the client may block writing to each other.
+type Pipeline struct {
+	conn       *Conn
+	statements []string
+}
+
+// PipelineResult is the result of a single statement of the pipeline
+type PipelineResult struct {
+	SQL  string              // statement as it's added to the pipeline
+	OK   mysqlproto.OKPacket // OK_PACKET of the statement without result set
+	Rows *BufferedRows       // rows of the statement, nil when statement failed
+	Err  error               // error of the statement
+}
+
+// Pipeline returns a new empty pipeline of the connection
+func (c *Conn) Pipeline() *Pipeline {
+	return &Pipeline{conn: c}
+}
+
+// Add appends the statement to the pipeline. Statement is sent
+// only when Execute is called.
+func (p *Pipeline) Add(sql string) {
+	p.statements = append(p.statements, sql)
+}
+
+// Len returns the number of statements of the pipeline
+func (p *Pipeline) Len() int {
+	return len(p.statements)
+}
+
+// Execute writes all statements to the server at once and then reads
+// their responses in the same order. Results are returned in the order
+// statements are added, one per statement. Result sets are read
+// into memory completely (see func (Conn) QueryBuffered).
+//
+// Failure of a statement, e.g. ERR_PACKET or PacketTooLargeError
+// for the statement which isn't sent, is returned by Err of its result
+// and doesn't affect the rest of the statements. When the stream is
+// broken, connection becomes invalid and the read error is returned
+// by all results which haven't been read. Returned error is non-nil
+// only when statements couldn't be sent, results are nil in this case.
+//
+// Statements of the pipeline are cleared, so it can be reused.
+func (p *Pipeline) Execute() ([]PipelineResult, error) {
+	if len(p.statements) == 0 {
+		return nil, ErrEmptyPipeline
+	}
+
+	c := p.conn
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	results := make([]PipelineResult, len(p.statements))
+	var requests []byte
+	sent := 0
+	for i, sql := range p.statements {
+		results[i].SQL = sql
+		if c.DefaultSchema != "" {
+			sql = qualifyTables(sql, c.DefaultSchema)
+		}
+		if err := c.checkPacketSize(len(sql)); err != nil {
+			results[i].Err = err
+			continue
+		}
+		requests = append(requests, mysqlproto.ComQueryRequest([]byte(sql))...)
+		sent++
+	}
+	p.statements = nil
+
+	if sent == 0 {
+		return results, nil
+	}
+
+	if err := c.startQueryTimeout(); err != nil {
+		c.valid = false
+		return nil, err
+	}
+
+	if c.netConn != nil {
+		c.netConn.pipelined = sent - 1
+		defer func() { c.netConn.pipelined = 0 }()
+	}
+
+	if _, err := c.conn.Write(requests); err != nil {
+		c.valid = false
+		return nil, c.timeoutError(err)
+	}
+
+	var errStream error // error of the broken stream
+	for i := range results {
+		res := &results[i]
+		if res.Err != nil {
+			continue
+		}
+		if errStream != nil {
+			res.Err = errStream
+			continue
+		}
+
+		res.Rows, res.OK, res.Err = c.readPipelineResult()
+		if !c.valid {
+			errStream = res.Err
+		}
+	}
+
+	return results, nil
+}
+
+// readPipelineResult reads the response to a single statement of the pipeline
+func (c *Conn) readPipelineResult() (*BufferedRows, mysqlproto.OKPacket, error) {
+	rows, err := c.readResultSet()
+	if err != nil {
+		return nil, mysqlproto.OKPacket{}, c.readError(err)
+	}
+
+	var ok mysqlproto.OKPacket
+	if rows.eof {
+		ok = c.lastOK
+	} else if err := rows.bufferRows(); err != nil {
+		return nil, mysqlproto.OKPacket{}, err
+	}
+
+	rows.eof = false
+	rows.buffered = true
+	return &BufferedRows{Rows: *rows}, ok, nil
+}
@@ -0,0 +1,116 @@
+package mysqldriver
+
+import (
+	"testing"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelineExecuteReadsResponsesInOrder(t *testing.T) {
+	okPacket := []byte{mysqlproto.OK_PACKET, 0x01, 0x00, 0x02, 0x00, 0x00, 0x00}
+	errPacket := append([]byte{mysqlproto.ERR_PACKET, 0x7a, 0x04, '#'}, "42S02Table doesn't exist"...)
+	conn := newPacketConn(
+		okPacket,
+		errPacket,
+		[]byte{0x01}, columnDefinition("name"), eofPacket,
+		[]byte{0x03, 'M', 'a', 'x'},
+		eofPacket,
+	)
+
+	p := conn.Pipeline()
+	p.Add("UPDATE dogs SET age = age + 1")
+	p.Add("SELECT * FROM unknown")
+	p.Add("SELECT name FROM dogs")
+	assert.Equal(t, p.Len(), 3)
+
+	results, err := p.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, len(results), 3)
+	assert.Equal(t, p.Len(), 0)
+
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, results[0].SQL, "UPDATE dogs SET age = age + 1")
+	assert.False(t, results[0].Rows.Next())
+
+	_, ok := results[1].Err.(mysqlproto.ERRPacket)
+	assert.True(t, ok)
+	assert.Nil(t, results[1].Rows)
+
+	rows := results[2].Rows
+	assert.NoError(t, results[2].Err)
+	assert.Equal(t, rows.Len(), 1)
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.String(), "Max")
+	assert.False(t, rows.Next())
+
+	assert.True(t, conn.valid)
+	assert.NoError(t, conn.acquire())
+}
+
+func TestPipelineExecuteSkipsTooLargeStatement(t *testing.T) {
+	okPacket := []byte{mysqlproto.OK_PACKET, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00}
+	conn := newPacketConn(okPacket)
+	conn.maxAllowedPacket = 16
+
+	p := conn.Pipeline()
+	p.Add("SELECT 'too long to be sent'")
+	p.Add("DO 1")
+
+	results, err := p.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, results[0].Err, &PacketTooLargeError{Size: 29, MaxAllowed: 16})
+	assert.NoError(t, results[1].Err)
+}
+
+func TestPipelineExecuteFailsRestOfStatementsWhenStreamIsBroken(t *testing.T) {
+	conn := newPacketConn([]byte{0x02}, columnDefinition("id"))
+
+	p := conn.Pipeline()
+	p.Add("SELECT id, name FROM dogs")
+	p.Add("DO 1")
+
+	results, err := p.Execute()
+	assert.NoError(t, err)
+	assert.NotNil(t, results[0].Err)
+	assert.Equal(t, results[1].Err, results[0].Err)
+	assert.False(t, conn.valid)
+}
+
+func TestPipelineExecuteWithoutStatements(t *testing.T) {
+	_, err := newPacketConn().Pipeline().Execute()
+	assert.Equal(t, err, ErrEmptyPipeline)
+}
+
+func TestCheckSequenceAcceptsPipelinedResponses(t *testing.T) {
+	c := &netConn{nextSeq: 1, pipelined: 1}
+	data := []byte{0x01, 0x00, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x00}
+	data = append(data, 0x01, 0x00, 0x00, 0x01, 0x00)
+	assert.NoError(t, c.checkSequence(data))
+	assert.Equal(t, c.pipelined, 0)
+
+	err := c.checkSequence([]byte{0x01, 0x00, 0x00, 0x01, 0x00})
+	assert.Equal(t, err, &SequenceError{Expected: 2, Received: 1})
+}
+
+func TestPipelineDB(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		p := conn.Pipeline()
+		p.Add(`INSERT INTO people(firstname) VALUES ("bob")`)
+		p.Add("SELECT firstname FROM people ORDER BY id LIMIT 1")
+		p.Add("SELECT * FROM unknown_table")
+		p.Add("SELECT COUNT(*) FROM people")
+
+		results, err := p.Execute()
+		assert.NoError(t, err)
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, results[0].OK.AffectedRows, uint64(1))
+		assert.NoError(t, results[1].Err)
+		assert.True(t, results[1].Rows.Next())
+		assert.Equal(t, results[1].Rows.String(), "bob")
+		assert.NotNil(t, results[2].Err)
+		assert.NoError(t, results[3].Err)
+		assert.True(t, results[3].Rows.Next())
+		assert.Equal(t, results[3].Rows.Int(), 1)
+	})
+}
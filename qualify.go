@@ -0,0 +1,244 @@
+package mysqldriver
+
+import (
+	"strings"
+)
+
+// qualifiedStatements are statements which table names
+// are qualified with Conn.DefaultSchema
+var qualifiedStatements = []string{"SELECT", "INSERT", "REPLACE", "UPDATE", "DELETE", "WITH"}
+
+// tableModifiers can precede the table name
+var tableModifiers = []string{"LOW_PRIORITY", "IGNORE"}
+
+// notTables are words which can follow FROM, JOIN
+// or INTO keyword but aren't table names
+var notTables = []string{"DUAL", "OUTFILE", "DUMPFILE", "LATERAL"}
+
+// notAliases are keywords which can follow the table name
+// in the list of tables but aren't aliases
+var notAliases = []string{
+	"WHERE", "SET", "ON", "USING", "PARTITION", "VALUES", "VALUE", "SELECT", "TABLE",
+	"JOIN", "INNER", "CROSS", "LEFT", "RIGHT", "NATURAL", "STRAIGHT_JOIN",
+	"GROUP", "HAVING", "WINDOW", "ORDER", "LIMIT", "UNION", "EXCEPT", "INTERSECT",
+	"FOR", "LOCK", "USE", "FORCE", "IGNORE", "INTO", "AS", "DEFAULT",
+}
+
+// sqlToken is a word, quoted identifier or punctuation
+// character of the statement. Strings and comments are skipped.
+type sqlToken struct {
+	start, end int
+	kind       byte // 'w' for words, '`' for quoted identifiers, the character otherwise
+}
+
+// qualifyTable qualifies the table name with the schema
+// unless it's already qualified
+func qualifyTable(table, schema string) string {
+	if strings.Contains(table, ".") {
+		return table
+	}
+	return schema + "." + table
+}
+
+// qualifyTables qualifies bare table names following FROM, JOIN,
+// INTO and UPDATE keywords with the schema. It's a heuristic
+// which doesn't parse SQL: names of common table expressions,
+// subqueries and FROM used by functions like EXTRACT are detected,
+// statements except of SELECT, INSERT, REPLACE, UPDATE, DELETE
+// and WITH are returned as they are.
+func qualifyTables(sql, schema string) string {
+	tokens := tokenizeSQL(sql)
+	if len(tokens) == 0 || !tokenIn(sql, tokens[0], qualifiedStatements) {
+		return sql
+	}
+
+	q := &qualifier{sql: sql, tokens: tokens, ctes: cteNames(sql, tokens)}
+	var parens []bool // whether each of open parentheses contains a query
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case tok.kind == '(':
+			parens = append(parens, i+1 < len(tokens) && tokenIn(sql, tokens[i+1], qualifiedStatements))
+		case tok.kind == ')':
+			if len(parens) > 0 {
+				parens = parens[:len(parens)-1]
+			}
+		case len(parens) > 0 && !parens[len(parens)-1]:
+			// FROM of functions like EXTRACT(YEAR FROM date)
+		case tokenIs(sql, tok, "FROM"):
+			i = q.tableList(i + 1)
+		case tokenIs(sql, tok, "UPDATE") && (i == 0 || tokens[i-1].kind == ')'):
+			// not FOR UPDATE or ON DUPLICATE KEY UPDATE
+			i = q.tableList(i + 1)
+		case tokenIs(sql, tok, "JOIN"):
+			i = q.table(i+1, false)
+		case tokenIs(sql, tok, "INTO"):
+			i = q.table(i+1, true)
+		}
+	}
+
+	if len(q.inserts) == 0 {
+		return sql
+	}
+
+	prefix := quoteIdentifier(schema) + "."
+	var buf strings.Builder
+	buf.Grow(len(sql) + len(q.inserts)*len(prefix))
+	last := 0
+	for _, pos := range q.inserts {
+		buf.WriteString(sql[last:pos])
+		buf.WriteString(prefix)
+		last = pos
+	}
+	buf.WriteString(sql[last:])
+	return buf.String()
+}
+
+type qualifier struct {
+	sql     string
+	tokens  []sqlToken
+	ctes    []string
+	inserts []int // positions of the unqualified table names
+}
+
+// table qualifies the table name at the given index
+// and returns index of the last token of the name.
+// Name followed by parenthesis is a column list of INTO
+// or a table function like JSON_TABLE otherwise.
+func (q *qualifier) table(i int, into bool) int {
+	for i < len(q.tokens) && tokenIn(q.sql, q.tokens[i], tableModifiers) {
+		i++
+	}
+	if i >= len(q.tokens) || tokenIn(q.sql, q.tokens[i], notTables) {
+		return i
+	}
+
+	tok := q.tokens[i]
+	if tok.kind != 'w' && tok.kind != '`' {
+		return i - 1
+	}
+	if i+1 < len(q.tokens) && q.tokens[i+1].kind == '.' {
+		return i + 2 // already qualified
+	}
+	if !into && i+1 < len(q.tokens) && q.tokens[i+1].kind == '(' {
+		return i
+	}
+
+	name := strings.Trim(q.sql[tok.start:tok.end], "`")
+	for _, cte := range q.ctes {
+		if strings.EqualFold(cte, name) {
+			return i
+		}
+	}
+
+	q.inserts = append(q.inserts, tok.start)
+	return i
+}
+
+// tableList qualifies comma-separated list of tables
+// with optional aliases and returns index of the last token
+func (q *qualifier) tableList(i int) int {
+	for {
+		i = q.table(i, false)
+		if i+1 < len(q.tokens) && tokenIs(q.sql, q.tokens[i+1], "AS") {
+			i++
+		}
+		if next := i + 1; next < len(q.tokens) && q.tokens[next].kind != ',' &&
+			(q.tokens[next].kind == '`' || q.tokens[next].kind == 'w' && !tokenIn(q.sql, q.tokens[next], notAliases)) {
+			i = next // alias
+		}
+		if i+1 >= len(q.tokens) || q.tokens[i+1].kind != ',' {
+			return i
+		}
+		i += 2
+	}
+}
+
+// cteNames returns names of common table expressions
+// which are followed by "AS (" or "(columns) AS ("
+func cteNames(sql string, tokens []sqlToken) []string {
+	var names []string
+	for i, tok := range tokens {
+		if tok.kind != 'w' && tok.kind != '`' {
+			continue
+		}
+
+		next := i + 1
+		if next < len(tokens) && tokens[next].kind == '(' {
+			for next < len(tokens) && tokens[next].kind != ')' {
+				next++
+			}
+			next++
+		}
+		if next+1 < len(tokens) && tokenIs(sql, tokens[next], "AS") && tokens[next+1].kind == '(' {
+			names = append(names, strings.Trim(sql[tok.start:tok.end], "`"))
+		}
+	}
+	return names
+}
+
+// tokenizeSQL splits the statement into tokens
+func tokenizeSQL(sql string) []sqlToken {
+	var tokens []sqlToken
+	query := []byte(sql)
+	for i := 0; i < len(sql); {
+		ch := sql[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n':
+			i++
+		case ch == '\'' || ch == '"':
+			if i = skipQuoted(query, i); i < 0 {
+				return tokens
+			}
+		case ch == '`':
+			end := skipQuoted(query, i)
+			if end < 0 {
+				return tokens
+			}
+			tokens = append(tokens, sqlToken{start: i, end: end, kind: '`'})
+			i = end
+		case ch == '#' || strings.HasPrefix(sql[i:], "-- ") || strings.HasPrefix(sql[i:], "--\t"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				return tokens
+			}
+			i += end
+		case strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return tokens
+			}
+			i += end + 4
+		case isWordChar(ch):
+			end := i + 1
+			for end < len(sql) && isWordChar(sql[end]) {
+				end++
+			}
+			tokens = append(tokens, sqlToken{start: i, end: end, kind: 'w'})
+			i = end
+		default:
+			tokens = append(tokens, sqlToken{start: i, end: i + 1, kind: ch})
+			i++
+		}
+	}
+	return tokens
+}
+
+func isWordChar(ch byte) bool {
+	return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9' ||
+		ch == '_' || ch == '$' || ch >= 0x80
+}
+
+// tokenIs reports whether token is the given keyword
+func tokenIs(sql string, tok sqlToken, keyword string) bool {
+	return tok.kind == 'w' && strings.EqualFold(sql[tok.start:tok.end], keyword)
+}
+
+func tokenIn(sql string, tok sqlToken, keywords []string) bool {
+	for _, keyword := range keywords {
+		if tokenIs(sql, tok, keyword) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package mysqldriver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQualifyTables(t *testing.T) {
+	tests := []struct {
+		sql      string
+		expected string
+	}{
+		{"SELECT * FROM dogs", "SELECT * FROM `t1`.dogs"},
+		{"SELECT * FROM `dogs` d JOIN owners AS o ON d.owner = o.id", "SELECT * FROM `t1`.`dogs` d JOIN `t1`.owners AS o ON d.owner = o.id"},
+		{"SELECT * FROM dogs, cats c, birds WHERE 1", "SELECT * FROM `t1`.dogs, `t1`.cats c, `t1`.birds WHERE 1"},
+		{"SELECT * FROM test.dogs JOIN `test`.`cats`", "SELECT * FROM test.dogs JOIN `test`.`cats`"},
+		{"INSERT INTO dogs(name) SELECT name FROM cats", "INSERT INTO `t1`.dogs(name) SELECT name FROM `t1`.cats"},
+		{"insert ignore into dogs values (1)", "insert ignore into `t1`.dogs values (1)"},
+		{"UPDATE LOW_PRIORITY dogs SET age = 1", "UPDATE LOW_PRIORITY `t1`.dogs SET age = 1"},
+		{"DELETE FROM dogs WHERE id IN (SELECT id FROM cats)", "DELETE FROM `t1`.dogs WHERE id IN (SELECT id FROM `t1`.cats)"},
+		{"SELECT * FROM (SELECT * FROM dogs) AS d", "SELECT * FROM (SELECT * FROM `t1`.dogs) AS d"},
+		{"WITH d AS (SELECT * FROM dogs) SELECT * FROM d", "WITH d AS (SELECT * FROM `t1`.dogs) SELECT * FROM d"},
+		{"WITH RECURSIVE s(n) AS (SELECT 1 UNION ALL SELECT n+1 FROM s) SELECT n FROM s", "WITH RECURSIVE s(n) AS (SELECT 1 UNION ALL SELECT n+1 FROM s) SELECT n FROM s"},
+		{"SELECT EXTRACT(YEAR FROM born), TRIM(LEADING 'x' FROM name) FROM dogs", "SELECT EXTRACT(YEAR FROM born), TRIM(LEADING 'x' FROM name) FROM `t1`.dogs"},
+		{"SELECT 'FROM dogs', `from` /* FROM dogs */ FROM DUAL", "SELECT 'FROM dogs', `from` /* FROM dogs */ FROM DUAL"},
+		{"SELECT * FROM dogs FOR UPDATE NOWAIT", "SELECT * FROM `t1`.dogs FOR UPDATE NOWAIT"},
+		{"INSERT INTO dogs VALUES (1) ON DUPLICATE KEY UPDATE age = 2", "INSERT INTO `t1`.dogs VALUES (1) ON DUPLICATE KEY UPDATE age = 2"},
+		{"SELECT * INTO @count FROM dogs", "SELECT * INTO @count FROM `t1`.dogs"},
+		{"SELECT * FROM dogs INTO OUTFILE '/tmp/dogs' FIELDS TERMINATED BY ','", "SELECT * FROM `t1`.dogs INTO OUTFILE '/tmp/dogs' FIELDS TERMINATED BY ','"},
+		{"SELECT * FROM JSON_TABLE('[]', '$[*]' COLUMNS(id INT PATH '$')) AS j", "SELECT * FROM JSON_TABLE('[]', '$[*]' COLUMNS(id INT PATH '$')) AS j"},
+		{"SHOW TABLES FROM test", "SHOW TABLES FROM test"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, qualifyTables(test.sql, "t1"), test.expected, test.sql)
+	}
+}
+
+func TestConnDefaultSchema(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec("CREATE DATABASE IF NOT EXISTS tenant")
+		assert.NoError(t, err)
+		defer conn.Exec("DROP DATABASE tenant")
+		_, err = conn.Exec("CREATE TABLE tenant.people (id INT, firstname VARCHAR(10))")
+		assert.NoError(t, err)
+		_, err = conn.Exec(`INSERT INTO test.people(firstname) VALUES ("shared")`)
+		assert.NoError(t, err)
+
+		conn.DefaultSchema = "tenant"
+		defer func() { conn.DefaultSchema = "" }()
+		_, err = conn.Exec(`INSERT INTO people(id, firstname) VALUES (1, "tenant")`)
+		assert.NoError(t, err)
+
+		rows, err := conn.Query("SELECT firstname FROM people")
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.String(), "tenant")
+		assert.False(t, rows.Next())
+		assert.NoError(t, rows.LastError())
+	})
+}
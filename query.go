@@ -1,23 +1,41 @@
 package mysqldriver
 
 import (
+	"bytes"
+	"errors"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pubnative/mysqlproto-go"
 )
 
+// ErrContainsNul is returned by SafeString when value contains NUL byte
+var ErrContainsNul = errors.New("mysqldriver: value contains NUL byte")
+
 // Rows represents result set of SELECT query
 type Rows struct {
-	resultSet mysqlproto.ResultSet
-	packet    []byte
-	offset    uint64
-	eof       bool
+	conn        *Conn
+	definitions []ColumnInfo
+	packet      []byte
+	offset      uint64
+	eof         bool
+	moreResults bool   // server sends another result set after this one
+	warnings    uint16 // warning count of EOF_PACKET following the rows
 
 	errRead  error // error reading from the stream
 	errParse error // error parsing the value
 
 	columns     map[string]columnValue
 	readColumns int
+
+	binary *binaryDecoder // decoder of binary protocol rows of prepared statement
+	stmt   *Stmt          // prepared statement reading OUT parameters of CALL
+	cursor *cursor        // rows are fetched from the cursor of prepared statement
+
+	buffered bool     // rows are read from the buffer instead of the stream
+	buffer   [][]byte // rows of BufferedRows
+	position int      // index of the next row in the buffer
 }
 
 type columnValue struct {
@@ -41,18 +59,20 @@ type columnValue struct {
 //  for rows.Next() {
 //  	// read values from the row
 //  }
-// It's required to read all rows before performing another query
-// because connection contains sequential stream of rows.
+// It's required to read all rows or to close them by Close before
+// performing another query because connection contains sequential
+// stream of rows.
+// Until then, all queries and commands of the connection
+// return ErrConnectionBusy error.
 //  rows, _ := conn.Query("SELECT name FROM dogs LIMIT 1")
 //  rows.Next()   // move cursor to the first row
 //  rows.String() // dog's name
-//  rows, _ = conn.Query("SELECT name FROM cats LIMIT 2")
-//  rows.Next()   // move cursor to the second row of first query
-//  rows.String() // still dog's name
+//  _, err := conn.Query("SELECT name FROM cats LIMIT 2")
+//  err == ErrConnectionBusy // the first stream of rows isn't read yet
 //  rows.Next()   // returns false. closes the first stream of rows
+//  rows, _ = conn.Query("SELECT name FROM cats LIMIT 2")
 //  rows.Next()   // move cursor to the first row of second query
 //  rows.String() // cat's name
-//  rows.Next()   // returns false. closes the second stream of rows
 func (r *Rows) Next() bool {
 	if r.eof {
 		return false
@@ -63,45 +83,173 @@ func (r *Rows) Next() bool {
 		return false
 	}
 
-	packet, err := r.resultSet.Row()
+	if r.errParse != nil && r.conn.StopOnParseError {
+		r.discard()
+		return false
+	}
+
+	if r.buffered {
+		return r.nextBuffered()
+	}
+
+	packet, err := r.readRow()
 	if err != nil {
-		r.errRead = err
+		r.errRead = r.conn.readError(err)
+		r.conn.release()
 		return false
 	}
 
 	if packet == nil {
 		r.eof = true
+		// connection is busy until all result sets are read
+		if !r.moreResults {
+			r.conn.release()
+		}
 		return false
 	} else {
-		r.packet = packet
-		r.offset = 0
-		r.readColumns = 0
+		r.setRow(packet)
 		return true
 	}
 }
 
+// setRow makes packet the current row
+func (r *Rows) setRow(packet []byte) {
+	r.packet = packet
+	r.offset = 0
+	r.readColumns = 0
+	// values of the previous row aren't returned by Value
+	for name := range r.columns {
+		delete(r.columns, name)
+	}
+
+	if r.binary != nil {
+		r.binary.reset()
+		r.offset = binaryRowValuesOffset(len(r.definitions))
+		if len(packet) < int(r.offset) || packet[0] != 0x00 {
+			r.errParse = errMalformedPacket
+			r.readColumns = len(r.definitions)
+		}
+	}
+}
+
+// Columns returns definitions of the result set columns
+func (r *Rows) Columns() []ColumnInfo {
+	columns := make([]ColumnInfo, len(r.definitions))
+	copy(columns, r.definitions)
+	return columns
+}
+
+// ColumnNames returns names of the result set columns as they're
+// referenced by Row, i.e. aliases given by AS instead of
+// the original names of the table columns
+func (r *Rows) ColumnNames() []string {
+	names := make([]string, len(r.definitions))
+	for i, column := range r.definitions {
+		names[i] = column.Name
+	}
+	return names
+}
+
+// NextColumn returns definition of the column which is read
+// by the next call of the accessor like String or Int.
+// It returns false when all columns of the row are read.
+func (r *Rows) NextColumn() (ColumnInfo, bool) {
+	if r.readColumns >= len(r.definitions) {
+		return ColumnInfo{}, false
+	}
+	return r.definitions[r.readColumns], true
+}
+
+// Close discards the rest of the rows and result sets, so the connection
+// can perform the next query without reading all rows by Next.
+// It returns the error of reading the stream, connection becomes
+// invalid in this case unless the error is ERRPacket.
+// It's safe to call Close after all rows are read and more than once.
+//  rows, _ := conn.Query("SELECT name FROM dogs")
+//  defer rows.Close()
+//  for rows.Next() {
+//  	if rows.String() == "Max" {
+//  		break // the rest of the dogs are discarded by Close
+//  	}
+//  }
+func (r *Rows) Close() error {
+	if (!r.eof || r.moreResults) && r.errRead == nil {
+		r.discard()
+	}
+	return r.errRead
+}
+
+// discard reads the rest of rows and result sets without parsing them
+func (r *Rows) discard() {
+	if r.buffered {
+		r.eof = true
+		return
+	}
+
+	var err error
+	if !r.eof {
+		err = r.drainRows()
+	}
+	if err == nil {
+		err = r.skipResultSets()
+	}
+	if err != nil {
+		r.errRead = err
+	} else {
+		r.eof = true
+	}
+	r.conn.release()
+}
+
 // Bytes returns value as slice of bytes.
 // NULL value is represented as empty slice.
+// The slice references the packet of the row or, for rows of
+// the prepared statement, the buffer decoding numbers and dates
+// which is reused by the next row, so it's valid only until the next
+// call of Next. Use BytesCopy to keep the value longer.
 func (r *Rows) Bytes() []byte {
 	value, _ := r.NullBytes()
 	return value
 }
 
+// BytesCopy returns a copy of the value which stays valid
+// after Next moves the cursor to the next row
+// (see func (Rows) Bytes). NULL value is represented as nil.
+func (r *Rows) BytesCopy() []byte {
+	value, null := r.NullBytes()
+	if null {
+		return nil
+	}
+	return append([]byte{}, value...)
+}
+
 // NullBytes returns value as a slice of bytes
 // and NULL indicator. When value is NULL, second parameter is true.
 // All other type-specific functions are based on this one.
 // NullBytes shouldn't be invoked after all columns are read.
 // Calling it after reading all values of the row
-// will return nil value with NULL flag
+// will return nil value with NULL flag.
+// The value is valid only until the next call of Next.
 func (r *Rows) NullBytes() ([]byte, bool) {
-	if r.readColumns == len(r.resultSet.Columns) {
+	if r.readColumns == len(r.definitions) {
+		return nil, true
+	}
+
+	if r.binary != nil {
+		return r.nullBinaryBytes()
+	}
+
+	// row contains less values than columns of the result set
+	if r.offset >= uint64(len(r.packet)) {
+		r.errParse = errMalformedPacket
+		r.readColumns = len(r.definitions)
 		return nil, true
 	}
 
 	value, offset, null := mysqlproto.ReadRowValue(r.packet, r.offset)
 	r.offset = offset
 
-	name := r.resultSet.Columns[r.readColumns].Name
+	name := r.definitions[r.readColumns].Name
 	r.columns[name] = columnValue{
 		data: value,
 		null: null,
@@ -113,6 +261,10 @@ func (r *Rows) NullBytes() ([]byte, bool) {
 
 // String returns value as a string.
 // NULL value is represented as an empty string.
+// Bytes sent by the server aren't converted, so the string
+// is in the character set of the session (see func (Conn) Charset)
+// or of the column when character_set_results is NULL
+// (see charset.DecodedString to convert them to UTF-8).
 func (r *Rows) String() string {
 	value, _ := r.NullString()
 	return value
@@ -125,6 +277,36 @@ func (r *Rows) NullString() (string, bool) {
 	return string(data), null
 }
 
+// SafeString returns value as a string. It returns ErrContainsNul
+// when value contains NUL byte, so it's safe to pass it
+// to C code or protocols which treat NUL as the end of the string.
+// NULL value is represented as an empty string.
+func (r *Rows) SafeString() (string, error) {
+	data, _ := r.NullBytes()
+	if bytes.IndexByte(data, 0) >= 0 {
+		return "", ErrContainsNul
+	}
+	return string(data), nil
+}
+
+// ZerofilledString returns value as a string padded with leading zeros
+// up to the display width when column has ZEROFILL attribute,
+// e.g. "00042" for INT(5) ZEROFILL column. Values of other columns
+// are returned as they are, the same as String does.
+// NULL value is represented as an empty string.
+func (r *Rows) ZerofilledString() string {
+	if r.readColumns == len(r.definitions) {
+		return ""
+	}
+	column := r.definitions[r.readColumns]
+
+	value, null := r.NullBytes()
+	if null || !column.Zerofill() || uint32(len(value)) >= column.MaxLength {
+		return string(value)
+	}
+	return strings.Repeat("0", int(column.MaxLength)-len(value)) + string(value)
+}
+
 // Int returns value as an int.
 // NULL value is represented as 0.
 // Int method uses strconv.Atoi to convert string into int.
@@ -143,8 +325,9 @@ func (r *Rows) NullInt() (int, bool) {
 	if null {
 		return 0, true
 	}
+	str = r.conn.stripGrouping(str)
 
-	num, err := atoi(str)
+	num, err := atoi(trimZeroFraction(str))
 	if err != nil {
 		r.errParse = err
 	}
@@ -166,12 +349,13 @@ func (r *Rows) Int8() int8 {
 // NullInt8 method uses strconv.ParseInt to convert string into int8.
 // (see https://golang.org/pkg/strconv/#ParseInt)
 func (r *Rows) NullInt8() (int8, bool) {
-	str, null := r.NullString()
+	str, null := r.NullBytes()
 	if null {
 		return 0, true
 	}
+	str = r.conn.stripGrouping(str)
 
-	num, err := strconv.ParseInt(str, 10, 8)
+	num, err := parseInt(trimZeroFraction(str), 8)
 	if err != nil {
 		r.errParse = err
 	}
@@ -193,12 +377,13 @@ func (r *Rows) Int16() int16 {
 // NullInt16 method uses strconv.ParseInt to convert string into int16.
 // (see https://golang.org/pkg/strconv/#ParseInt)
 func (r *Rows) NullInt16() (int16, bool) {
-	str, null := r.NullString()
+	str, null := r.NullBytes()
 	if null {
 		return 0, true
 	}
+	str = r.conn.stripGrouping(str)
 
-	num, err := strconv.ParseInt(str, 10, 16)
+	num, err := parseInt(trimZeroFraction(str), 16)
 	if err != nil {
 		r.errParse = err
 	}
@@ -220,12 +405,13 @@ func (r *Rows) Int32() int32 {
 // NullInt32 method uses strconv.ParseInt to convert string into int32.
 // (see https://golang.org/pkg/strconv/#ParseInt)
 func (r *Rows) NullInt32() (int32, bool) {
-	str, null := r.NullString()
+	str, null := r.NullBytes()
 	if null {
 		return 0, true
 	}
+	str = r.conn.stripGrouping(str)
 
-	num, err := strconv.ParseInt(str, 10, 32)
+	num, err := parseInt(trimZeroFraction(str), 32)
 	if err != nil {
 		r.errParse = err
 	}
@@ -247,12 +433,13 @@ func (r *Rows) Int64() int64 {
 // NullInt64 method uses strconv.ParseInt to convert string into int64.
 // (see https://golang.org/pkg/strconv/#ParseInt)
 func (r *Rows) NullInt64() (int64, bool) {
-	str, null := r.NullString()
+	str, null := r.NullBytes()
 	if null {
 		return 0, true
 	}
+	str = r.conn.stripGrouping(str)
 
-	num, err := strconv.ParseInt(str, 10, 64)
+	num, err := parseInt(trimZeroFraction(str), 64)
 	if err != nil {
 		r.errParse = err
 	}
@@ -260,6 +447,146 @@ func (r *Rows) NullInt64() (int64, bool) {
 	return int64(num), false
 }
 
+// Uint returns value as a uint.
+// NULL value is represented as 0.
+// Uint method uses strconv.ParseUint to convert string into uint.
+// (see https://golang.org/pkg/strconv/#ParseUint)
+func (r *Rows) Uint() uint {
+	num, _ := r.NullUint()
+	return num
+}
+
+// NullUint returns value as a uint and NULL indicator.
+// When value is NULL, second parameter is true.
+// NullUint method uses strconv.ParseUint to convert string into uint.
+// (see https://golang.org/pkg/strconv/#ParseUint)
+func (r *Rows) NullUint() (uint, bool) {
+	str, null := r.NullBytes()
+	if null {
+		return 0, true
+	}
+	str = r.conn.stripGrouping(str)
+
+	num, err := parseUint(trimZeroFraction(str), strconv.IntSize)
+	if err != nil {
+		r.errParse = err
+	}
+
+	return uint(num), false
+}
+
+// Uint8 returns value as a uint8.
+// NULL value is represented as 0.
+// Uint8 method uses strconv.ParseUint to convert string into uint8.
+// (see https://golang.org/pkg/strconv/#ParseUint)
+func (r *Rows) Uint8() uint8 {
+	num, _ := r.NullUint8()
+	return num
+}
+
+// NullUint8 returns value as a uint8 and NULL indicator.
+// When value is NULL, second parameter is true.
+// NullUint8 method uses strconv.ParseUint to convert string into uint8.
+// (see https://golang.org/pkg/strconv/#ParseUint)
+func (r *Rows) NullUint8() (uint8, bool) {
+	str, null := r.NullBytes()
+	if null {
+		return 0, true
+	}
+	str = r.conn.stripGrouping(str)
+
+	num, err := parseUint(trimZeroFraction(str), 8)
+	if err != nil {
+		r.errParse = err
+	}
+
+	return uint8(num), false
+}
+
+// Uint16 returns value as a uint16.
+// NULL value is represented as 0.
+// Uint16 method uses strconv.ParseUint to convert string into uint16.
+// (see https://golang.org/pkg/strconv/#ParseUint)
+func (r *Rows) Uint16() uint16 {
+	num, _ := r.NullUint16()
+	return num
+}
+
+// NullUint16 returns value as a uint16 and NULL indicator.
+// When value is NULL, second parameter is true.
+// NullUint16 method uses strconv.ParseUint to convert string into uint16.
+// (see https://golang.org/pkg/strconv/#ParseUint)
+func (r *Rows) NullUint16() (uint16, bool) {
+	str, null := r.NullBytes()
+	if null {
+		return 0, true
+	}
+	str = r.conn.stripGrouping(str)
+
+	num, err := parseUint(trimZeroFraction(str), 16)
+	if err != nil {
+		r.errParse = err
+	}
+
+	return uint16(num), false
+}
+
+// Uint32 returns value as a uint32.
+// NULL value is represented as 0.
+// Uint32 method uses strconv.ParseUint to convert string into uint32.
+// (see https://golang.org/pkg/strconv/#ParseUint)
+func (r *Rows) Uint32() uint32 {
+	num, _ := r.NullUint32()
+	return num
+}
+
+// NullUint32 returns value as a uint32 and NULL indicator.
+// When value is NULL, second parameter is true.
+// NullUint32 method uses strconv.ParseUint to convert string into uint32.
+// (see https://golang.org/pkg/strconv/#ParseUint)
+func (r *Rows) NullUint32() (uint32, bool) {
+	str, null := r.NullBytes()
+	if null {
+		return 0, true
+	}
+	str = r.conn.stripGrouping(str)
+
+	num, err := parseUint(trimZeroFraction(str), 32)
+	if err != nil {
+		r.errParse = err
+	}
+
+	return uint32(num), false
+}
+
+// Uint64 returns value as a uint64.
+// NULL value is represented as 0.
+// Uint64 method uses strconv.ParseUint to convert string into uint64.
+// (see https://golang.org/pkg/strconv/#ParseUint)
+func (r *Rows) Uint64() uint64 {
+	num, _ := r.NullUint64()
+	return num
+}
+
+// NullUint64 returns value as a uint64 and NULL indicator.
+// When value is NULL, second parameter is true.
+// NullUint64 method uses strconv.ParseUint to convert string into uint64.
+// (see https://golang.org/pkg/strconv/#ParseUint)
+func (r *Rows) NullUint64() (uint64, bool) {
+	str, null := r.NullBytes()
+	if null {
+		return 0, true
+	}
+	str = r.conn.stripGrouping(str)
+
+	num, err := parseUint(trimZeroFraction(str), 64)
+	if err != nil {
+		r.errParse = err
+	}
+
+	return uint64(num), false
+}
+
 // Float32 returns value as a float32.
 // NULL value is represented as 0.0.
 // Float32 method uses strconv.ParseFloat to convert string into float32.
@@ -274,12 +601,11 @@ func (r *Rows) Float32() float32 {
 // NullFloat32 method uses strconv.ParseFloat to convert string into float32.
 // (see https://golang.org/pkg/strconv/#ParseFloat)
 func (r *Rows) NullFloat32() (float32, bool) {
-	str, null := r.NullString()
+	value, null := r.NullBytes()
 	if null {
 		return 0, true
 	}
-
-	num, err := strconv.ParseFloat(str, 32)
+	num, err := parseFloat(r.conn.stripGrouping(value), 32)
 	if err != nil {
 		r.errParse = err
 	}
@@ -291,6 +617,8 @@ func (r *Rows) NullFloat32() (float32, bool) {
 // NULL value is represented as 0.0.
 // Float64 method uses strconv.ParseFloat to convert string into float64.
 // (see https://golang.org/pkg/strconv/#ParseFloat)
+// Conversion of DECIMAL value is lossy, use Decimal or NullBigRat
+// to get the exact value.
 func (r *Rows) Float64() float64 {
 	num, _ := r.NullFloat64()
 	return num
@@ -300,13 +628,14 @@ func (r *Rows) Float64() float64 {
 // When value is NULL, second parameter is true.
 // NullFloat64 method uses strconv.ParseFloat to convert string into float64.
 // (see https://golang.org/pkg/strconv/#ParseFloat)
+// Conversion of DECIMAL value is lossy, use Decimal or NullBigRat
+// to get the exact value.
 func (r *Rows) NullFloat64() (float64, bool) {
-	str, null := r.NullString()
+	value, null := r.NullBytes()
 	if null {
 		return 0, true
 	}
-
-	num, err := strconv.ParseFloat(str, 64)
+	num, err := parseFloat(r.conn.stripGrouping(value), 64)
 	if err != nil {
 		r.errParse = err
 	}
@@ -340,6 +669,34 @@ func (r *Rows) NullBool() (bool, bool) {
 	return b, false
 }
 
+// Time returns value of DATE, DATETIME or TIMESTAMP column as a time.Time.
+// NULL value is represented as zero time.Time.
+// Fractional seconds with any precision from DATETIME(1)
+// up to DATETIME(6) are converted into nanoseconds.
+// Zero dates like "0000-00-00 00:00:00" are represented as zero time.Time.
+// Values are interpreted in the location of the connection
+// (see Conn.Location), UTC by default.
+func (r *Rows) Time() time.Time {
+	t, _ := r.NullTime()
+	return t
+}
+
+// NullTime returns value of DATE, DATETIME or TIMESTAMP column
+// as a time.Time and NULL indicator.
+// When value is NULL, second parameter is true.
+func (r *Rows) NullTime() (time.Time, bool) {
+	str, null := r.NullBytes()
+	if null {
+		return time.Time{}, true
+	}
+
+	t, err := parseDateTime(str, r.conn.location())
+	if err != nil {
+		r.errParse = err
+	}
+	return t, false
+}
+
 // LastError returns the error if any occurred during
 // reading result set of SELECT query. This method should
 // be always called after reading all rows.
@@ -364,23 +721,51 @@ func (r *Rows) LastError() error {
 // Query function is used only for SELECT query.
 // For all other queries and commands see func (c Conn) Exec
 func (c *Conn) Query(sql string) (*Rows, error) {
-	req := mysqlproto.ComQueryRequest([]byte(sql))
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	return c.query(sql)
+}
+
+// query performs the query on the acquired connection
+func (c *Conn) query(sql string) (*Rows, error) {
+	if c.DefaultSchema != "" {
+		sql = qualifyTables(sql, c.DefaultSchema)
+	}
+	return c.sendCommand(mysqlproto.ComQueryRequest([]byte(sql)), len(sql))
+}
+
+// sendCommand sends the command to the acquired connection
+// and reads the result set. Size is the length of the command
+// payload excluding the command byte.
+func (c *Conn) sendCommand(req []byte, size int) (*Rows, error) {
+	if err := c.checkPacketSize(size); err != nil {
+		c.release()
+		return nil, err
+	}
+
+	if err := c.startQueryTimeout(); err != nil {
+		c.valid = false
+		c.release()
+		return nil, err
+	}
+
 	if _, err := c.conn.Write(req); err != nil {
+		err = c.timeoutError(err)
 		c.valid = false
+		c.release()
 		return nil, err
 	}
 
-	resultSet, err := mysqlproto.ComQueryResponse(c.conn)
+	rows, err := c.readResultSet()
 	if err != nil {
-		if _, ok := err.(mysqlproto.ERRPacket); !ok {
-			c.valid = false
-		}
+		err = c.packetTooLargeError(c.readError(err), size)
+		c.release()
 		return nil, err
 	}
 
-	rows := &Rows{
-		resultSet: resultSet,
-		columns:   make(map[string]columnValue, len(resultSet.Columns)),
+	if rows.eof && !rows.moreResults {
+		c.release()
 	}
 	return rows, nil
 }
@@ -397,27 +782,67 @@ func (c *Conn) Query(sql string) (*Rows, error) {
 //  	return err // generic error
 //  }
 func (c *Conn) Exec(sql string) (mysqlproto.OKPacket, error) {
+	if err := c.acquire(); err != nil {
+		return mysqlproto.OKPacket{}, err
+	}
+	return c.exec(sql)
+}
+
+// exec executes the statement on the acquired connection
+func (c *Conn) exec(sql string) (mysqlproto.OKPacket, error) {
+	defer c.release()
+
+	if c.DefaultSchema != "" {
+		sql = qualifyTables(sql, c.DefaultSchema)
+	}
+
+	packet, err := c.writeQuery(sql)
+	if err != nil {
+		return mysqlproto.OKPacket{}, err
+	}
+
+	if packet.Payload[0] == localInfilePacket {
+		return c.execLocalInfile(string(packet.Payload[1:]), packet.SequenceID+1)
+	}
+	return c.execResult(packet.Payload, len(sql))
+}
+
+// writeQuery sends COM_QUERY with the statement limited
+// by max_allowed_packet and QueryTimeout of the connection
+// and reads the first packet of the response
+func (c *Conn) writeQuery(sql string) (mysqlproto.Packet, error) {
+	if err := c.checkPacketSize(len(sql)); err != nil {
+		return mysqlproto.Packet{}, err
+	}
+
+	if err := c.startQueryTimeout(); err != nil {
+		c.valid = false
+		return mysqlproto.Packet{}, err
+	}
+
 	req := mysqlproto.ComQueryRequest([]byte(sql))
 	if _, err := c.conn.Write(req); err != nil {
 		c.valid = false
-		return mysqlproto.OKPacket{}, err
+		return mysqlproto.Packet{}, c.timeoutError(err)
 	}
 
 	packet, err := c.conn.NextPacket()
 	if err != nil {
 		c.valid = false
-		return mysqlproto.OKPacket{}, err
+		return mysqlproto.Packet{}, c.timeoutError(err)
 	}
+	return packet, nil
+}
 
-	if packet.Payload[0] == mysqlproto.OK_PACKET {
-		pkt, err := mysqlproto.ParseOKPacket(packet.Payload, c.conn.CapabilityFlags)
-		return pkt, err
-	} else {
-		pkt, err := mysqlproto.ParseERRPacket(packet.Payload, c.conn.CapabilityFlags)
-		if err == nil {
-			return mysqlproto.OKPacket{}, pkt
-		} else {
-			return mysqlproto.OKPacket{}, err
-		}
+// execResult parses OK_PACKET or ERR_PACKET returned for
+// the statement of the given size and updates the session
+// state of the connection by OK_PACKET
+func (c *Conn) execResult(payload []byte, size int) (mysqlproto.OKPacket, error) {
+	pkt, err := parseExecResult(payload, c.conn.CapabilityFlags)
+	if err != nil {
+		return pkt, c.packetTooLargeError(err, size)
 	}
+	c.handleOKPacket(pkt)
+	c.trackSessionState(payload)
+	return pkt, nil
 }
@@ -301,6 +301,221 @@ func TestQueryRowReader(t *testing.T) {
 	})
 }
 
+func TestQuerySelectDateTimeWithFractionalSeconds(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		rows, err := conn.Query(`SELECT
+			CAST("2017-03-04 05:06:07.123" AS DATETIME(3)),
+			CAST("2017-03-04 05:06:07.123456" AS DATETIME(6)),
+			CAST("2017-03-04 05:06:07" AS DATETIME),
+			CAST("2017-03-04" AS DATE),
+			NULL
+		`)
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.Time(), time.Date(2017, 3, 4, 5, 6, 7, 123000000, time.UTC))
+		assert.Equal(t, rows.Time(), time.Date(2017, 3, 4, 5, 6, 7, 123456000, time.UTC))
+		assert.Equal(t, rows.Time(), time.Date(2017, 3, 4, 5, 6, 7, 0, time.UTC))
+		assert.Equal(t, rows.Time(), time.Date(2017, 3, 4, 0, 0, 0, 0, time.UTC))
+		tm, null := rows.NullTime()
+		assert.True(t, null)
+		assert.True(t, tm.IsZero())
+		assert.NoError(t, rows.LastError())
+		assert.False(t, rows.Next())
+	})
+}
+
+func TestQueryReturnsErrorWhenConnectionIsBusy(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec(`INSERT INTO people(firstname) VALUES ("bob"),("ben")`)
+		assert.NoError(t, err)
+
+		rows, err := conn.Query("SELECT firstname FROM people ORDER BY id")
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.String(), "bob")
+
+		_, err = conn.Query("SELECT id FROM people")
+		assert.Equal(t, err, ErrConnectionBusy)
+		_, err = conn.Exec(`INSERT INTO people(firstname) VALUES ("bin")`)
+		assert.Equal(t, err, ErrConnectionBusy)
+		assert.True(t, conn.valid)
+
+		// the first result set isn't affected
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.String(), "ben")
+		assert.False(t, rows.Next())
+		assert.NoError(t, rows.LastError())
+
+		rows, err = conn.Query("SELECT COUNT(*) FROM people")
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.Int(), 2)
+		assert.False(t, rows.Next())
+	})
+}
+
+func TestQueryReleasesConnectionOnError(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Query("SELECT * FROM unknown_table")
+		assert.NotNil(t, err)
+		_, err = conn.Exec("SELECT * FROM unknown_table")
+		assert.NotNil(t, err)
+		_, err = conn.Exec(`INSERT INTO people(firstname) VALUES ("bob")`)
+		assert.NoError(t, err)
+	})
+}
+
+func TestQuerySelectSafeString(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec(`INSERT INTO people(firstname, lastname) VALUES ("Bob", CONCAT("Smi", CHAR(0), "th"))`)
+		assert.NoError(t, err)
+		_, err = conn.Exec(`INSERT INTO people(firstname, lastname) VALUES (NULL, NULL)`)
+		assert.NoError(t, err)
+
+		rows, err := conn.Query("SELECT firstname, lastname FROM people ORDER BY id")
+		assert.NoError(t, err)
+
+		assert.True(t, rows.Next())
+		str, err := rows.SafeString()
+		assert.NoError(t, err)
+		assert.Equal(t, str, "Bob")
+		str, err = rows.SafeString()
+		assert.Equal(t, err, ErrContainsNul)
+		assert.Equal(t, str, "")
+
+		assert.True(t, rows.Next())
+		row := rows.Row()
+		str, err = row.SafeString("firstname")
+		assert.NoError(t, err)
+		assert.Equal(t, str, "")
+		assert.False(t, rows.Next())
+	})
+}
+
+func TestQueryZerofilledString(t *testing.T) {
+	zip := columnDefinition("zip")
+	zip[len(zip)-10] = 0x05 // max length
+	zip[len(zip)-5] = byte(flagZerofill | flagUnsigned)
+	conn := newPacketConn(
+		[]byte{0x03},
+		zip,
+		columnDefinition("name"),
+		zip,
+		eofPacket,
+		[]byte{0x02, '4', '2', 0x02, '4', '2', 0xfb},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT zip, name, zip FROM people")
+	assert.NoError(t, err)
+	assert.True(t, rows.Columns()[0].Zerofill())
+	assert.False(t, rows.Columns()[1].Zerofill())
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.ZerofilledString(), "00042")
+	assert.Equal(t, rows.ZerofilledString(), "42")
+	assert.Equal(t, rows.ZerofilledString(), "")
+	assert.Equal(t, rows.ZerofilledString(), "")
+	assert.False(t, rows.Next())
+}
+
+func TestQueryStripGrouping(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		rows, err := conn.Query("SELECT FORMAT(1234.5, 2), FORMAT(1234567, 0)")
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.Float64(), float64(0))
+		assert.Equal(t, rows.Int(), 0)
+		assert.False(t, rows.Next())
+		assert.NotNil(t, rows.LastError())
+
+		conn.StripGrouping = true
+		rows, err = conn.Query("SELECT FORMAT(1234.5, 2) AS price, FORMAT(1234567, 0)")
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.Float64(), 1234.5)
+		assert.Equal(t, rows.Int(), 1234567)
+		assert.False(t, rows.Next())
+		assert.NoError(t, rows.LastError())
+
+		rows, err = conn.Query("SELECT FORMAT(1234.5, 2) AS price")
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		row := rows.Row()
+		assert.Equal(t, row.Float32("price"), float32(1234.5))
+		assert.Equal(t, row.String("price"), "1,234.50")
+		assert.False(t, rows.Next())
+		assert.NoError(t, rows.LastError())
+	})
+}
+
+func TestQueryStopOnParseError(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec(`INSERT INTO people(firstname) VALUES ("1"), ("Bob"), ("3")`)
+		assert.NoError(t, err)
+
+		conn.StopOnParseError = true
+		rows, err := conn.Query("SELECT firstname FROM people ORDER BY id")
+		assert.NoError(t, err)
+
+		var nums []int
+		for rows.Next() {
+			nums = append(nums, rows.Int())
+		}
+		assert.Equal(t, nums, []int{1, 0})
+		assert.NotNil(t, rows.LastError())
+		assert.False(t, rows.Next())
+
+		// the rest of the rows are discarded
+		rows, err = conn.Query("SELECT COUNT(*) FROM people")
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.Int(), 3)
+		assert.False(t, rows.Next())
+	})
+}
+
+func TestQueryContinuesOnParseErrorByDefault(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec(`INSERT INTO people(firstname) VALUES ("1"), ("Bob"), ("3")`)
+		assert.NoError(t, err)
+
+		rows, err := conn.Query("SELECT firstname FROM people ORDER BY id")
+		assert.NoError(t, err)
+
+		var nums []int
+		for rows.Next() {
+			nums = append(nums, rows.Int())
+		}
+		assert.Equal(t, nums, []int{1, 0, 3})
+		assert.NotNil(t, rows.LastError())
+	})
+}
+
+func TestQuerySelectNumericAggregates(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec(`INSERT INTO people(cats,grade) VALUES (16,4.5),(32,1.5),(1,3)`)
+		assert.NoError(t, err)
+
+		rows, err := conn.Query("SELECT SUM(cats), SUM(grade), SUM(cats), AVG(cats), AVG(grade) FROM people")
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.Int64(), int64(49))
+		assert.Equal(t, rows.Int(), 9)
+		assert.Equal(t, rows.Int32(), int32(49))
+		assert.Equal(t, rows.Float64(), float64(16.3333))
+		assert.Equal(t, rows.Float64(), float64(3))
+		assert.NoError(t, rows.LastError())
+		assert.False(t, rows.Next())
+
+		rows, err = conn.Query("SELECT AVG(cats) FROM people")
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.Int64(), int64(0))
+		assert.EqualError(t, rows.LastError(), `strconv.ParseInt: parsing "16.3333": invalid syntax`)
+		assert.False(t, rows.Next())
+	})
+}
+
 func TestQueryMarkConnInvalidWhenStreamIsBroken(t *testing.T) {
 	db := NewDB("root@tcp(127.0.0.1:3306)/test", 10, time.Duration(0))
 	conn, err := db.GetConn()
@@ -473,6 +688,85 @@ func setup(t *testing.T, fn func(conn *Conn)) {
 	}()
 }
 
+func TestQueryWindowFunctionColumnNames(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec(`INSERT INTO people(firstname, age) VALUES ("bob", 30), ("ben", 20), ("max", 30)`)
+		assert.NoError(t, err)
+
+		rows, err := conn.Query(`
+			SELECT firstname AS name, RANK() OVER (ORDER BY age DESC) AS position,
+			SUM(age) OVER () AS total
+			FROM people ORDER BY id
+		`)
+		assert.NoError(t, err)
+		assert.Equal(t, rows.ColumnNames(), []string{"name", "position", "total"})
+		assert.Equal(t, rows.Columns()[0].OrgName, "firstname")
+
+		assert.True(t, rows.Next())
+		row := rows.Row()
+		assert.Equal(t, row.String("name"), "bob")
+		assert.Equal(t, row.Int("position"), 1)
+		assert.Equal(t, row.Int("total"), 80)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.Row().Int("position"), 3)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.Row().Int("position"), 1)
+		assert.False(t, rows.Next())
+		assert.NoError(t, rows.LastError())
+	})
+}
+
+func TestQueryRecursiveCTEColumnNames(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		rows, err := conn.Query(`
+			WITH RECURSIVE seq(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM seq WHERE n < 3)
+			SELECT n AS num, n * n AS square FROM seq
+		`)
+		assert.NoError(t, err)
+		assert.Equal(t, rows.ColumnNames(), []string{"num", "square"})
+
+		var squares []int
+		for rows.Next() {
+			row := rows.Row()
+			squares = append(squares, row.Int("num")*100+row.Int("square"))
+		}
+		assert.NoError(t, rows.LastError())
+		assert.Equal(t, squares, []int{101, 204, 309})
+	})
+}
+
+func TestRowsUint(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x03},
+		columnDefinition("id"),
+		columnDefinition("age"),
+		columnDefinition("badge"),
+		eofPacket,
+		[]byte{0x14, '1', '8', '4', '4', '6', '7', '4', '4', '0', '7', '3', '7', '0', '9', '5', '5', '1', '6', '1', '5', 0x03, '2', '5', '5', 0xfb},
+		[]byte{0x01, '1', 0x03, '2', '5', '6', 0x02, '-', '1'},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT id, age, badge FROM dogs")
+	assert.NoError(t, err)
+
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Uint64(), uint64(18446744073709551615))
+	assert.Equal(t, rows.Uint8(), uint8(255))
+	num, null := rows.NullUint32()
+	assert.Equal(t, num, uint32(0))
+	assert.True(t, null)
+	assert.NoError(t, rows.LastError())
+
+	assert.True(t, rows.Next())
+	row := rows.Row()
+	assert.Equal(t, row.Uint("id"), uint(1))
+	assert.Equal(t, row.Uint16("age"), uint16(256))
+	row.Uint16("badge")
+	assert.False(t, rows.Next())
+	assert.Equal(t, rows.LastError().(*strconv.NumError).Err, strconv.ErrSyntax)
+}
+
 func ExampleConn_Query_default() {
 	db := NewDB("root@tcp(127.0.0.1:3306)/test", 10, time.Duration(0))
 	conn, err := db.GetConn()
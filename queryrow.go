@@ -0,0 +1,62 @@
+package mysqldriver
+
+import "errors"
+
+// ErrNoRows is returned by SingleRow.Scan when the query returns no rows
+var ErrNoRows = errors.New("mysqldriver: no rows in result set")
+
+// SingleRow is the result of QueryRow which reads only the first row.
+// The query is performed right away, the error is returned by Scan.
+type SingleRow struct {
+	rows *Rows
+	err  error
+}
+
+// QueryRow performs the query which is expected to return at most one row,
+// e.g. lookup by the primary key. Arguments replace ? placeholders the same
+// way as QueryArgs does. SingleRow must be read by Scan, otherwise
+// the connection stays busy with the result set.
+//
+//	var name string
+//	err := conn.QueryRow("SELECT name FROM dogs WHERE id = ?", 1).Scan(&name)
+//	if err == mysqldriver.ErrNoRows {
+//		// there is no dog with the given id
+//	}
+func (c *Conn) QueryRow(sql string, args ...interface{}) *SingleRow {
+	var rows *Rows
+	var err error
+	if len(args) == 0 {
+		rows, err = c.Query(sql)
+	} else {
+		rows, err = c.QueryArgs(sql, args...)
+	}
+	return &SingleRow{rows: rows, err: err}
+}
+
+// Scan reads the first row into dest the same way as Rows.Scan does
+// and discards the rest of the result set. It returns ErrNoRows when
+// the query returns no rows, otherwise the error of the query, reading
+// or parsing the values (see func (Rows) LastError).
+// Scan can be called only once.
+func (r *SingleRow) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.err = ErrNoRows
+
+	if !r.rows.Next() {
+		if err := r.rows.LastError(); err != nil {
+			return err
+		}
+		return ErrNoRows
+	}
+
+	err := r.rows.Scan(dest...)
+	if closeErr := r.rows.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = r.rows.LastError()
+	}
+	return err
+}
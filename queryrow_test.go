@@ -0,0 +1,56 @@
+package mysqldriver
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnQueryRowScansFirstRow(t *testing.T) {
+	conn, s := newSessionConn("8.0.22",
+		[]byte{0x02}, columnDefinition("name"), columnDefinition("age"), eofPacket,
+		[]byte{0x03, 'M', 'a', 'x', 0x01, '3'},
+		[]byte{0x03, 'B', 'o', 'b', 0x01, '5'},
+		eofPacket,
+	)
+
+	var name string
+	var age int
+	err := conn.QueryRow("SELECT name, age FROM dogs WHERE owner = ?", "Bob").Scan(&name, &age)
+	assert.NoError(t, err)
+	assert.Equal(t, name, "Max")
+	assert.Equal(t, age, 3)
+	assert.True(t, bytes.Contains(s.written, []byte("owner = 'Bob'")))
+	// the rest of rows is discarded
+	assert.Equal(t, conn.acquire(), nil)
+}
+
+func TestConnQueryRowReturnsErrNoRows(t *testing.T) {
+	conn := newPacketConn([]byte{0x01}, columnDefinition("name"), eofPacket, eofPacket)
+
+	row := conn.QueryRow("SELECT name FROM dogs WHERE id = 0")
+	var name string
+	assert.Equal(t, row.Scan(&name), ErrNoRows)
+	assert.Equal(t, row.Scan(&name), ErrNoRows)
+	assert.Equal(t, conn.acquire(), nil)
+}
+
+func TestConnQueryRowReturnsErrors(t *testing.T) {
+	conn := newPacketConn([]byte{mysqlproto.ERR_PACKET, 0x7a, 0x04, '#', '4', '2', 'S', '0', '2'})
+	var name string
+	_, ok := conn.QueryRow("SELECT name FROM unknown").Scan(&name).(mysqlproto.ERRPacket)
+	assert.True(t, ok)
+
+	conn = newPacketConn([]byte{0x01}, columnDefinition("age"), eofPacket, []byte{0x03, 'o', 'l', 'd'}, eofPacket)
+	var age int
+	err := conn.QueryRow("SELECT age FROM dogs").Scan(&age)
+	assert.EqualError(t, err, `strconv.Atoi: parsing "old": invalid syntax`)
+	assert.Equal(t, conn.acquire(), nil)
+
+	conn = newPacketConn([]byte{0x01}, columnDefinition("age"), eofPacket, []byte{0x01, '3'}, eofPacket)
+	err = conn.QueryRow("SELECT age FROM dogs").Scan(&age, &age)
+	assert.Equal(t, err, &ScanCountError{Columns: 1, Dest: 2})
+	assert.Equal(t, conn.acquire(), nil)
+}
@@ -0,0 +1,82 @@
+package mysqldriver
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNoQueryStats is returned by LastQueryStats when performance_schema
+// doesn't contain statements of the connection, e.g. when
+// performance_schema or events_statements_history consumer is disabled
+var ErrNoQueryStats = errors.New("mysqldriver: statement statistics aren't available")
+
+// QueryStats contains execution statistics of the statement
+// collected by performance_schema
+type QueryStats struct {
+	SQL             string        // text of the statement
+	Duration        time.Duration // total execution time
+	LockTime        time.Duration // time spent waiting for table locks
+	RowsExamined    int64         // number of rows read from storage engines
+	RowsSent        int64         // number of rows returned to the client
+	RowsAffected    int64         // number of rows changed by the statement
+	TmpTables       int64         // number of internal temporary tables
+	TmpDiskTables   int64         // number of internal on-disk temporary tables
+	SortRows        int64         // number of sorted rows
+	SortMergePasses int64         // number of merge passes of the sort algorithm
+	NoIndexUsed     bool          // table scan is performed without index
+}
+
+const lastQueryStatsSQL = `SELECT h.SQL_TEXT, h.TIMER_WAIT, h.LOCK_TIME,
+	h.ROWS_EXAMINED, h.ROWS_SENT, h.ROWS_AFFECTED,
+	h.CREATED_TMP_TABLES, h.CREATED_TMP_DISK_TABLES,
+	h.SORT_ROWS, h.SORT_MERGE_PASSES, h.NO_INDEX_USED
+FROM performance_schema.events_statements_history h
+JOIN performance_schema.threads t ON t.THREAD_ID = h.THREAD_ID
+WHERE t.PROCESSLIST_ID = CONNECTION_ID()
+ORDER BY h.EVENT_ID DESC
+LIMIT 1`
+
+// LastQueryStats returns statistics of the last statement
+// performed by the connection. Statement which is being executed is
+// in events_statements_current table until it's completed,
+// so the previous statement is read from events_statements_history.
+// It requires performance_schema with enabled
+// events_statements_history consumer and SELECT privilege on it.
+//
+//	rows, _ := conn.Query("SELECT * FROM dogs ORDER BY age")
+//	for rows.Next() {
+//	}
+//	stats, _ := conn.LastQueryStats()
+//	fmt.Println(stats.Duration, stats.RowsExamined, stats.SortRows)
+func (c *Conn) LastQueryStats() (*QueryStats, error) {
+	rows, err := c.Query(lastQueryStatsSQL)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats *QueryStats
+	for rows.Next() {
+		// timers are measured in picoseconds
+		stats = &QueryStats{
+			SQL:             rows.String(),
+			Duration:        time.Duration(rows.Int64() / 1000),
+			LockTime:        time.Duration(rows.Int64() / 1000),
+			RowsExamined:    rows.Int64(),
+			RowsSent:        rows.Int64(),
+			RowsAffected:    rows.Int64(),
+			TmpTables:       rows.Int64(),
+			TmpDiskTables:   rows.Int64(),
+			SortRows:        rows.Int64(),
+			SortMergePasses: rows.Int64(),
+			NoIndexUsed:     rows.Int64() > 0,
+		}
+	}
+	if err := rows.LastError(); err != nil {
+		return nil, err
+	}
+
+	if stats == nil {
+		return nil, ErrNoQueryStats
+	}
+	return stats, nil
+}
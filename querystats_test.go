@@ -0,0 +1,33 @@
+package mysqldriver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnLastQueryStats(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec(`INSERT INTO people(firstname) VALUES ("bob"), ("alice")`)
+		assert.NoError(t, err)
+
+		stats, err := conn.LastQueryStats()
+		assert.NoError(t, err)
+		assert.Equal(t, stats.SQL, `INSERT INTO people(firstname) VALUES ("bob"), ("alice")`)
+		assert.Equal(t, stats.RowsAffected, int64(2))
+
+		rows, err := conn.Query("SELECT firstname FROM people ORDER BY firstname")
+		assert.NoError(t, err)
+		for rows.Next() {
+		}
+		assert.NoError(t, rows.LastError())
+
+		stats, err = conn.LastQueryStats()
+		assert.NoError(t, err)
+		assert.Equal(t, stats.SQL, "SELECT firstname FROM people ORDER BY firstname")
+		assert.Equal(t, stats.RowsSent, int64(2))
+		assert.Equal(t, stats.SortRows, int64(2))
+		assert.True(t, stats.NoIndexUsed)
+		assert.True(t, stats.Duration > 0)
+	})
+}
@@ -0,0 +1,145 @@
+package mysqldriver
+
+import (
+	"time"
+
+	"github.com/pubnative/mysqlproto-go"
+)
+
+// ReconnectingConn represents connection obtained from the pool
+// which transparently re-establishes itself when a query
+// fails because the connection is broken, e.g. the server
+// has been restarted or has closed the idle connection.
+//
+// Only Query is retried by default as reading is idempotent.
+// Exec is retried only when ReconnectPolicy.RetryExec of the DB
+// is set. All other commands are passed to the underlying
+// connection as they are and are never retried.
+// Rows which fail in the middle of the result set aren't retried
+// either, the error is returned by Rows.LastError.
+// Connection whose user is changed by ChangeUser isn't
+// re-established as a new one would use the pool's credentials.
+// Connection with a transaction in progress isn't re-established
+// either, as the statements of the transaction are lost with it
+// and retried ones would run outside of the transaction.
+type ReconnectingConn struct {
+	*Conn
+	db *DB
+}
+
+// ReconnectPolicy configures how ReconnectingConn
+// re-establishes the broken connection (see DB.Reconnect).
+// Zero value reconnects once without delay.
+type ReconnectPolicy struct {
+	// MaxAttempts is the number of times connection is re-established
+	// and the query is retried before the error is returned.
+	// Zero value means one attempt.
+	MaxAttempts int
+
+	// Backoff is the delay before the second attempt which is
+	// doubled for every next one. The first attempt isn't delayed.
+	Backoff time.Duration
+
+	// MaxBackoff limits the delay between attempts.
+	// Zero value means no limit.
+	MaxBackoff time.Duration
+
+	// RetryExec makes Exec retried as well as Query. Be careful,
+	// connection can break after the server has executed the statement,
+	// but before the result is received, so the retry applies
+	// non-idempotent statement like INSERT twice.
+	RetryExec bool
+}
+
+// attempts returns the number of attempts of the policy
+func (p ReconnectPolicy) attempts() int {
+	if p.MaxAttempts <= 0 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// backoff returns the delay before the attempt counted from 1
+func (p ReconnectPolicy) backoff(attempt int) time.Duration {
+	if attempt <= 1 || p.Backoff <= 0 {
+		return 0
+	}
+	delay := p.Backoff
+	for i := 2; i < attempt && (p.MaxBackoff <= 0 || delay < p.MaxBackoff); i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay
+}
+
+// GetReconnectingConn gets connection from the pool the same way
+// as GetConn does and wraps it into ReconnectingConn.
+// The connection should be returned back by calling db.PutConn(conn.Conn)
+func (db *DB) GetReconnectingConn() (*ReconnectingConn, error) {
+	conn, err := db.GetConn()
+	if err != nil {
+		return nil, err
+	}
+	return &ReconnectingConn{Conn: conn, db: db}, nil
+}
+
+// Query performs SELECT query. When query fails because of the
+// connection error (see IsConnectionError, not a syntax error
+// returned by the server), connection is closed and a new one is established.
+// After that query is performed once again. When it fails
+// MaxAttempts of ReconnectPolicy times, the last error
+// is returned to the caller.
+func (c *ReconnectingConn) Query(sql string) (*Rows, error) {
+	rows, err := c.Conn.Query(sql)
+	err = c.reconnect(err, func() error {
+		rows, err = c.Conn.Query(sql)
+		return err
+	})
+	return rows, err
+}
+
+// Exec executes the statement. It's retried the same way
+// as Query only when RetryExec of ReconnectPolicy is set.
+func (c *ReconnectingConn) Exec(sql string) (mysqlproto.OKPacket, error) {
+	pkt, err := c.Conn.Exec(sql)
+	if !c.db.Reconnect.RetryExec {
+		return pkt, err
+	}
+	err = c.reconnect(err, func() error {
+		pkt, err = c.Conn.Exec(sql)
+		return err
+	})
+	return pkt, err
+}
+
+// reconnect re-establishes the connection and retries fn
+// while it fails with the connection error (see IsConnectionError)
+// or because the connection has been interrupted by Rows.Interrupt.
+// The new connection isn't interrupted.
+// Statements exceeding QueryTimeout aren't retried
+// as they'd likely exceed it again.
+func (c *ReconnectingConn) reconnect(err error, fn func() error) error {
+	policy := c.db.Reconnect
+	for attempt := 1; attempt <= policy.attempts(); attempt++ {
+		broken := IsConnectionError(err) || err == ErrInterrupted
+		if !broken || c.Conn.userChanged || c.Conn.tx != nil {
+			return err
+		}
+
+		c.Conn.Close()
+		time.Sleep(policy.backoff(attempt))
+		conn, dialErr := c.db.dial()
+		if dialErr != nil {
+			if conn != nil {
+				conn.Close()
+			}
+			err = dialErr
+			continue
+		}
+		c.Conn = conn
+		err = fn()
+	}
+	return err
+}
@@ -0,0 +1,158 @@
+package mysqldriver
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconnectingConnQueryRetriesWhenConnectionIsBroken(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:3306)/test", 1, time.Duration(0))
+	conn, err := db.GetReconnectingConn()
+	assert.NoError(t, err)
+
+	broken := conn.Conn
+	assert.Nil(t, broken.conn.Close())
+
+	rows, err := conn.Query("SELECT 1")
+	assert.NoError(t, err)
+	assert.False(t, broken.valid)
+	assert.True(t, conn.valid)
+	assert.True(t, broken != conn.Conn)
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Int(), 1)
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.LastError())
+	assert.Nil(t, db.PutConn(conn.Conn))
+}
+
+func TestReconnectingConnCallsOnDialForNewConnection(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:3306)/test", 1, time.Duration(0))
+	var calls int
+	db.OnDial = func(conn *Conn) error {
+		calls++
+		return nil
+	}
+	conn, err := db.GetReconnectingConn()
+	assert.NoError(t, err)
+	assert.Equal(t, calls, 1)
+
+	assert.Nil(t, conn.Conn.conn.Close())
+	_, err = conn.Query("SELECT 1")
+	assert.NoError(t, err)
+	assert.Equal(t, calls, 2)
+}
+
+func TestReconnectingConnQueryDoesNotRetryQueryError(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:3306)/test", 1, time.Duration(0))
+	conn, err := db.GetReconnectingConn()
+	assert.NoError(t, err)
+
+	original := conn.Conn
+	_, err = conn.Query("SELECT * FROM unknown_table")
+	_, ok := err.(mysqlproto.ERRPacket)
+	assert.True(t, ok)
+	assert.True(t, original == conn.Conn)
+	assert.True(t, conn.valid)
+}
+
+func TestReconnectingConnExecIsNotRetried(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:3306)/test", 1, time.Duration(0))
+	conn, err := db.GetReconnectingConn()
+	assert.NoError(t, err)
+
+	original := conn.Conn
+	assert.Nil(t, original.conn.Close())
+
+	_, err = conn.Exec("SELECT 1")
+	assert.NotNil(t, err)
+	assert.False(t, conn.valid)
+	assert.True(t, original == conn.Conn)
+}
+
+func TestReconnectPolicyBackoff(t *testing.T) {
+	policy := ReconnectPolicy{Backoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+	assert.Equal(t, policy.backoff(1), time.Duration(0))
+	assert.Equal(t, policy.backoff(2), 10*time.Millisecond)
+	assert.Equal(t, policy.backoff(3), 20*time.Millisecond)
+	assert.Equal(t, policy.backoff(4), 40*time.Millisecond)
+	assert.Equal(t, policy.backoff(5), 50*time.Millisecond)
+	assert.Equal(t, policy.backoff(100), 50*time.Millisecond)
+
+	assert.Equal(t, ReconnectPolicy{}.attempts(), 1)
+	assert.Equal(t, ReconnectPolicy{}.backoff(2), time.Duration(0))
+}
+
+func TestReconnectingConnQueryRetriesMaxAttempts(t *testing.T) {
+	// nothing listens the port, so reconnection fails
+	db := NewDB("root@tcp(127.0.0.1:1)/test", 1, time.Duration(0))
+	db.Reconnect = ReconnectPolicy{MaxAttempts: 3, Backoff: 5 * time.Millisecond}
+	broken, _ := newSessionConn("8.0.22")
+	conn := &ReconnectingConn{Conn: broken, db: db}
+
+	start := time.Now()
+	_, err := conn.Query("SELECT 1")
+	_, ok := err.(*net.OpError)
+	assert.True(t, ok)
+	assert.True(t, time.Since(start) >= 15*time.Millisecond)
+	assert.False(t, conn.valid)
+}
+
+func TestReconnectingConnDoesNotReconnectChangedUser(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:1)/test", 1, time.Duration(0))
+	var dials int
+	db.OnDial = func(conn *Conn) error {
+		dials++
+		return nil
+	}
+	broken, _ := newSessionConn("8.0.22")
+	broken.userChanged = true
+	conn := &ReconnectingConn{Conn: broken, db: db}
+
+	_, err := conn.Query("SELECT 1")
+	assert.NotNil(t, err)
+	_, ok := err.(*net.OpError)
+	assert.False(t, ok)
+	assert.True(t, broken == conn.Conn)
+	assert.Equal(t, dials, 0)
+}
+
+func TestReconnectingConnDoesNotReconnectInTransaction(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:1)/test", 1, time.Duration(0))
+	db.Reconnect.RetryExec = true
+	broken, _ := newSessionConn("8.0.22")
+	broken.tx = &Tx{conn: broken}
+	conn := &ReconnectingConn{Conn: broken, db: db}
+
+	_, err := conn.Exec("DELETE FROM dogs")
+	assert.NotNil(t, err)
+	_, ok := err.(*net.OpError)
+	assert.False(t, ok)
+	assert.True(t, broken == conn.Conn)
+
+	_, err = conn.Query("SELECT 1")
+	assert.NotNil(t, err)
+	_, ok = err.(*net.OpError)
+	assert.False(t, ok)
+	assert.True(t, broken == conn.Conn)
+}
+
+func TestReconnectingConnExecRetriesWithRetryExec(t *testing.T) {
+	db := NewDB("root@tcp(127.0.0.1:1)/test", 1, time.Duration(0))
+	broken, _ := newSessionConn("8.0.22")
+	conn := &ReconnectingConn{Conn: broken, db: db}
+
+	_, err := conn.Exec("DELETE FROM dogs")
+	_, ok := err.(*net.OpError)
+	assert.False(t, ok)
+
+	db.Reconnect.RetryExec = true
+	broken, _ = newSessionConn("8.0.22")
+	conn = &ReconnectingConn{Conn: broken, db: db}
+	_, err = conn.Exec("DELETE FROM dogs")
+	_, ok = err.(*net.OpError)
+	assert.True(t, ok)
+}
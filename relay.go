@@ -0,0 +1,173 @@
+package mysqldriver
+
+import (
+	"io"
+
+	"github.com/pubnative/mysqlproto-go"
+)
+
+// maxPacketPayload is the maximum payload of a single packet,
+// longer payloads are split into several packets
+const maxPacketPayload = 0xffffff
+
+// RelayTo writes the result set to dst as the server responds
+// to COM_QUERY, so a proxy can forward it to its client connection.
+// Sequence IDs of the packets start from 1 because the client's
+// COM_QUERY has sequence ID 0. Column definitions are encoded
+// from Columns, row packets and the terminating EOF_PACKET
+// or ERR_PACKET are written as they're received from the server.
+// Rows which are already read by Next aren't written.
+// The client is expected not to use CLIENT_DEPRECATE_EOF capability.
+//
+// All rows are read by RelayTo. ERR_PACKET returned by the server
+// instead of a row is written to dst and returned as an error.
+// When writing to dst fails, the rest of rows is discarded.
+//
+//	rows, _ := conn.Query(query) // query received from the client
+//	if err := rows.RelayTo(client); err != nil {
+//		// handle error
+//	}
+func (r *Rows) RelayTo(dst io.Writer) error {
+	w := &packetWriter{w: dst, seq: 1}
+
+	if len(r.definitions) == 0 {
+		// statement which doesn't return rows is answered with OK_PACKET
+		return w.write(okPacketPayload(r.conn.lastOK))
+	}
+
+	err := w.write(appendLengthEncodedInteger(nil, uint64(len(r.definitions))))
+	for _, column := range r.definitions {
+		if err == nil {
+			err = w.write(columnDefinitionPayload(column))
+		}
+	}
+	if err == nil {
+		err = w.write(eofPacketPayload(r.conn.status))
+	}
+	if err != nil {
+		r.discard()
+		return err
+	}
+
+	if r.buffered {
+		for ; r.position < len(r.buffer); r.position++ {
+			if err := w.write(r.buffer[r.position]); err != nil {
+				r.discard()
+				return err
+			}
+		}
+		r.eof = true
+		return w.write(eofPacketPayload(r.conn.status))
+	}
+
+	if r.errRead != nil {
+		return r.errRead
+	}
+	if r.eof {
+		return w.write(eofPacketPayload(r.conn.status))
+	}
+
+	for {
+		packet, err := r.conn.conn.NextPacket()
+		if err != nil {
+			r.errRead = r.conn.readError(err)
+			r.conn.release()
+			return r.errRead
+		}
+
+		payload := packet.Payload
+		switch {
+		case isEOFPacket(payload):
+			if len(payload) >= 5 {
+				r.conn.status = uint16(payload[3]) | uint16(payload[4])<<8
+			}
+			r.eof = true
+			r.conn.release()
+			return w.write(payload)
+		case len(payload) > 0 && payload[0] == mysqlproto.ERR_PACKET:
+			errPacket, err := mysqlproto.ParseERRPacket(payload, r.conn.conn.CapabilityFlags)
+			if err != nil {
+				r.errRead = r.conn.readError(err)
+			} else {
+				r.errRead = errPacket
+			}
+			r.conn.release()
+			if err := w.write(payload); err != nil {
+				return err
+			}
+			return r.errRead
+		}
+
+		if err := w.write(payload); err != nil {
+			r.discard()
+			return err
+		}
+	}
+}
+
+// packetWriter writes payloads as packets
+// with successive sequence IDs
+type packetWriter struct {
+	w   io.Writer
+	seq byte
+}
+
+func (w *packetWriter) write(payload []byte) error {
+	for {
+		length := len(payload)
+		if length > maxPacketPayload {
+			length = maxPacketPayload
+		}
+
+		header := [4]byte{byte(length), byte(length >> 8), byte(length >> 16), w.seq}
+		w.seq++
+		if _, err := w.w.Write(header[:]); err != nil {
+			return err
+		}
+		if _, err := w.w.Write(payload[:length]); err != nil {
+			return err
+		}
+
+		// payload of the maximum length is followed by a packet
+		// with the rest of it, even if the rest is empty
+		if length < maxPacketPayload {
+			return nil
+		}
+		payload = payload[length:]
+	}
+}
+
+// columnDefinitionPayload encodes Protocol::ColumnDefinition41 packet.
+// MariaDB extended metadata isn't encoded.
+func columnDefinitionPayload(column ColumnInfo) []byte {
+	payload := appendLengthEncodedString(nil, "def")
+	payload = appendLengthEncodedString(payload, column.Schema)
+	payload = appendLengthEncodedString(payload, column.Table)
+	payload = appendLengthEncodedString(payload, column.OrgTable)
+	payload = appendLengthEncodedString(payload, column.Name)
+	payload = appendLengthEncodedString(payload, column.OrgName)
+	return append(payload,
+		0x0c,
+		byte(column.CharacterSet), byte(column.CharacterSet>>8),
+		byte(column.MaxLength), byte(column.MaxLength>>8), byte(column.MaxLength>>16), byte(column.MaxLength>>24),
+		column.Type,
+		byte(column.Flags), byte(column.Flags>>8),
+		column.Decimals,
+		0x00, 0x00, // filler
+	)
+}
+
+// eofPacketPayload encodes EOF_PACKET without warnings
+func eofPacketPayload(status uint16) []byte {
+	return []byte{mysqlproto.EOF_PACKET, 0x00, 0x00, byte(status), byte(status >> 8)}
+}
+
+// okPacketPayload encodes OK_PACKET of CLIENT_PROTOCOL_41
+func okPacketPayload(pkt mysqlproto.OKPacket) []byte {
+	payload := []byte{mysqlproto.OK_PACKET}
+	payload = appendLengthEncodedInteger(payload, pkt.AffectedRows)
+	payload = appendLengthEncodedInteger(payload, pkt.LastInsertID)
+	payload = append(payload, byte(pkt.StatusFlags), byte(pkt.StatusFlags>>8))
+	payload = append(payload, byte(pkt.Warnings), byte(pkt.Warnings>>8))
+	return append(payload, pkt.Info...)
+}
@@ -0,0 +1,102 @@
+package mysqldriver
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// readPackets splits data into payloads and checks their sequence IDs
+func readPackets(t *testing.T, data []byte) [][]byte {
+	var payloads [][]byte
+	for seq := byte(1); len(data) > 0; seq++ {
+		length := packetLength(data)
+		assert.Equal(t, data[3], seq)
+		payloads = append(payloads, data[4:4+length])
+		data = data[4+length:]
+	}
+	return payloads
+}
+
+func TestRowsRelayTo(t *testing.T) {
+	lastEOF := []byte{mysqlproto.EOF_PACKET, 0x01, 0x00, 0x22, 0x00}
+	conn := newPacketConn(
+		[]byte{0x02},
+		columnDefinition("id"),
+		columnDefinition("name"),
+		eofPacket,
+		[]byte{0x01, '1', 0x03, 'b', 'o', 'b'},
+		[]byte{0x01, '2', 0xfb},
+		lastEOF,
+	)
+
+	rows, err := conn.Query("SELECT id, name FROM people")
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, rows.RelayTo(&buf))
+	payloads := readPackets(t, buf.Bytes())
+	assert.Equal(t, len(payloads), 7)
+	assert.Equal(t, payloads[0], []byte{0x02})
+
+	column, err := parseColumnDefinition(payloads[2], false)
+	assert.NoError(t, err)
+	assert.Equal(t, column, rows.Columns()[1])
+
+	assert.True(t, isEOFPacket(payloads[3]))
+	assert.Equal(t, payloads[4], []byte{0x01, '1', 0x03, 'b', 'o', 'b'})
+	assert.Equal(t, payloads[5], []byte{0x01, '2', 0xfb})
+	assert.Equal(t, payloads[6], lastEOF)
+
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.LastError())
+	assert.Equal(t, conn.status, uint16(0x22))
+	assert.Equal(t, conn.acquire(), nil)
+}
+
+func TestRowsRelayToSkipsReadRows(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01},
+		columnDefinition("id"),
+		eofPacket,
+		[]byte{0x01, '1'},
+		[]byte{0x01, '2'},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT id FROM people")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+
+	var buf bytes.Buffer
+	assert.NoError(t, rows.RelayTo(&buf))
+	payloads := readPackets(t, buf.Bytes())
+	assert.Equal(t, len(payloads), 5)
+	assert.Equal(t, payloads[3], []byte{0x01, '2'})
+}
+
+func TestRowsRelayToOKPacket(t *testing.T) {
+	conn := &Conn{lastOK: mysqlproto.OKPacket{AffectedRows: 3, LastInsertID: 300, StatusFlags: 2}}
+	rows := &Rows{conn: conn, eof: true}
+
+	var buf bytes.Buffer
+	assert.NoError(t, rows.RelayTo(&buf))
+	assert.Equal(t, buf.Bytes(), []byte{
+		0x09, 0x00, 0x00, 0x01,
+		mysqlproto.OK_PACKET, 0x03, 0xfc, 0x2c, 0x01, 0x02, 0x00, 0x00, 0x00,
+	})
+}
+
+func TestPacketWriterSplitsLargePayload(t *testing.T) {
+	var buf bytes.Buffer
+	w := &packetWriter{w: &buf, seq: 1}
+	assert.NoError(t, w.write(make([]byte, maxPacketPayload)))
+
+	data := buf.Bytes()
+	assert.Equal(t, len(data), 4+maxPacketPayload+4)
+	assert.Equal(t, data[:4], []byte{0xff, 0xff, 0xff, 0x01})
+	assert.Equal(t, data[4+maxPacketPayload:], []byte{0x00, 0x00, 0x00, 0x02})
+	assert.Equal(t, w.seq, byte(3))
+}
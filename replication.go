@@ -0,0 +1,76 @@
+package mysqldriver
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// ErrNotReplica is returned by ReplicationLag when server isn't a replica
+var ErrNotReplica = errors.New("mysqldriver: server is not a replica")
+
+// ErrReplicationStopped is returned by ReplicationLag when
+// replication threads aren't running, so the lag is unknown
+var ErrReplicationStopped = errors.New("mysqldriver: replication is stopped")
+
+// ReplicationLag returns how far the replica is behind the source
+// using Seconds_Behind_Source value of SHOW REPLICA STATUS.
+// SHOW SLAVE STATUS is used for servers older than MySQL 8.0.22.
+// For multi-source replication the max lag of all channels is returned.
+//
+// It returns ErrNotReplica when replication isn't configured
+// and ErrReplicationStopped when lag is unknown because
+// replication isn't running.
+func (c *Conn) ReplicationLag() (time.Duration, error) {
+	rows, err := c.Query("SHOW REPLICA STATUS")
+	if code, ok := ErrorCode(err); ok && code == errCodeParse {
+		rows, err = c.Query("SHOW SLAVE STATUS")
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	column := ""
+	for _, col := range rows.Columns() {
+		if col.Name == "Seconds_Behind_Source" || col.Name == "Seconds_Behind_Master" {
+			column = col.Name
+		}
+	}
+	if column == "" {
+		rows.discard()
+		return 0, errors.New("mysqldriver: replica status doesn't contain Seconds_Behind_Source column")
+	}
+
+	var lags []columnValue
+	for rows.Next() {
+		lag, null := rows.Row().NullBytes(column)
+		lags = append(lags, columnValue{data: append([]byte(nil), lag...), null: null})
+	}
+	if err := rows.LastError(); err != nil {
+		return 0, err
+	}
+
+	return maxReplicationLag(lags)
+}
+
+// maxReplicationLag returns the max lag of the replication channels
+func maxReplicationLag(lags []columnValue) (time.Duration, error) {
+	if len(lags) == 0 {
+		return 0, ErrNotReplica
+	}
+
+	var max time.Duration
+	for _, lag := range lags {
+		if lag.null {
+			return 0, ErrReplicationStopped
+		}
+		seconds, err := strconv.ParseInt(string(lag.data), 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		if lag := time.Duration(seconds) * time.Second; lag > max {
+			max = lag
+		}
+	}
+	return max, nil
+}
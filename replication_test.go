@@ -0,0 +1,33 @@
+package mysqldriver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxReplicationLag(t *testing.T) {
+	lag, err := maxReplicationLag([]columnValue{{data: []byte("3")}, {data: []byte("10")}, {data: []byte("0")}})
+	assert.NoError(t, err)
+	assert.Equal(t, lag, 10*time.Second)
+
+	_, err = maxReplicationLag(nil)
+	assert.Equal(t, err, ErrNotReplica)
+
+	_, err = maxReplicationLag([]columnValue{{data: []byte("3")}, {null: true}})
+	assert.Equal(t, err, ErrReplicationStopped)
+
+	_, err = maxReplicationLag([]columnValue{{data: []byte("abc")}})
+	assert.NotNil(t, err)
+}
+
+func TestConnReplicationLagOfPrimary(t *testing.T) {
+	conn, err := NewConn("root", "", "tcp", "127.0.0.1:3306", "test", time.Duration(0))
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.ReplicationLag()
+	assert.Equal(t, err, ErrNotReplica)
+	assert.True(t, conn.valid)
+}
@@ -0,0 +1,135 @@
+package mysqldriver
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pubnative/mysqlproto-go"
+)
+
+// handleOKPacket stores the result of the last statement
+func (c *Conn) handleOKPacket(pkt mysqlproto.OKPacket) {
+	c.status = pkt.StatusFlags
+	c.warnings = pkt.Warnings
+	c.lastOK = pkt
+}
+
+// LastInsertID returns AUTO_INCREMENT value generated for the first row
+// inserted by the last statement performed by Exec.
+// It's zero when statement hasn't generated the value.
+//
+//	if _, err := conn.Exec("INSERT INTO dogs(name) VALUES ('Max')"); err == nil {
+//		id := conn.LastInsertID()
+//	}
+func (c *Conn) LastInsertID() uint64 {
+	return c.lastOK.LastInsertID
+}
+
+// AffectedRows returns the number of rows affected by the last
+// statement performed by Exec (see func (Conn) MatchedRows)
+func (c *Conn) AffectedRows() uint64 {
+	return c.lastOK.AffectedRows
+}
+
+// Warnings returns the number of warnings of the last statement
+// performed by Exec or Query, the same as WarningCount.
+//
+// Deprecated: use WarningCount.
+func (c *Conn) Warnings() uint16 {
+	return c.warnings
+}
+
+// MatchedRows returns the number of rows matched by WHERE clause
+// of the last UPDATE statement performed by Exec
+// regardless of whether they were changed or not.
+//
+// AffectedRows of OKPacket depends on CLIENT_FOUND_ROWS capability.
+// It's enabled by default, so AffectedRows of UPDATE is the number of
+// matched rows the same as MatchedRows returns. When it's disabled
+// with Options.NoFoundRows, AffectedRows is the number of changed rows
+// and it can be less than MatchedRows. It also affects
+// INSERT ... ON DUPLICATE KEY UPDATE statement: row which is found
+// but not changed counts as 1 affected row with CLIENT_FOUND_ROWS
+// and as 0 without it.
+//
+// For all other statements MatchedRows returns AffectedRows.
+func (c *Conn) MatchedRows() uint64 {
+	if matched, ok := infoNumber(c.lastOK.Info, "Rows matched:"); ok {
+		return matched
+	}
+	return c.lastOK.AffectedRows
+}
+
+// AffectedRowsError is returned by ExecExpect when statement
+// affects unexpected number of rows
+type AffectedRowsError struct {
+	Expected uint64
+	Affected uint64
+}
+
+func (e *AffectedRowsError) Error() string {
+	return "mysqldriver: statement affected " + strconv.FormatUint(e.Affected, 10) +
+		" rows instead of " + strconv.FormatUint(e.Expected, 10)
+}
+
+// ExecExactlyOne executes the statement and returns AffectedRowsError
+// unless exactly one row is affected (see func (Conn) ExecExpect)
+//
+//	err := conn.ExecExactlyOne("UPDATE dogs SET name = 'Max' WHERE id = 1")
+func (c *Conn) ExecExactlyOne(sql string) error {
+	return c.ExecExpect(sql, 1)
+}
+
+// ExecExpect executes the statement and returns AffectedRowsError
+// when the number of affected rows isn't equal to expected one.
+// Statement isn't reverted, so it should be performed
+// within the transaction which is rolled back on error:
+//
+//	err := conn.WithTransaction(func(tx *mysqldriver.Tx) error {
+//		return tx.ExecExpect("DELETE FROM dogs WHERE owner_id = 1", 2)
+//	})
+//
+// Number of affected rows of UPDATE statement
+// depends on Options.NoFoundRows (see func (Conn) MatchedRows).
+func (c *Conn) ExecExpect(sql string, expected uint64) error {
+	pkt, err := c.Exec(sql)
+	if err != nil {
+		return err
+	}
+	return checkAffectedRows(pkt, expected)
+}
+
+// ExecExpect executes the statement within the transaction
+// (see func (Conn) ExecExpect)
+func (tx *Tx) ExecExpect(sql string, expected uint64) error {
+	pkt, err := tx.Exec(sql)
+	if err != nil {
+		return err
+	}
+	return checkAffectedRows(pkt, expected)
+}
+
+func checkAffectedRows(pkt mysqlproto.OKPacket, expected uint64) error {
+	if pkt.AffectedRows != expected {
+		return &AffectedRowsError{Expected: expected, Affected: pkt.AffectedRows}
+	}
+	return nil
+}
+
+// infoNumber reads number of the given field from the info
+// of OK_PACKET like "Rows matched: 1  Changed: 0  Warnings: 0"
+func infoNumber(info, field string) (uint64, bool) {
+	start := strings.Index(info, field)
+	if start < 0 {
+		return 0, false
+	}
+
+	value := strings.TrimLeft(info[start+len(field):], " ")
+	end := 0
+	for end < len(value) && value[end] >= '0' && value[end] <= '9' {
+		end++
+	}
+
+	num, err := strconv.ParseUint(value[:end], 10, 64)
+	return num, err == nil
+}
@@ -0,0 +1,102 @@
+package mysqldriver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInfoNumber(t *testing.T) {
+	info := "Rows matched: 12  Changed: 3  Warnings: 0"
+	num, ok := infoNumber(info, "Rows matched:")
+	assert.True(t, ok)
+	assert.Equal(t, num, uint64(12))
+	num, ok = infoNumber(info, "Changed:")
+	assert.True(t, ok)
+	assert.Equal(t, num, uint64(3))
+	_, ok = infoNumber(info, "Duplicates:")
+	assert.False(t, ok)
+	_, ok = infoNumber("", "Rows matched:")
+	assert.False(t, ok)
+}
+
+func TestConnMatchedRowsWithoutInfo(t *testing.T) {
+	conn := &Conn{}
+	conn.handleOKPacket(mysqlproto.OKPacket{AffectedRows: 4})
+	assert.Equal(t, conn.MatchedRows(), uint64(4))
+	conn.handleOKPacket(mysqlproto.OKPacket{AffectedRows: 1, Info: "Rows matched: 2  Changed: 1  Warnings: 0"})
+	assert.Equal(t, conn.MatchedRows(), uint64(2))
+}
+
+func TestConnLastOKPacketAccessors(t *testing.T) {
+	conn := &Conn{}
+	conn.handleOKPacket(mysqlproto.OKPacket{AffectedRows: 3, LastInsertID: 42, Warnings: 1})
+	assert.Equal(t, conn.LastInsertID(), uint64(42))
+	assert.Equal(t, conn.AffectedRows(), uint64(3))
+	assert.Equal(t, conn.Warnings(), uint16(1))
+}
+
+func TestConnLastInsertID(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec(`INSERT INTO people(firstname) VALUES ("bob"), ("ben")`)
+		assert.NoError(t, err)
+		assert.Equal(t, conn.LastInsertID(), uint64(1))
+		assert.Equal(t, conn.AffectedRows(), uint64(2))
+
+		_, err = conn.Exec(`INSERT IGNORE INTO people(cars) VALUES (1000)`)
+		assert.NoError(t, err)
+		assert.Equal(t, conn.LastInsertID(), uint64(3))
+		assert.Equal(t, conn.Warnings(), uint16(1)) // value is out of range of TINYINT
+	})
+}
+
+func testMatchedRows(t *testing.T, opts Options, affected uint64) {
+	conn, err := NewConnOptions(context.Background(), "root", "", "tcp", "127.0.0.1:3306", "test", time.Duration(0), opts)
+	assert.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, conn.Capabilities().Has(CapabilityFoundRows), !opts.NoFoundRows)
+
+	_, err = conn.Exec("CREATE TEMPORARY TABLE matched (id int, age int)")
+	assert.NoError(t, err)
+	_, err = conn.Exec("INSERT INTO matched VALUES (1, 3), (2, 3), (3, 5)")
+	assert.NoError(t, err)
+
+	pkt, err := conn.Exec("UPDATE matched SET age = 5 WHERE id < 4")
+	assert.NoError(t, err)
+	assert.Equal(t, pkt.AffectedRows, affected)
+	assert.Equal(t, conn.MatchedRows(), uint64(3))
+}
+
+func TestConnMatchedRowsWithFoundRows(t *testing.T) {
+	testMatchedRows(t, Options{}, 3)
+}
+
+func TestConnMatchedRowsWithoutFoundRows(t *testing.T) {
+	testMatchedRows(t, Options{NoFoundRows: true}, 2)
+}
+
+func TestConnExecExpect(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec(`INSERT INTO people(firstname, age) VALUES ("Bob", 3), ("Alice", 3)`)
+		assert.NoError(t, err)
+
+		assert.NoError(t, conn.ExecExactlyOne(`UPDATE people SET age = 4 WHERE firstname = "Bob"`))
+		assert.NoError(t, conn.ExecExpect(`UPDATE people SET age = 5`, 2))
+
+		err = conn.ExecExactlyOne(`UPDATE people SET age = 6`)
+		assert.Equal(t, err, &AffectedRowsError{Expected: 1, Affected: 2})
+		assert.Equal(t, err.Error(), "mysqldriver: statement affected 2 rows instead of 1")
+
+		err = conn.ExecExactlyOne(`DELETE FROM people WHERE firstname = "Unknown"`)
+		assert.Equal(t, err, &AffectedRowsError{Expected: 1, Affected: 0})
+
+		err = conn.WithTransaction(func(tx *Tx) error {
+			return tx.ExecExpect("DELETE FROM people", 1)
+		})
+		assert.Equal(t, err, &AffectedRowsError{Expected: 1, Affected: 2})
+		assert.Equal(t, countPeople(t, conn), 2)
+	})
+}
@@ -0,0 +1,124 @@
+package mysqldriver
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/pubnative/mysqlproto-go"
+)
+
+// ColumnCountError is returned by Query when the server sends
+// less column definitions than it declared in the result set header.
+// The stream of packets can't be trusted anymore,
+// so the connection becomes invalid.
+type ColumnCountError struct {
+	Declared int // number of columns in the result set header
+	Read     int // number of column definitions received
+}
+
+func (e *ColumnCountError) Error() string {
+	return fmt.Sprintf("mysqldriver: result set declares %d columns but %d column definitions are received", e.Declared, e.Read)
+}
+
+// readResultSet reads the response to COM_QUERY up to the first row.
+// Statements which don't return rows are answered with OK_PACKET,
+// in this case the returned Rows are already drained.
+func (c *Conn) readResultSet() (*Rows, error) {
+	packet, err := c.conn.NextPacket()
+	if err != nil {
+		return nil, err
+	}
+
+	payload := packet.Payload
+	if len(payload) == 0 {
+		return nil, errMalformedPacket
+	}
+
+	switch payload[0] {
+	case mysqlproto.ERR_PACKET:
+		errPacket, err := mysqlproto.ParseERRPacket(payload, c.conn.CapabilityFlags)
+		if err != nil {
+			return nil, err
+		}
+		return nil, errPacket
+	case mysqlproto.OK_PACKET:
+		pkt, err := mysqlproto.ParseOKPacket(payload, c.conn.CapabilityFlags)
+		if err != nil {
+			return nil, err
+		}
+		c.handleOKPacket(pkt)
+		c.trackSessionState(payload)
+		return &Rows{conn: c, eof: true, moreResults: pkt.StatusFlags&serverMoreResultsExists != 0}, nil
+	}
+
+	count, _, err := readLengthEncodedInteger(payload, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := c.readColumnDefinitions(int(count))
+	if err != nil {
+		return nil, err
+	}
+
+	// warning count is sent after the last row
+	c.warnings = 0
+	return &Rows{
+		conn:        c,
+		definitions: columns,
+		columns:     make(map[string]columnValue, len(columns)),
+	}, nil
+}
+
+// readRow returns payload of the next row packet. It returns nil
+// when all rows are read. ERR_PACKET sent instead of the row,
+// e.g. when query is killed, is returned as an error.
+func (r *Rows) readRow() ([]byte, error) {
+	if r.cursor != nil && r.cursor.fetch {
+		if err := r.fetchRows(); err != nil {
+			return nil, err
+		}
+	}
+
+	packet, err := r.conn.conn.NextPacket()
+	if err != nil {
+		return nil, err
+	}
+
+	payload := packet.Payload
+	if isEOFPacket(payload) {
+		// header(1), warnings(2), status_flags(2)
+		if len(payload) >= 5 {
+			r.warnings = binary.LittleEndian.Uint16(payload[1:])
+			r.conn.warnings = r.warnings
+			r.conn.status = binary.LittleEndian.Uint16(payload[3:])
+			r.moreResults = r.conn.status&serverMoreResultsExists != 0
+		}
+		// batch of the cursor is read, but the cursor has more rows
+		if r.cursor != nil && !r.conn.cursorExhausted() {
+			r.cursor.fetch = true
+			return r.readRow()
+		}
+		return nil, nil
+	}
+
+	if len(payload) > 0 && payload[0] == mysqlproto.ERR_PACKET {
+		errPacket, err := mysqlproto.ParseERRPacket(payload, r.conn.conn.CapabilityFlags)
+		if err != nil {
+			return nil, err
+		}
+		return nil, errPacket
+	}
+
+	return payload, nil
+}
+
+// readError marks connection as invalid unless err is ERR_PACKET
+// returned by the server, the stream is consistent in this case
+func (c *Conn) readError(err error) error {
+	if _, ok := err.(mysqlproto.ERRPacket); ok {
+		return err
+	}
+	c.valid = false
+	return c.timeoutError(err)
+}
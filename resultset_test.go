@@ -0,0 +1,211 @@
+package mysqldriver
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// newPacketConn returns connection which reads given payloads
+// as a response to any command
+func newPacketConn(payloads ...[]byte) *Conn {
+	data := framePackets(nil, payloads...)
+	stream := mysqlproto.NewStream(&readRecorder{data: bytes.NewReader(data)}, 0)
+	return &Conn{
+		conn:  mysqlproto.Conn{Stream: stream, CapabilityFlags: capabilityFlags},
+		valid: true,
+	}
+}
+
+// framePackets prepends headers to the payloads. Sequence IDs
+// are taken from seqs, when it's nil they follow a query.
+func framePackets(seqs []byte, payloads ...[]byte) []byte {
+	var data []byte
+	for i, payload := range payloads {
+		seq := byte(i + 1)
+		if seqs != nil {
+			seq = seqs[i]
+		}
+		length := len(payload)
+		data = append(data, byte(length), byte(length>>8), byte(length>>16), seq)
+		data = append(data, payload...)
+	}
+	return data
+}
+
+func columnDefinition(name string) []byte {
+	payload := []byte{0x03, 'd', 'e', 'f', 0x00, 0x00, 0x00}
+	payload = append(payload, byte(len(name)))
+	payload = append(payload, name...)
+	payload = append(payload, 0x00)
+	return append(payload, 0x0c, 0x21, 0x00, 0x0b, 0x00, 0x00, 0x00, fieldTypeVarString, 0x00, 0x00, 0x00, 0x00, 0x00)
+}
+
+var eofPacket = []byte{mysqlproto.EOF_PACKET, 0x00, 0x00, 0x02, 0x00}
+
+func TestQueryReturnsErrorWhenColumnDefinitionsAreMissing(t *testing.T) {
+	conn := newPacketConn([]byte{0x02}, columnDefinition("id"), eofPacket)
+
+	rows, err := conn.Query("SELECT id, name FROM people")
+	assert.Nil(t, rows)
+	assert.Equal(t, err, &ColumnCountError{Declared: 2, Read: 1})
+	assert.Equal(t, err.Error(), "mysqldriver: result set declares 2 columns but 1 column definitions are received")
+	assert.False(t, conn.valid)
+	assert.Equal(t, conn.acquire(), nil)
+}
+
+func TestQueryReadsColumnDefinitions(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x02},
+		columnDefinition("id"),
+		columnDefinition("name"),
+		eofPacket,
+		[]byte{0x01, '1', 0x03, 'b', 'o', 'b'},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT id, name FROM people")
+	assert.NoError(t, err)
+	assert.Equal(t, len(rows.Columns()), 2)
+	assert.Equal(t, rows.Columns()[1].Name, "name")
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Int(), 1)
+	assert.Equal(t, rows.String(), "bob")
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.LastError())
+	assert.True(t, conn.valid)
+}
+
+func TestRowsNullBytesOfShortRow(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x02},
+		columnDefinition("id"),
+		columnDefinition("name"),
+		eofPacket,
+		[]byte{0x01, '1'},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT id, name FROM people")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Int(), 1)
+	value, null := rows.NullBytes()
+	assert.Nil(t, value)
+	assert.True(t, null)
+	assert.False(t, rows.Next())
+	assert.Equal(t, rows.LastError(), errMalformedPacket)
+}
+
+func TestRowsBytesCopy(t *testing.T) {
+	id := columnDefinition("id")
+	id[len(id)-6] = fieldTypeLong
+	conn := newPacketConn(
+		[]byte{0x03}, id, columnDefinition("name"), columnDefinition("note"), eofPacket,
+		[]byte{0x00, 0x10, 0x2a, 0x00, 0x00, 0x00, 0x00}, // 42, "", NULL
+		[]byte{0x00, 0x10, 0x2b, 0x00, 0x00, 0x00, 0x00}, // 43, "", NULL
+		eofPacket,
+	)
+	stmt := &Stmt{conn: conn, id: 1}
+
+	rows, err := stmt.Query()
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	copied := rows.BytesCopy()
+	assert.Equal(t, copied, []byte("42"))
+	value, _, err := rows.Value("id")
+	assert.NoError(t, err)
+	assert.Equal(t, rows.BytesCopy(), []byte{})
+	assert.Nil(t, rows.BytesCopy())
+
+	// number of the next row is decoded into the same buffer
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Int(), 43)
+	assert.Equal(t, value, []byte("43"))
+	assert.Equal(t, copied, []byte("42"))
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.LastError())
+}
+
+func TestRowsReuseBuffersOfBinaryRows(t *testing.T) {
+	id := columnDefinition("id")
+	id[len(id)-6] = fieldTypeLong
+	conn := newPacketConn([]byte{0x02}, id, columnDefinition("name"), eofPacket)
+	stmt := &Stmt{conn: conn, id: 1}
+	rows, err := stmt.Query()
+	assert.NoError(t, err)
+	packet := []byte{0x00, 0x00, 0x2a, 0x00, 0x00, 0x00, 0x03, 'b', 'o', 'b'}
+
+	// decoding buffer and values of the columns are reused by every row
+	allocs := testing.AllocsPerRun(1000, func() {
+		rows.setRow(packet)
+		rows.Int()
+		rows.Bytes()
+	})
+	assert.Equal(t, allocs, 0.0)
+	assert.NoError(t, rows.LastError())
+}
+
+func TestRowsValuesAfterNext(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x02}, columnDefinition("id"), columnDefinition("name"), eofPacket,
+		[]byte{0x01, '1', 0x03, 'b', 'o', 'b'},
+		[]byte{0x01, '2', 0x03, 'm', 'a', 'x'},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT id, name FROM people")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	aliased := rows.Bytes()
+	row := rows.Row()
+	id := row.Int("id")
+	name, copied, aliasedName := row.String("name"), row.BytesCopy("name"), row.Bytes("name")
+	assert.Equal(t, aliased, []byte("1"))
+
+	assert.True(t, rows.Next())
+	// stream reuses the buffer of the previous row
+	copy(aliased, "9")
+	copy(aliasedName, "xxx")
+	assert.Equal(t, id, 1)
+	assert.Equal(t, name, "bob")
+	assert.Equal(t, copied, []byte("bob"))
+	assert.Equal(t, rows.String(), "2")
+	assert.Equal(t, rows.String(), "max")
+	assert.False(t, rows.Next())
+}
+
+func TestRowsCloseDiscardsRows(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01}, columnDefinition("name"), eofPacket,
+		[]byte{0x03, 'b', 'o', 'b'},
+		[]byte{0x03, 'm', 'a', 'x'},
+		[]byte{0x03, 'b', 'e', 'n'},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT name FROM dogs")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	assert.NoError(t, rows.Close())
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.Close())
+	assert.True(t, conn.valid)
+	assert.Equal(t, conn.acquire(), nil)
+}
+
+func TestRowsCloseReturnsErrorOfStream(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01}, columnDefinition("name"), eofPacket,
+		[]byte{0x03, 'b', 'o', 'b'},
+	)
+
+	rows, err := conn.Query("SELECT name FROM dogs")
+	assert.NoError(t, err)
+	assert.Equal(t, rows.Close(), io.EOF)
+	assert.False(t, conn.valid)
+	assert.Equal(t, conn.acquire(), nil)
+}
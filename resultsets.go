@@ -0,0 +1,106 @@
+package mysqldriver
+
+// NextResultSet moves to the next result set when the statement
+// returns several of them, e.g. CALL of the stored procedure
+// with multiple SELECT statements. Unread rows of the current
+// result set are discarded. It returns false when there are
+// no more result sets or an error occurred (see LastError() function).
+// Results of the statements which don't return rows are skipped.
+//
+//	rows, _ := conn.Query("CALL dogs_and_cats()")
+//	for rows.Next() {
+//		rows.String() // dog's name
+//	}
+//	if rows.NextResultSet() {
+//		for rows.Next() {
+//			rows.String() // cat's name
+//		}
+//	}
+//
+// Connection is busy until all result sets are read,
+// so NextResultSet must be called until it returns false.
+func (r *Rows) NextResultSet() bool {
+	if r.buffered || r.errRead != nil {
+		return false
+	}
+
+	if !r.eof {
+		if err := r.drainRows(); err != nil {
+			r.errRead = err
+			r.conn.release()
+			return false
+		}
+		r.eof = true
+		if !r.moreResults {
+			r.conn.release()
+			return false
+		}
+	}
+
+	for r.moreResults {
+		next, err := r.readResultSet()
+		if err != nil {
+			r.errRead = r.conn.readError(err)
+			r.moreResults = false
+			r.conn.release()
+			return false
+		}
+
+		r.moreResults = next.moreResults
+		if !next.eof {
+			r.definitions = next.definitions
+			r.columns = next.columns
+			r.packet, r.offset, r.readColumns = nil, 0, 0
+			r.eof = false
+			return true
+		}
+		if !r.moreResults {
+			r.conn.release()
+		}
+	}
+	return false
+}
+
+// drainRows reads the rest of the rows of the current result set
+// without releasing the connection
+func (r *Rows) drainRows() error {
+	if r.cursor != nil {
+		// the rest of the cursor isn't fetched, it's closed
+		// by the server on the next execution of the statement
+		fetch := r.cursor.fetch
+		r.cursor = nil
+		if fetch {
+			return nil
+		}
+	}
+
+	for {
+		packet, err := r.readRow()
+		if err != nil {
+			return r.conn.readError(err)
+		}
+		if packet == nil {
+			return nil
+		}
+	}
+}
+
+// skipResultSets reads the rest of the result sets
+// after the current one without releasing the connection
+func (r *Rows) skipResultSets() error {
+	for r.moreResults {
+		next, err := r.readResultSet()
+		if err != nil {
+			r.moreResults = false
+			return r.conn.readError(err)
+		}
+		if !next.eof {
+			if err := next.drainRows(); err != nil {
+				r.moreResults = false
+				return err
+			}
+		}
+		r.moreResults = next.moreResults
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package mysqldriver
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// eofMoreResultsPacket terminates the result set
+// which is followed by another one
+var eofMoreResultsPacket = []byte{mysqlproto.EOF_PACKET, 0x00, 0x00, 0x0a, 0x00}
+
+func TestRowsNextResultSet(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01}, columnDefinition("dog"), eofPacket,
+		[]byte{0x03, 'M', 'a', 'x'},
+		[]byte{0x04, 'R', 'o', 'c', 'k'},
+		eofMoreResultsPacket,
+		[]byte{0x02}, columnDefinition("id"), columnDefinition("cat"), eofPacket,
+		[]byte{0x01, '1', 0x03, 'T', 'o', 'm'},
+		eofMoreResultsPacket,
+		[]byte{mysqlproto.OK_PACKET, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00},
+	)
+
+	rows, err := conn.Query("CALL dogs_and_cats()")
+	assert.NoError(t, err)
+
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.String(), "Max")
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.String(), "Rock")
+	assert.False(t, rows.Next())
+	assert.Equal(t, conn.acquire(), ErrConnectionBusy)
+
+	assert.True(t, rows.NextResultSet())
+	assert.Equal(t, rows.ColumnNames(), []string{"id", "cat"})
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Int(), 1)
+	assert.Equal(t, rows.String(), "Tom")
+	assert.False(t, rows.Next())
+
+	assert.False(t, rows.NextResultSet())
+	assert.NoError(t, rows.LastError())
+	assert.NoError(t, conn.acquire())
+}
+
+func TestRowsNextResultSetDiscardsUnreadRows(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01}, columnDefinition("dog"), eofPacket,
+		[]byte{0x03, 'M', 'a', 'x'},
+		eofMoreResultsPacket,
+		[]byte{0x01}, columnDefinition("cat"), eofPacket,
+		[]byte{0x03, 'T', 'o', 'm'},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("CALL dogs_and_cats()")
+	assert.NoError(t, err)
+
+	assert.True(t, rows.NextResultSet())
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.String(), "Tom")
+	assert.False(t, rows.Next())
+	assert.False(t, rows.NextResultSet())
+	assert.NoError(t, conn.acquire())
+}
+
+func TestRowsNextResultSetSingleResultSet(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01}, columnDefinition("dog"), eofPacket,
+		[]byte{0x03, 'M', 'a', 'x'},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT dog FROM dogs")
+	assert.NoError(t, err)
+	for rows.Next() {
+	}
+	assert.False(t, rows.NextResultSet())
+	assert.NoError(t, rows.LastError())
+	assert.NoError(t, conn.acquire())
+}
+
+func TestRowsNextResultSetReturnsError(t *testing.T) {
+	errPacket := append([]byte{mysqlproto.ERR_PACKET, 0x26, 0x05, '#'}, "42S02Table 'test.cats' doesn't exist"...)
+	conn := newPacketConn(
+		[]byte{0x01}, columnDefinition("dog"), eofPacket,
+		eofMoreResultsPacket,
+		errPacket,
+	)
+
+	rows, err := conn.Query("CALL dogs_and_cats()")
+	assert.NoError(t, err)
+	assert.False(t, rows.Next())
+	assert.False(t, rows.NextResultSet())
+	_, ok := rows.LastError().(mysqlproto.ERRPacket)
+	assert.True(t, ok)
+	assert.True(t, conn.valid)
+	assert.NoError(t, conn.acquire())
+}
+
+func TestRowsDiscardSkipsResultSets(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01}, columnDefinition("dog"), eofPacket,
+		[]byte{0x03, 'M', 'a', 'x'},
+		eofMoreResultsPacket,
+		[]byte{0x01}, columnDefinition("cat"), eofPacket,
+		[]byte{0x03, 'T', 'o', 'm'},
+		eofMoreResultsPacket,
+		[]byte{mysqlproto.OK_PACKET, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00},
+	)
+
+	rows, err := conn.Query("CALL dogs_and_cats()")
+	assert.NoError(t, err)
+	rows.discard()
+	assert.NoError(t, rows.LastError())
+	assert.NoError(t, conn.acquire())
+	_, err = conn.conn.NextPacket()
+	assert.Equal(t, err, io.EOF) // stream is read completely
+}
+
+func TestSQLRowsNextResultSet(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01}, columnDefinition("dog"), eofPacket,
+		[]byte{0x03, 'M', 'a', 'x'},
+		eofMoreResultsPacket,
+		[]byte{0x01}, columnDefinition("cat"), eofPacket,
+		[]byte{0x03, 'T', 'o', 'm'},
+		eofPacket,
+	)
+	sc := &sqlConn{conn: conn}
+
+	rows, err := sc.QueryContext(context.Background(), "CALL dogs_and_cats()", nil)
+	assert.NoError(t, err)
+	sets := rows.(driver.RowsNextResultSet)
+
+	dest := make([]driver.Value, 1)
+	assert.NoError(t, rows.Next(dest))
+	assert.Equal(t, rows.Next(dest), io.EOF)
+	assert.True(t, sets.HasNextResultSet())
+	assert.NoError(t, sets.NextResultSet())
+	assert.Equal(t, rows.Columns(), []string{"cat"})
+	assert.NoError(t, rows.Next(dest))
+	assert.Equal(t, dest, []driver.Value{[]byte("Tom")})
+	assert.Equal(t, rows.Next(dest), io.EOF)
+	assert.False(t, sets.HasNextResultSet())
+	assert.Equal(t, sets.NextResultSet(), io.EOF)
+	assert.NoError(t, rows.Close())
+	assert.NoError(t, conn.acquire())
+}
+
+func TestQueryCallWithMultipleResultSets(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec("DROP PROCEDURE IF EXISTS one_and_two")
+		assert.NoError(t, err)
+		_, err = conn.Exec("CREATE PROCEDURE one_and_two() BEGIN SELECT 1 AS one; SELECT 'two' AS two; END")
+		assert.NoError(t, err)
+
+		rows, err := conn.Query("CALL one_and_two()")
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.Int(), 1)
+		assert.False(t, rows.Next())
+
+		assert.True(t, rows.NextResultSet())
+		assert.Equal(t, rows.ColumnNames(), []string{"two"})
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.String(), "two")
+		assert.False(t, rows.Next())
+		assert.False(t, rows.NextResultSet())
+		assert.NoError(t, rows.LastError())
+
+		// connection is released after the last result
+		_, err = conn.Exec("DROP PROCEDURE one_and_two")
+		assert.NoError(t, err)
+	})
+}
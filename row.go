@@ -1,7 +1,9 @@
 package mysqldriver
 
 import (
+	"bytes"
 	"strconv"
+	"time"
 )
 
 // Row reads the entire row.
@@ -21,8 +23,10 @@ import (
 //		row := rows.Row() // reads name, age and return the full row
 //		fmt.Println(row.Int("id"), row.String("name"), row.Int("age"))
 //  }
+// Row shares the buffers with Rows, so it can't be used
+// after the next call of Next.
 func (r *Rows) Row() Row {
-	for range r.resultSet.Columns[r.readColumns:] {
+	for range r.definitions[r.readColumns:] {
 		r.NullBytes()
 	}
 
@@ -45,6 +49,7 @@ type Row struct {
 // IMPORTANT. This function panics if it can't find the column by the name.
 //
 // All other type-specific functions are based on this one.
+// The value is valid only until the next call of Rows.Next.
 func (r Row) NullBytes(col string) ([]byte, bool) {
 	column, ok := r.columns[col]
 	if !ok {
@@ -52,7 +57,7 @@ func (r Row) NullBytes(col string) ([]byte, bool) {
 		if len(r.columns) > 0 {
 			msg += ` Available columns are: `
 			var i int
-			for _, c := range r.rows.resultSet.Columns {
+			for _, c := range r.rows.definitions {
 				if i > 0 {
 					msg += ", "
 				}
@@ -68,11 +73,24 @@ func (r Row) NullBytes(col string) ([]byte, bool) {
 
 // Bytes returns value as slice of bytes.
 // NULL value is represented as empty slice.
+// The slice references the buffer of the row, use BytesCopy
+// to keep the value after the next call of Rows.Next.
 func (r Row) Bytes(col string) []byte {
 	value, _ := r.NullBytes(col)
 	return value
 }
 
+// BytesCopy returns a copy of the value which stays valid
+// after Rows.Next moves the cursor to the next row.
+// NULL value is represented as nil.
+func (r Row) BytesCopy(col string) []byte {
+	value, null := r.NullBytes(col)
+	if null {
+		return nil
+	}
+	return append([]byte{}, value...)
+}
+
 // NullString returns string as a value and
 // NULL indicator. When value is NULL, second parameter is true.
 func (r Row) NullString(col string) (string, bool) {
@@ -82,11 +100,23 @@ func (r Row) NullString(col string) (string, bool) {
 
 // String returns value as a string.
 // NULL value is represented as an empty string.
+// Bytes sent by the server aren't converted (see func (Rows) String).
 func (r Row) String(col string) string {
 	value, _ := r.NullString(col)
 	return value
 }
 
+// SafeString returns value as a string. It returns ErrContainsNul
+// when value contains NUL byte (see func (Rows) SafeString).
+// NULL value is represented as an empty string.
+func (r Row) SafeString(col string) (string, error) {
+	value, _ := r.NullBytes(col)
+	if bytes.IndexByte(value, 0) >= 0 {
+		return "", ErrContainsNul
+	}
+	return string(value), nil
+}
+
 // NullInt returns value as an int and NULL indicator.
 // When value is NULL, second parameter is true.
 // NullInt method uses strconv.Atoi to convert string into int.
@@ -96,8 +126,9 @@ func (r Row) NullInt(col string) (int, bool) {
 	if null {
 		return 0, true
 	}
+	value = r.rows.conn.stripGrouping(value)
 
-	num, err := atoi(value)
+	num, err := atoi(trimZeroFraction(value))
 	if err != nil {
 		r.rows.errParse = err
 	}
@@ -119,12 +150,13 @@ func (r Row) Int(col string) int {
 // NullInt8 method uses strconv.ParseInt to convert string into int8.
 // (see https://golang.org/pkg/strconv/#ParseInt)
 func (r Row) NullInt8(col string) (int8, bool) {
-	str, null := r.NullString(col)
+	str, null := r.NullBytes(col)
 	if null {
 		return 0, true
 	}
+	str = r.rows.conn.stripGrouping(str)
 
-	num, err := strconv.ParseInt(str, 10, 8)
+	num, err := parseInt(trimZeroFraction(str), 8)
 	if err != nil {
 		r.rows.errParse = err
 	}
@@ -146,12 +178,13 @@ func (r Row) Int8(col string) int8 {
 // NullInt16 method uses strconv.ParseInt to convert string into int16.
 // (see https://golang.org/pkg/strconv/#ParseInt)
 func (r Row) NullInt16(col string) (int16, bool) {
-	str, null := r.NullString(col)
+	str, null := r.NullBytes(col)
 	if null {
 		return 0, true
 	}
+	str = r.rows.conn.stripGrouping(str)
 
-	num, err := strconv.ParseInt(str, 10, 16)
+	num, err := parseInt(trimZeroFraction(str), 16)
 	if err != nil {
 		r.rows.errParse = err
 	}
@@ -173,12 +206,13 @@ func (r Row) Int16(col string) int16 {
 // NullInt32 method uses strconv.ParseInt to convert string into int32.
 // (see https://golang.org/pkg/strconv/#ParseInt)
 func (r Row) NullInt32(col string) (int32, bool) {
-	str, null := r.NullString(col)
+	str, null := r.NullBytes(col)
 	if null {
 		return 0, true
 	}
+	str = r.rows.conn.stripGrouping(str)
 
-	num, err := strconv.ParseInt(str, 10, 32)
+	num, err := parseInt(trimZeroFraction(str), 32)
 	if err != nil {
 		r.rows.errParse = err
 	}
@@ -200,12 +234,13 @@ func (r Row) Int32(col string) int32 {
 // NullInt64 method uses strconv.ParseInt to convert string into int64.
 // (see https://golang.org/pkg/strconv/#ParseInt)
 func (r Row) NullInt64(col string) (int64, bool) {
-	str, null := r.NullString(col)
+	str, null := r.NullBytes(col)
 	if null {
 		return 0, true
 	}
+	str = r.rows.conn.stripGrouping(str)
 
-	num, err := strconv.ParseInt(str, 10, 64)
+	num, err := parseInt(trimZeroFraction(str), 64)
 	if err != nil {
 		r.rows.errParse = err
 	}
@@ -222,17 +257,156 @@ func (r Row) Int64(col string) int64 {
 	return num
 }
 
+// NullUint returns value as a uint and NULL indicator.
+// When value is NULL, second parameter is true.
+// NullUint method uses strconv.ParseUint to convert string into uint.
+// (see https://golang.org/pkg/strconv/#ParseUint)
+func (r Row) NullUint(col string) (uint, bool) {
+	str, null := r.NullBytes(col)
+	if null {
+		return 0, true
+	}
+	str = r.rows.conn.stripGrouping(str)
+
+	num, err := parseUint(trimZeroFraction(str), strconv.IntSize)
+	if err != nil {
+		r.rows.errParse = err
+	}
+
+	return uint(num), false
+}
+
+// Uint returns value as a uint.
+// NULL value is represented as 0.
+// Uint method uses strconv.ParseUint to convert string into uint.
+// (see https://golang.org/pkg/strconv/#ParseUint)
+func (r Row) Uint(col string) uint {
+	num, _ := r.NullUint(col)
+	return num
+}
+
+// NullUint8 returns value as a uint8 and NULL indicator.
+// When value is NULL, second parameter is true.
+// NullUint8 method uses strconv.ParseUint to convert string into uint8.
+// (see https://golang.org/pkg/strconv/#ParseUint)
+func (r Row) NullUint8(col string) (uint8, bool) {
+	str, null := r.NullBytes(col)
+	if null {
+		return 0, true
+	}
+	str = r.rows.conn.stripGrouping(str)
+
+	num, err := parseUint(trimZeroFraction(str), 8)
+	if err != nil {
+		r.rows.errParse = err
+	}
+
+	return uint8(num), false
+}
+
+// Uint8 returns value as a uint8.
+// NULL value is represented as 0.
+// Uint8 method uses strconv.ParseUint to convert string into uint8.
+// (see https://golang.org/pkg/strconv/#ParseUint)
+func (r Row) Uint8(col string) uint8 {
+	num, _ := r.NullUint8(col)
+	return num
+}
+
+// NullUint16 returns value as a uint16 and NULL indicator.
+// When value is NULL, second parameter is true.
+// NullUint16 method uses strconv.ParseUint to convert string into uint16.
+// (see https://golang.org/pkg/strconv/#ParseUint)
+func (r Row) NullUint16(col string) (uint16, bool) {
+	str, null := r.NullBytes(col)
+	if null {
+		return 0, true
+	}
+	str = r.rows.conn.stripGrouping(str)
+
+	num, err := parseUint(trimZeroFraction(str), 16)
+	if err != nil {
+		r.rows.errParse = err
+	}
+
+	return uint16(num), false
+}
+
+// Uint16 returns value as a uint16.
+// NULL value is represented as 0.
+// Uint16 method uses strconv.ParseUint to convert string into uint16.
+// (see https://golang.org/pkg/strconv/#ParseUint)
+func (r Row) Uint16(col string) uint16 {
+	num, _ := r.NullUint16(col)
+	return num
+}
+
+// NullUint32 returns value as a uint32 and NULL indicator.
+// When value is NULL, second parameter is true.
+// NullUint32 method uses strconv.ParseUint to convert string into uint32.
+// (see https://golang.org/pkg/strconv/#ParseUint)
+func (r Row) NullUint32(col string) (uint32, bool) {
+	str, null := r.NullBytes(col)
+	if null {
+		return 0, true
+	}
+	str = r.rows.conn.stripGrouping(str)
+
+	num, err := parseUint(trimZeroFraction(str), 32)
+	if err != nil {
+		r.rows.errParse = err
+	}
+
+	return uint32(num), false
+}
+
+// Uint32 returns value as a uint32.
+// NULL value is represented as 0.
+// Uint32 method uses strconv.ParseUint to convert string into uint32.
+// (see https://golang.org/pkg/strconv/#ParseUint)
+func (r Row) Uint32(col string) uint32 {
+	num, _ := r.NullUint32(col)
+	return num
+}
+
+// NullUint64 returns value as a uint64 and NULL indicator.
+// When value is NULL, second parameter is true.
+// NullUint64 method uses strconv.ParseUint to convert string into uint64.
+// (see https://golang.org/pkg/strconv/#ParseUint)
+func (r Row) NullUint64(col string) (uint64, bool) {
+	str, null := r.NullBytes(col)
+	if null {
+		return 0, true
+	}
+	str = r.rows.conn.stripGrouping(str)
+
+	num, err := parseUint(trimZeroFraction(str), 64)
+	if err != nil {
+		r.rows.errParse = err
+	}
+
+	return uint64(num), false
+}
+
+// Uint64 returns value as a uint64.
+// NULL value is represented as 0.
+// Uint64 method uses strconv.ParseUint to convert string into uint64.
+// (see https://golang.org/pkg/strconv/#ParseUint)
+func (r Row) Uint64(col string) uint64 {
+	num, _ := r.NullUint64(col)
+	return num
+}
+
 // NullFloat32 returns value as a float32 and NULL indicator.
 // When value is NULL, second parameter is true.
 // NullFloat32 method uses strconv.ParseFloat to convert string into float32.
 // (see https://golang.org/pkg/strconv/#ParseFloat)
 func (r Row) NullFloat32(col string) (float32, bool) {
-	str, null := r.NullString(col)
+	value, null := r.NullBytes(col)
 	if null {
 		return 0, true
 	}
-
-	num, err := strconv.ParseFloat(str, 32)
+	num, err := parseFloat(r.rows.conn.stripGrouping(value), 32)
 	if err != nil {
 		r.rows.errParse = err
 	}
@@ -253,13 +427,14 @@ func (r Row) Float32(col string) float32 {
 // When value is NULL, second parameter is true.
 // NullFloat64 method uses strconv.ParseFloat to convert string into float64.
 // (see https://golang.org/pkg/strconv/#ParseFloat)
+// Conversion of DECIMAL value is lossy, use Decimal or NullBigRat
+// to get the exact value.
 func (r Row) NullFloat64(col string) (float64, bool) {
-	str, null := r.NullString(col)
+	value, null := r.NullBytes(col)
 	if null {
 		return 0, true
 	}
-
-	num, err := strconv.ParseFloat(str, 64)
+	num, err := parseFloat(r.rows.conn.stripGrouping(value), 64)
 	if err != nil {
 		r.rows.errParse = err
 	}
@@ -271,6 +446,8 @@ func (r Row) NullFloat64(col string) (float64, bool) {
 // NULL value is represented as 0.0.
 // Float64 method uses strconv.ParseFloat to convert string into float64.
 // (see https://golang.org/pkg/strconv/#ParseFloat)
+// Conversion of DECIMAL value is lossy, use Decimal or NullBigRat
+// to get the exact value.
 func (r Row) Float64(col string) float64 {
 	num, _ := r.NullFloat64(col)
 	return num
@@ -301,3 +478,26 @@ func (r Row) Bool(col string) bool {
 	b, _ := r.NullBool(col)
 	return b
 }
+
+// NullTime returns value of DATE, DATETIME or TIMESTAMP column
+// as a time.Time and NULL indicator (see func (Rows) Time).
+// When value is NULL, second parameter is true.
+func (r Row) NullTime(col string) (time.Time, bool) {
+	str, null := r.NullBytes(col)
+	if null {
+		return time.Time{}, true
+	}
+
+	t, err := parseDateTime(str, r.rows.conn.location())
+	if err != nil {
+		r.rows.errParse = err
+	}
+	return t, false
+}
+
+// Time returns value of DATE, DATETIME or TIMESTAMP column as a time.Time.
+// NULL value is represented as zero time.Time.
+func (r Row) Time(col string) time.Time {
+	t, _ := r.NullTime(col)
+	return t
+}
@@ -0,0 +1,118 @@
+package mysqldriver
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ScanCountError is returned by Rows.Scan when the number of
+// destinations doesn't match the number of unread columns of the row
+type ScanCountError struct {
+	Columns int // number of unread columns
+	Dest    int // number of given destinations
+}
+
+func (e *ScanCountError) Error() string {
+	return fmt.Sprintf("mysqldriver: row has %d unread columns but %d destinations are given", e.Columns, e.Dest)
+}
+
+// Scan reads the unread columns of the current row into dest
+// in the order of the columns. Supported destinations are
+// *int, *int64, *float64, *bool, *string, *[]byte, *time.Time,
+// *interface{} (see func (Rows) Any), *sql.RawBytes and
+// *sql.NullString, *sql.NullInt64, *sql.NullInt32, *sql.NullFloat64,
+// *sql.NullBool, *sql.NullTime. NULL value makes Valid of sql.Null* types
+// false, other types get the value returned by their accessor for NULL,
+// e.g. 0 for *int. *[]byte gets a copy of the value, while *sql.RawBytes
+// references the packet and it's valid only until the next call of Next.
+//
+//	rows, _ := conn.Query("SELECT name, age, owner FROM dogs")
+//	for rows.Next() {
+//		var name string
+//		var age int
+//		var owner sql.NullString
+//		if err := rows.Scan(&name, &age, &owner); err != nil {
+//			// handle error
+//		}
+//	}
+//
+// Parse error of a value is returned right away
+// and it's also returned by LastError.
+func (r *Rows) Scan(dest ...interface{}) error {
+	if unread := len(r.definitions) - r.readColumns; unread != len(dest) {
+		return &ScanCountError{Columns: unread, Dest: len(dest)}
+	}
+
+	prev := r.errParse
+	r.errParse = nil
+	defer func() {
+		if r.errParse == nil {
+			r.errParse = prev
+		}
+	}()
+
+	for _, d := range dest {
+		if err := r.scanValue(d); err != nil {
+			return err
+		}
+		if r.errParse != nil {
+			return r.errParse
+		}
+	}
+	return nil
+}
+
+func (r *Rows) scanValue(dest interface{}) error {
+	switch d := dest.(type) {
+	case *int:
+		*d = r.Int()
+	case *int64:
+		*d = r.Int64()
+	case *float64:
+		*d = r.Float64()
+	case *bool:
+		*d = r.Bool()
+	case *string:
+		*d = r.String()
+	case *[]byte:
+		value, null := r.NullBytes()
+		if null {
+			*d = nil
+		} else {
+			*d = append([]byte(nil), value...)
+		}
+	case *time.Time:
+		*d = r.Time()
+	case *interface{}:
+		*d = r.Any()
+	case *sql.RawBytes:
+		value, null := r.NullBytes()
+		if null {
+			*d = nil
+		} else {
+			*d = value
+		}
+	case *sql.NullString:
+		value, null := r.NullString()
+		*d = sql.NullString{String: value, Valid: !null}
+	case *sql.NullInt64:
+		value, null := r.NullInt64()
+		*d = sql.NullInt64{Int64: value, Valid: !null}
+	case *sql.NullInt32:
+		value, null := r.NullInt32()
+		*d = sql.NullInt32{Int32: value, Valid: !null}
+	case *sql.NullFloat64:
+		value, null := r.NullFloat64()
+		*d = sql.NullFloat64{Float64: value, Valid: !null}
+	case *sql.NullBool:
+		value, null := r.NullBool()
+		*d = sql.NullBool{Bool: value, Valid: !null}
+	case *sql.NullTime:
+		value, null := r.NullTime()
+		*d = sql.NullTime{Time: value, Valid: !null}
+	default:
+		return fmt.Errorf("mysqldriver: unsupported Scan destination %T", dest)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package mysqldriver
+
+import (
+	"database/sql"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRowsScan(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x07},
+		columnDefinition("id"), columnDefinition("name"), columnDefinition("weight"),
+		columnDefinition("born"), columnDefinition("owner"), columnDefinition("photo"),
+		columnDefinition("vaccinated"),
+		eofPacket,
+		[]byte{
+			0x01, '7',
+			0x03, 'M', 'a', 'x',
+			0x03, '2', '.', '5',
+			0x0a, '2', '0', '2', '0', '-', '0', '1', '-', '0', '2',
+			0xfb,
+			0x02, 0x01, 0x02,
+			0x01, '1',
+		},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT * FROM dogs")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+
+	var id int64
+	var name sql.RawBytes
+	var weight float64
+	var born time.Time
+	var owner sql.NullString
+	var photo []byte
+	var vaccinated bool
+	err = rows.Scan(&id, &name, &weight, &born, &owner, &photo, &vaccinated)
+	assert.NoError(t, err)
+	assert.Equal(t, id, int64(7))
+	assert.Equal(t, string(name), "Max")
+	assert.Equal(t, weight, 2.5)
+	assert.Equal(t, born, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, owner, sql.NullString{})
+	assert.Equal(t, photo, []byte{0x01, 0x02})
+	assert.True(t, vaccinated)
+
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.LastError())
+}
+
+func TestRowsScanReturnsErrorWhenNumberOfDestinationsMismatch(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x02}, columnDefinition("id"), columnDefinition("name"), eofPacket,
+		[]byte{0x01, '7', 0x03, 'M', 'a', 'x'},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT id, name FROM dogs")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+
+	var id int
+	assert.Equal(t, rows.Scan(&id), &ScanCountError{Columns: 2, Dest: 1})
+
+	// the rest of the columns
+	id = rows.Int()
+	var name string
+	assert.NoError(t, rows.Scan(&name))
+	assert.Equal(t, id, 7)
+	assert.Equal(t, name, "Max")
+}
+
+func TestRowsScanReturnsParseError(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01}, columnDefinition("age"), eofPacket,
+		[]byte{0x03, 'a', 'b', 'c'},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT age FROM dogs")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+
+	var age int
+	err = rows.Scan(&age)
+	_, ok := err.(*strconv.NumError)
+	assert.True(t, ok)
+	assert.False(t, rows.Next())
+	assert.Equal(t, rows.LastError(), err)
+}
+
+func TestRowsScanUnsupportedDestination(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01}, columnDefinition("age"), eofPacket,
+		[]byte{0x01, '3'},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT age FROM dogs")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+
+	var age uint8
+	assert.Equal(t, rows.Scan(&age).Error(), "mysqldriver: unsupported Scan destination *uint8")
+}
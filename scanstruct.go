@@ -0,0 +1,209 @@
+package mysqldriver
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var errScanStructDest = errors.New("mysqldriver: ScanStruct destination must be a pointer to struct")
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// ScanStruct reads the unread columns of the current row into
+// the fields of the struct pointed by dest. Column is matched with
+// the field by `db:"name"` tag or by the field name ignoring case.
+// Fields of embedded structs are matched as fields of dest.
+// Unexported fields and fields tagged with `db:"-"` are skipped,
+// columns without matching field are ignored.
+//
+// Values are converted by Null* accessors according to the field type:
+// string, bool, integer and float types, []byte, time.Time and all
+// destinations supported by Scan. Pointer fields are set to nil
+// when value is NULL, other fields get the value returned by
+// their accessor for NULL, e.g. 0 for int.
+//
+//	type Dog struct {
+//		ID    int64   `db:"id"`
+//		Name  string  `db:"name"`
+//		Owner *string `db:"owner_name"`
+//	}
+//
+//	rows, _ := conn.Query("SELECT id, name, owner_name FROM dogs")
+//	for rows.Next() {
+//		var dog Dog
+//		if err := rows.ScanStruct(&dog); err != nil {
+//			// handle error
+//		}
+//	}
+//
+// Parse error of a value is returned right away
+// and it's also returned by LastError.
+func (r *Rows) ScanStruct(dest interface{}) error {
+	value := reflect.ValueOf(dest)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return errScanStructDest
+	}
+	value = value.Elem()
+	fields := structFields(value.Type())
+
+	prev := r.errParse
+	r.errParse = nil
+	defer func() {
+		if r.errParse == nil {
+			r.errParse = prev
+		}
+	}()
+
+	for r.readColumns < len(r.definitions) {
+		index, ok := fields[strings.ToLower(r.definitions[r.readColumns].Name)]
+		if !ok {
+			r.NullBytes() // skip the column
+			continue
+		}
+
+		if _, err := r.scanField(value.FieldByIndex(index)); err != nil {
+			return err
+		}
+		if r.errParse != nil {
+			return r.errParse
+		}
+	}
+	return nil
+}
+
+// structFields returns indexes of the fields
+// by lower-cased names of the columns
+func structFields(typ reflect.Type) map[string][]int {
+	fields := make(map[string][]int, typ.NumField())
+	addStructFields(fields, typ, nil)
+	return fields
+}
+
+func addStructFields(fields map[string][]int, typ reflect.Type, parent []int) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("db")
+		if tag == "-" {
+			continue
+		}
+
+		index := append(append([]int(nil), parent...), i)
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			addStructFields(fields, field.Type, index)
+			continue
+		}
+		if field.PkgPath != "" { // unexported
+			continue
+		}
+
+		name := tag
+		if name == "" {
+			name = field.Name
+		}
+		name = strings.ToLower(name)
+		// fields of dest take precedence over the fields of embedded structs
+		if existing, ok := fields[name]; !ok || len(existing) > len(index) {
+			fields[name] = index
+		}
+	}
+}
+
+// scanField reads the next value into the field
+// and reports whether the value is NULL
+func (r *Rows) scanField(field reflect.Value) (bool, error) {
+	switch field.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(field.Type().Elem())
+		null, err := r.scanField(elem.Elem())
+		if err != nil {
+			return false, err
+		}
+		if null {
+			field.Set(reflect.Zero(field.Type()))
+		} else {
+			field.Set(elem)
+		}
+		return null, nil
+	case reflect.String:
+		value, null := r.NullString()
+		field.SetString(value)
+		return null, nil
+	case reflect.Bool:
+		value, null := r.NullBool()
+		field.SetBool(value)
+		return null, nil
+	case reflect.Int:
+		value, null := r.NullInt()
+		field.SetInt(int64(value))
+		return null, nil
+	case reflect.Int8:
+		value, null := r.NullInt8()
+		field.SetInt(int64(value))
+		return null, nil
+	case reflect.Int16:
+		value, null := r.NullInt16()
+		field.SetInt(int64(value))
+		return null, nil
+	case reflect.Int32:
+		value, null := r.NullInt32()
+		field.SetInt(int64(value))
+		return null, nil
+	case reflect.Int64:
+		value, null := r.NullInt64()
+		field.SetInt(value)
+		return null, nil
+	case reflect.Uint:
+		value, null := r.NullUint()
+		field.SetUint(uint64(value))
+		return null, nil
+	case reflect.Uint8:
+		value, null := r.NullUint8()
+		field.SetUint(uint64(value))
+		return null, nil
+	case reflect.Uint16:
+		value, null := r.NullUint16()
+		field.SetUint(uint64(value))
+		return null, nil
+	case reflect.Uint32:
+		value, null := r.NullUint32()
+		field.SetUint(uint64(value))
+		return null, nil
+	case reflect.Uint64:
+		value, null := r.NullUint64()
+		field.SetUint(value)
+		return null, nil
+	case reflect.Float32:
+		value, null := r.NullFloat32()
+		field.SetFloat(float64(value))
+		return null, nil
+	case reflect.Float64:
+		value, null := r.NullFloat64()
+		field.SetFloat(value)
+		return null, nil
+	case reflect.Slice:
+		if field.Type().Elem().Kind() == reflect.Uint8 {
+			value, null := r.NullBytes()
+			if null {
+				field.SetBytes(nil)
+			} else {
+				field.SetBytes(append([]byte(nil), value...))
+			}
+			return null, nil
+		}
+	case reflect.Struct:
+		if field.Type() == timeType {
+			value, null := r.NullTime()
+			field.Set(reflect.ValueOf(value))
+			return null, nil
+		}
+	}
+
+	// sql.Null* types and other destinations of Scan
+	if err := r.scanValue(field.Addr().Interface()); err != nil {
+		return false, fmt.Errorf("mysqldriver: unsupported ScanStruct field type %s", field.Type())
+	}
+	return false, nil
+}
@@ -0,0 +1,110 @@
+package mysqldriver
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type scanStructModel struct {
+	Created time.Time
+}
+
+type scanStructDog struct {
+	scanStructModel
+	ID       int64 `db:"id"`
+	Name     string
+	Owner    *string        `db:"owner_name"`
+	Age      *uint8         `db:"age"`
+	Nickname sql.NullString `db:"nickname"`
+	Photo    []byte         `db:"photo"`
+	Ignored  string         `db:"-"`
+	secret   string
+}
+
+func TestRowsScanStruct(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x09},
+		columnDefinition("id"), columnDefinition("NAME"), columnDefinition("owner_name"),
+		columnDefinition("age"), columnDefinition("nickname"), columnDefinition("photo"),
+		columnDefinition("created"), columnDefinition("secret"), columnDefinition("unknown"),
+		eofPacket,
+		[]byte{
+			0x01, '7',
+			0x03, 'M', 'a', 'x',
+			0xfb,
+			0x01, '3',
+			0x04, 'M', 'a', 'x', 'y',
+			0x02, 0x01, 0x02,
+			0x0a, '2', '0', '2', '0', '-', '0', '1', '-', '0', '2',
+			0x01, 's',
+			0x01, 'u',
+		},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT * FROM dogs")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+
+	dog := scanStructDog{Ignored: "kept", Owner: new(string)}
+	assert.NoError(t, rows.ScanStruct(&dog))
+
+	age := uint8(3)
+	assert.Equal(t, dog, scanStructDog{
+		scanStructModel: scanStructModel{Created: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+		ID:              7,
+		Name:            "Max",
+		Owner:           nil,
+		Age:             &age,
+		Nickname:        sql.NullString{String: "Maxy", Valid: true},
+		Photo:           []byte{0x01, 0x02},
+		Ignored:         "kept",
+	})
+
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.LastError())
+}
+
+func TestRowsScanStructReturnsParseError(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01}, columnDefinition("id"), eofPacket,
+		[]byte{0x03, 'a', 'b', 'c'},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT id FROM dogs")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+
+	var dog scanStructDog
+	err = rows.ScanStruct(&dog)
+	assert.NotNil(t, err)
+	assert.False(t, rows.Next())
+	assert.Equal(t, rows.LastError(), err)
+}
+
+func TestRowsScanStructRequiresPointerToStruct(t *testing.T) {
+	rows := &Rows{}
+	var dog scanStructDog
+	assert.Equal(t, rows.ScanStruct(dog), errScanStructDest)
+	assert.Equal(t, rows.ScanStruct(new(int)), errScanStructDest)
+	assert.Equal(t, rows.ScanStruct((*scanStructDog)(nil)), errScanStructDest)
+}
+
+func TestRowsScanStructUnsupportedFieldType(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01}, columnDefinition("tags"), eofPacket,
+		[]byte{0x01, 'a'},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT tags FROM dogs")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+
+	var dest struct{ Tags []string }
+	assert.Equal(t, rows.ScanStruct(&dest).Error(), "mysqldriver: unsupported ScanStruct field type []string")
+}
@@ -0,0 +1,83 @@
+package mysqldriver
+
+// TableSchema describes columns of the table
+type TableSchema struct {
+	Name    string
+	Columns []TableColumn
+}
+
+// TableColumn describes a single column of the table
+// as it's returned by SHOW COLUMNS statement
+type TableColumn struct {
+	Name       string // column name
+	Type       string // full column type, e.g. "int(10) unsigned"
+	Nullable   bool   // column can contain NULL values
+	Key        string // "PRI", "UNI", "MUL" or empty string
+	Default    string // default value of the column
+	HasDefault bool   // false when default value is NULL
+	Extra      string // e.g. "auto_increment"
+}
+
+// Column returns column by its name
+func (s *TableSchema) Column(name string) (TableColumn, bool) {
+	for _, column := range s.Columns {
+		if column.Name == name {
+			return column, true
+		}
+	}
+	return TableColumn{}, false
+}
+
+// TableSchema returns definitions of the table columns using
+// SHOW COLUMNS statement. The name can be qualified with
+// the database name, e.g. "test.people".
+//
+// Schema is cached by the connection, so the following calls
+// don't query the server. Use InvalidateTableSchema after
+// the table has been altered.
+func (c *Conn) TableSchema(name string) (*TableSchema, error) {
+	if schema, ok := c.schemas[name]; ok {
+		return schema, nil
+	}
+
+	rows, err := c.Query("SHOW COLUMNS FROM " + quoteIdentifier(name))
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &TableSchema{Name: name}
+	for rows.Next() {
+		row := rows.Row()
+		def, null := row.NullString("Default")
+		schema.Columns = append(schema.Columns, TableColumn{
+			Name:       row.String("Field"),
+			Type:       row.String("Type"),
+			Nullable:   row.String("Null") == "YES",
+			Key:        row.String("Key"),
+			Default:    def,
+			HasDefault: !null,
+			Extra:      row.String("Extra"),
+		})
+	}
+	if err := rows.LastError(); err != nil {
+		return nil, err
+	}
+
+	if c.schemas == nil {
+		c.schemas = make(map[string]*TableSchema)
+	}
+	c.schemas[name] = schema
+	return schema, nil
+}
+
+// InvalidateTableSchema removes schema of the table from the cache.
+// All cached schemas are removed when no names are given.
+func (c *Conn) InvalidateTableSchema(names ...string) {
+	if len(names) == 0 {
+		c.schemas = nil
+		return
+	}
+	for _, name := range names {
+		delete(c.schemas, name)
+	}
+}
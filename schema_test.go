@@ -0,0 +1,66 @@
+package mysqldriver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnTableSchema(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		schema, err := conn.TableSchema("people")
+		assert.NoError(t, err)
+		assert.Equal(t, schema.Name, "people")
+		assert.Len(t, schema.Columns, 12)
+
+		id, ok := schema.Column("id")
+		assert.True(t, ok)
+		assert.Equal(t, id.Type, "int")
+		assert.False(t, id.Nullable)
+		assert.Equal(t, id.Key, "PRI")
+		assert.False(t, id.HasDefault)
+		assert.Equal(t, id.Extra, "auto_increment")
+
+		name, ok := schema.Column("firstname")
+		assert.True(t, ok)
+		assert.Equal(t, name.Type, "varchar(255)")
+		assert.True(t, name.Nullable)
+		assert.Equal(t, name.Key, "")
+
+		_, ok = schema.Column("unknown")
+		assert.False(t, ok)
+	})
+}
+
+func TestConnTableSchemaIsCached(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		schema, err := conn.TableSchema("test.categories")
+		assert.NoError(t, err)
+		assert.Len(t, schema.Columns, 2)
+
+		_, err = conn.Exec("ALTER TABLE categories ADD COLUMN position int NOT NULL DEFAULT 0")
+		assert.NoError(t, err)
+
+		cached, err := conn.TableSchema("test.categories")
+		assert.NoError(t, err)
+		assert.True(t, cached == schema)
+
+		conn.InvalidateTableSchema("test.categories")
+		schema, err = conn.TableSchema("test.categories")
+		assert.NoError(t, err)
+		assert.Len(t, schema.Columns, 3)
+
+		position, ok := schema.Column("position")
+		assert.True(t, ok)
+		assert.True(t, position.HasDefault)
+		assert.Equal(t, position.Default, "0")
+	})
+}
+
+func TestConnTableSchemaOfUnknownTable(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.TableSchema("unknown_table")
+		assert.NotNil(t, err)
+		assert.Len(t, conn.schemas, 0)
+	})
+}
@@ -0,0 +1,194 @@
+package mysqldriver
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/pubnative/mysqlproto-go"
+)
+
+var errUnterminatedScript = errors.New("mysqldriver: SQL script contains unterminated string or comment")
+
+// StatementError is returned by ExecFile when one of
+// the statements of the script failed
+type StatementError struct {
+	Index int    // index of the failed statement starting from 0
+	SQL   string // statement which failed
+	Err   error  // original error returned by Exec
+}
+
+func (e *StatementError) Error() string {
+	return "mysqldriver: statement #" + strconv.Itoa(e.Index) + " failed: " + e.Err.Error()
+}
+
+// ExecFile reads SQL script, splits it into statements and executes
+// them one by one using Exec. Statements are separated by ";"
+// unless the delimiter is changed with DELIMITER command
+// the same way as mysql command-line client does it:
+//
+//	DELIMITER $$
+//	CREATE PROCEDURE hello() BEGIN SELECT "hello"; END $$
+//	DELIMITER ;
+//
+// Delimiters inside of string literals, quoted identifiers
+// and comments are ignored. Statements consisting only
+// of comments aren't sent to the server.
+//
+// Execution stops on the first failed statement. In this case
+// results of the already executed statements are returned
+// together with *StatementError.
+func (c *Conn) ExecFile(r io.Reader) ([]mysqlproto.OKPacket, error) {
+	script, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	statements, err := splitStatements(script)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]mysqlproto.OKPacket, 0, len(statements))
+	for i, sql := range statements {
+		pkt, err := c.Exec(sql)
+		if err != nil {
+			return results, &StatementError{Index: i, SQL: sql, Err: err}
+		}
+		results = append(results, pkt)
+	}
+
+	return results, nil
+}
+
+func splitStatements(script []byte) ([]string, error) {
+	var statements []string
+	delimiter := []byte(";")
+	start := 0
+	blank := true // only whitespace and comments since start
+
+	add := func(stmt []byte) {
+		if blank {
+			return
+		}
+		if stmt := strings.TrimSpace(string(stmt)); stmt != "" {
+			statements = append(statements, stmt)
+		}
+	}
+
+	for i := 0; i < len(script); {
+		if blank {
+			if d, next, ok := parseDelimiterCommand(script, i); ok {
+				delimiter = d
+				i = next
+				start = i
+				continue
+			}
+		}
+
+		ch := script[i]
+		switch {
+		case ch == '\'' || ch == '"' || ch == '`':
+			end := skipQuoted(script, i)
+			if end < 0 {
+				return nil, errUnterminatedScript
+			}
+			i = end
+			blank = false
+		case ch == '#' || isLineComment(script[i:]):
+			end := bytes.IndexByte(script[i:], '\n')
+			if end < 0 {
+				end = len(script) - i
+			}
+			i += end
+		case bytes.HasPrefix(script[i:], []byte("/*")):
+			end := bytes.Index(script[i+2:], []byte("*/"))
+			if end < 0 {
+				return nil, errUnterminatedScript
+			}
+			// /*! ... */ is executed by the server and /*+ ... */ contains
+			// optimizer hints, so they aren't just comments
+			if bytes.HasPrefix(script[i:], []byte("/*!")) || bytes.HasPrefix(script[i:], []byte("/*+")) {
+				blank = false
+			}
+			i += end + 4
+		case bytes.HasPrefix(script[i:], delimiter):
+			add(script[start:i])
+			i += len(delimiter)
+			start = i
+			blank = true
+		default:
+			if !isSpace(ch) {
+				blank = false
+			}
+			i++
+		}
+	}
+	add(script[start:])
+
+	return statements, nil
+}
+
+// parseDelimiterCommand parses "DELIMITER xx" command
+// at the given position and returns new delimiter and
+// position of the next line
+func parseDelimiterCommand(script []byte, pos int) ([]byte, int, bool) {
+	const cmd = "delimiter"
+	if len(script)-pos <= len(cmd) ||
+		!strings.EqualFold(string(script[pos:pos+len(cmd)]), cmd) ||
+		(script[pos+len(cmd)] != ' ' && script[pos+len(cmd)] != '\t') {
+		return nil, 0, false
+	}
+
+	line := script[pos+len(cmd):]
+	next := len(script)
+	if end := bytes.IndexByte(line, '\n'); end >= 0 {
+		line = line[:end]
+		next = pos + len(cmd) + end + 1
+	}
+
+	delimiter := bytes.TrimSpace(line)
+	if len(delimiter) == 0 {
+		return nil, 0, false
+	}
+	return delimiter, next, true
+}
+
+// skipQuoted returns position right after the closing quote
+// or -1 when the string isn't terminated
+func skipQuoted(script []byte, pos int) int {
+	quote := script[pos]
+	for i := pos + 1; i < len(script); i++ {
+		switch script[i] {
+		case '\\':
+			if quote != '`' {
+				i++
+			}
+		case quote:
+			if i+1 < len(script) && script[i+1] == quote {
+				i++ // doubled quote is an escaped quote
+				continue
+			}
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// isLineComment reports whether data starts with "--" comment.
+// The second dash must be followed by whitespace, control
+// character or the end of the script, otherwise it's e.g.
+// the subtraction of the negative number "1--1".
+func isLineComment(data []byte) bool {
+	if !bytes.HasPrefix(data, []byte("--")) {
+		return false
+	}
+	return len(data) == 2 || data[2] <= ' '
+}
+
+func isSpace(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r' || ch == '\v' || ch == '\f'
+}
@@ -0,0 +1,132 @@
+package mysqldriver
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitStatementsSimple(t *testing.T) {
+	stmts, err := splitStatements([]byte("CREATE TABLE a (id int);\nINSERT INTO a VALUES (1) ;\n\n;"))
+	assert.NoError(t, err)
+	assert.Equal(t, stmts, []string{"CREATE TABLE a (id int)", "INSERT INTO a VALUES (1)"})
+}
+
+func TestSplitStatementsWithoutTrailingDelimiter(t *testing.T) {
+	stmts, err := splitStatements([]byte("SELECT 1; SELECT 2"))
+	assert.NoError(t, err)
+	assert.Equal(t, stmts, []string{"SELECT 1", "SELECT 2"})
+}
+
+func TestSplitStatementsIgnoresDelimiterInStrings(t *testing.T) {
+	script := `INSERT INTO a VALUES ('a;b', "c;d", 'it''s;', "say \";\"");
+		SELECT ` + "`weird;name`" + ` FROM a;`
+	stmts, err := splitStatements([]byte(script))
+	assert.NoError(t, err)
+	assert.Equal(t, stmts, []string{
+		`INSERT INTO a VALUES ('a;b', "c;d", 'it''s;', "say \";\"")`,
+		"SELECT `weird;name` FROM a",
+	})
+}
+
+func TestSplitStatementsIgnoresDelimiterInComments(t *testing.T) {
+	script := "-- first; comment\nSELECT 1; # second; comment\n/* third;\n comment */ SELECT 2;"
+	stmts, err := splitStatements([]byte(script))
+	assert.NoError(t, err)
+	assert.Equal(t, stmts, []string{
+		"-- first; comment\nSELECT 1",
+		"# second; comment\n/* third;\n comment */ SELECT 2",
+	})
+}
+
+func TestSplitStatementsDelimiterCommand(t *testing.T) {
+	script := `DROP PROCEDURE IF EXISTS hello;
+DELIMITER $$
+CREATE PROCEDURE hello()
+BEGIN
+	SELECT "hello;";
+	SELECT 1;
+END $$
+delimiter ;
+CALL hello();`
+	stmts, err := splitStatements([]byte(script))
+	assert.NoError(t, err)
+	assert.Equal(t, stmts, []string{
+		"DROP PROCEDURE IF EXISTS hello",
+		"CREATE PROCEDURE hello()\nBEGIN\n\tSELECT \"hello;\";\n\tSELECT 1;\nEND",
+		"CALL hello()",
+	})
+}
+
+func TestSplitStatementsDelimiterCommandAfterComments(t *testing.T) {
+	script := `-- create procedure
+# of the schema
+/* hello */
+DELIMITER $$
+CREATE PROCEDURE hello() BEGIN SELECT 1; END $$
+-- back to default
+DELIMITER ;
+CALL hello();`
+	stmts, err := splitStatements([]byte(script))
+	assert.NoError(t, err)
+	assert.Equal(t, stmts, []string{
+		"CREATE PROCEDURE hello() BEGIN SELECT 1; END",
+		"CALL hello()",
+	})
+}
+
+func TestSplitStatementsKeepsExecutableComments(t *testing.T) {
+	stmts, err := splitStatements([]byte("/*!40101 SET NAMES utf8 */;\n-- trailing comment"))
+	assert.NoError(t, err)
+	assert.Equal(t, stmts, []string{"/*!40101 SET NAMES utf8 */"})
+}
+
+func TestSplitStatementsEmptyLineComment(t *testing.T) {
+	script := "--\nSELECT 1; --\r\nSELECT 'a;b';\nSELECT 1--1; --"
+	stmts, err := splitStatements([]byte(script))
+	assert.NoError(t, err)
+	assert.Equal(t, stmts, []string{
+		"--\nSELECT 1",
+		"--\r\nSELECT 'a;b'",
+		"SELECT 1--1",
+	})
+}
+
+func TestSplitStatementsUnterminated(t *testing.T) {
+	_, err := splitStatements([]byte("SELECT 'abc; SELECT 1;"))
+	assert.Equal(t, err, errUnterminatedScript)
+	_, err = splitStatements([]byte("SELECT 1; /* comment"))
+	assert.Equal(t, err, errUnterminatedScript)
+}
+
+func TestExecFile(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		results, err := conn.ExecFile(strings.NewReader(`
+			INSERT INTO people(firstname) VALUES ("bob;");
+			INSERT INTO people(firstname) VALUES ("ben"), ("bin");
+		`))
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		assert.Equal(t, results[0].AffectedRows, uint64(1))
+		assert.Equal(t, results[1].AffectedRows, uint64(2))
+	})
+}
+
+func TestExecFileStopsOnFirstError(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		results, err := conn.ExecFile(strings.NewReader(`
+			INSERT INTO people(firstname) VALUES ("bob");
+			INSERT INTO unknown_table(firstname) VALUES ("ben");
+			INSERT INTO people(firstname) VALUES ("bin");
+		`))
+		assert.Len(t, results, 1)
+		stmtErr, ok := err.(*StatementError)
+		assert.True(t, ok)
+		assert.Equal(t, stmtErr.Index, 1)
+		assert.Equal(t, stmtErr.SQL, `INSERT INTO unknown_table(firstname) VALUES ("ben")`)
+		_, ok = stmtErr.Err.(mysqlproto.ERRPacket)
+		assert.True(t, ok)
+	})
+}
@@ -0,0 +1,94 @@
+package mysqldriver
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSequenceMismatch is returned when Options.StrictSequence is set
+// and the server sends packet with unexpected sequence ID. Returned
+// error is *SequenceError, compare it using errors.Is.
+// Connection can't be used after that.
+var ErrSequenceMismatch = errors.New("mysqldriver: packet sequence ID mismatch")
+
+// SequenceError contains sequence IDs of the mismatched packet
+type SequenceError struct {
+	Expected byte // sequence ID following the previous packet
+	Received byte // sequence ID of the packet sent by the server
+}
+
+func (e *SequenceError) Error() string {
+	return fmt.Sprintf("%s: expected %d, received %d", ErrSequenceMismatch, e.Expected, e.Received)
+}
+
+func (e *SequenceError) Is(target error) bool {
+	return target == ErrSequenceMismatch
+}
+
+// packetScanner finds packet headers in the stream
+// which is read or written by chunks of any size
+type packetScanner struct {
+	header    [4]byte
+	headerLen int
+	remaining int // length of the current payload which isn't scanned yet
+}
+
+// next skips data up to the end of the next packet header.
+// It returns sequence ID of the packet and the rest of data,
+// ok is false when data ends before the header is completed.
+func (s *packetScanner) next(data []byte) (seq byte, rest []byte, ok bool) {
+	if s.remaining > 0 {
+		n := s.remaining
+		if n > len(data) {
+			n = len(data)
+		}
+		s.remaining -= n
+		data = data[n:]
+	}
+
+	n := copy(s.header[s.headerLen:], data)
+	s.headerLen += n
+	data = data[n:]
+	if s.headerLen < len(s.header) {
+		return 0, nil, false
+	}
+
+	s.headerLen = 0
+	s.remaining = packetLength(s.header[:])
+	return s.header[3], data, true
+}
+
+// checkSequence verifies sequence IDs of the packets read from the server.
+// Every packet must follow the previous one, the sequence is reset
+// by the packets written to the server.
+func (c *netConn) checkSequence(data []byte) error {
+	for len(data) > 0 {
+		seq, rest, ok := c.reads.next(data)
+		if !ok {
+			return nil
+		}
+		if seq != c.nextSeq {
+			// every response of the pipeline starts right after
+			// the request with sequence ID 0
+			if c.pipelined == 0 || seq != 1 {
+				return &SequenceError{Expected: c.nextSeq, Received: seq}
+			}
+			c.pipelined--
+		}
+		c.nextSeq = seq + 1
+		data = rest
+	}
+	return nil
+}
+
+// trackSequence remembers sequence ID of the packets written to the server
+func (c *netConn) trackSequence(data []byte) {
+	for len(data) > 0 {
+		seq, rest, ok := c.writes.next(data)
+		if !ok {
+			return
+		}
+		c.nextSeq = seq + 1
+		data = rest
+	}
+}
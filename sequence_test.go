@@ -0,0 +1,88 @@
+package mysqldriver
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// newStrictConn returns connection which verifies sequence IDs
+// of the given packets. Sequence IDs follow a query.
+func newStrictConn(seqs []byte, payloads ...[]byte) *Conn {
+	data := framePackets(seqs, payloads...)
+	conn := &netConn{Conn: &readRecorder{data: bytes.NewReader(data)}, strictSequence: true, nextSeq: 1}
+	stream := mysqlproto.NewStream(conn, 0)
+	return &Conn{
+		conn:    mysqlproto.Conn{Stream: stream, CapabilityFlags: capabilityFlags},
+		netConn: conn,
+		valid:   true,
+	}
+}
+
+func TestPacketScanner(t *testing.T) {
+	data := []byte{
+		0x02, 0x00, 0x00, 0x05, 0x0a, 0x0b,
+		0x00, 0x00, 0x00, 0x06,
+		0x01, 0x00, 0x00, 0x07, 0x0c,
+	}
+
+	// scan by chunks of every size
+	for size := 1; size <= len(data); size++ {
+		var s packetScanner
+		var seqs []byte
+		for start := 0; start < len(data); start += size {
+			end := start + size
+			if end > len(data) {
+				end = len(data)
+			}
+			chunk := data[start:end]
+			for {
+				seq, rest, ok := s.next(chunk)
+				if !ok {
+					break
+				}
+				seqs = append(seqs, seq)
+				chunk = rest
+			}
+		}
+		assert.Equal(t, seqs, []byte{0x05, 0x06, 0x07}, size)
+	}
+}
+
+func TestStrictSequence(t *testing.T) {
+	conn := newStrictConn(
+		[]byte{1, 2, 3, 4, 5},
+		[]byte{0x01}, columnDefinition("id"), eofPacket, []byte{0x01, '1'}, eofPacket,
+	)
+	rows, err := conn.readResultSet()
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Int(), 1)
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.LastError())
+}
+
+func TestStrictSequenceMismatch(t *testing.T) {
+	conn := newStrictConn(
+		[]byte{1, 2, 3, 3, 5},
+		[]byte{0x01}, columnDefinition("id"), eofPacket, []byte{0x01, '1'}, eofPacket,
+	)
+	rows, err := conn.readResultSet()
+	assert.NoError(t, err)
+	assert.False(t, rows.Next())
+	assert.True(t, errors.Is(rows.LastError(), ErrSequenceMismatch))
+	assert.Equal(t, rows.LastError(), &SequenceError{Expected: 4, Received: 3})
+	assert.EqualError(t, rows.LastError(), "mysqldriver: packet sequence ID mismatch: expected 4, received 3")
+	assert.False(t, conn.valid)
+}
+
+func TestNetConnTracksWrittenSequence(t *testing.T) {
+	conn := &netConn{strictSequence: true}
+	conn.trackSequence([]byte{0x01, 0x00, 0x00, 0x00, 0x03})
+	assert.Equal(t, conn.nextSeq, byte(1))
+	conn.trackSequence([]byte{0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x03})
+	assert.Equal(t, conn.nextSeq, byte(4))
+}
@@ -0,0 +1,45 @@
+package mysqldriver
+
+import "time"
+
+// ServerTime returns the current time of the server with microsecond
+// precision, e.g. to detect clock skew between the application
+// and the server. It's read by "SELECT UTC_TIMESTAMP(6)", so the result
+// doesn't depend on the time zone of the session.
+//
+//	now, err := conn.ServerTime()
+//	skew := time.Since(now) // includes the round trip to the server
+func (c *Conn) ServerTime() (time.Time, error) {
+	rows, err := c.Query("SELECT UTC_TIMESTAMP(6)")
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var now time.Time
+	for rows.Next() {
+		now, err = parseDateTime(rows.Bytes(), time.UTC)
+	}
+	if errRead := rows.LastError(); errRead != nil {
+		return time.Time{}, errRead
+	}
+	return now, err
+}
+
+// ServerTimeZone returns the global time zone of the server
+// as it's returned by "SELECT @@global.time_zone", e.g. "SYSTEM"
+// or "+00:00". Session time zone can differ from it.
+func (c *Conn) ServerTimeZone() (string, error) {
+	rows, err := c.Query("SELECT @@global.time_zone")
+	if err != nil {
+		return "", err
+	}
+
+	var zone string
+	for rows.Next() {
+		zone = rows.String()
+	}
+	if err := rows.LastError(); err != nil {
+		return "", err
+	}
+	return zone, nil
+}
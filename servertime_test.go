@@ -0,0 +1,31 @@
+package mysqldriver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnServerTime(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec("SET time_zone = '+05:00'")
+		assert.NoError(t, err)
+
+		before := time.Now()
+		now, err := conn.ServerTime()
+		assert.NoError(t, err)
+		assert.Equal(t, now.Location(), time.UTC)
+		// server is running on the same host
+		skew := now.Sub(before)
+		assert.True(t, skew > -time.Second && skew < time.Second)
+	})
+}
+
+func TestConnServerTimeZone(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		zone, err := conn.ServerTimeZone()
+		assert.NoError(t, err)
+		assert.NotEmpty(t, zone)
+	})
+}
@@ -0,0 +1,346 @@
+package mysqldriver
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/pubnative/mysqlproto-go"
+)
+
+// ErrStmtClosed is returned when prepared statement is used after Close
+var ErrStmtClosed = errors.New("mysqldriver: statement is closed")
+
+// Stmt represents prepared statement.
+// Statement belongs to the connection which prepared it
+// and must be closed when it isn't needed anymore.
+type Stmt struct {
+	// FetchSize makes Query open read-only cursor on the server
+	// and fetch rows from it in batches of the given size,
+	// so huge result sets aren't sent to the client at once.
+	// The next batch is requested by Rows.Next when the current
+	// one is read. Zero value sends all rows without the cursor.
+	FetchSize int
+
+	conn      *Conn
+	id        uint32
+	params    []ColumnInfo
+	columns   []ColumnInfo
+	closed    bool
+	outParams []interface{} // values of OUT parameters of the last CALL
+}
+
+// Prepare creates prepared statement on the server
+// by sending COM_STMT_PREPARE command. Server parses the statement
+// and returns definitions of its parameters and result columns.
+//
+//	stmt, _ := conn.Prepare("SELECT name FROM dogs WHERE age > ?")
+//	defer stmt.Close()
+//	len(stmt.Params())           // 1
+//	stmt.ResultColumns()[0].Name // "name"
+func (c *Conn) Prepare(sql string) (*Stmt, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	if c.DefaultSchema != "" {
+		sql = qualifyTables(sql, c.DefaultSchema)
+	}
+	if _, err := c.conn.Write(commandPacket(comStmtPrepare, []byte(sql))); err != nil {
+		c.valid = false
+		return nil, err
+	}
+
+	packet, err := c.conn.NextPacket()
+	if err != nil {
+		c.valid = false
+		return nil, err
+	}
+
+	payload := packet.Payload
+	if payload[0] == mysqlproto.ERR_PACKET {
+		errPacket, err := mysqlproto.ParseERRPacket(payload, c.conn.CapabilityFlags)
+		if err != nil {
+			c.valid = false
+			return nil, err
+		}
+		return nil, errPacket
+	}
+
+	// status(1), statement_id(4), num_columns(2), num_params(2), reserved(1), warning_count(2)
+	if payload[0] != mysqlproto.OK_PACKET || len(payload) < 12 {
+		c.valid = false
+		return nil, errMalformedPacket
+	}
+
+	stmt := &Stmt{
+		conn: c,
+		id:   binary.LittleEndian.Uint32(payload[1:]),
+	}
+	numColumns := int(binary.LittleEndian.Uint16(payload[5:]))
+	numParams := int(binary.LittleEndian.Uint16(payload[7:]))
+
+	if stmt.params, err = c.readColumnDefinitions(numParams); err != nil {
+		c.valid = false
+		return nil, err
+	}
+	if stmt.columns, err = c.readColumnDefinitions(numColumns); err != nil {
+		c.valid = false
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+// Params returns definitions of the statement parameters
+// in the order of the placeholders
+func (s *Stmt) Params() []ColumnInfo {
+	return s.params
+}
+
+// ResultColumns returns definitions of the columns
+// of the result set produced by the statement.
+// It's empty for statements which don't return rows.
+func (s *Stmt) ResultColumns() []ColumnInfo {
+	return s.columns
+}
+
+// Query executes the statement with the given arguments
+// by sending COM_STMT_EXECUTE command. Arguments are sent
+// in the binary protocol, so they don't need escaping.
+// Supported arguments are the same as QueryArgs supports.
+// Values of the rows are sent in the binary protocol too,
+// but they're read by the same accessors as Query results.
+//
+//	stmt, _ := conn.Prepare("SELECT name FROM dogs WHERE age > ?")
+//	rows, _ := stmt.Query(2)
+//	for rows.Next() {
+//		rows.String() // name
+//	}
+func (s *Stmt) Query(args ...interface{}) (*Rows, error) {
+	if s.closed {
+		return nil, ErrStmtClosed
+	}
+
+	payload, err := s.executePayload(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.conn.acquire(); err != nil {
+		return nil, err
+	}
+	s.outParams = nil
+	rows, err := s.conn.sendCommand(commandPacket(comStmtExecute, payload), len(payload))
+	if err != nil {
+		return nil, err
+	}
+	rows.binary = &binaryDecoder{}
+	rows.stmt = s
+	// CALL of the procedure without result sets
+	// returns only OUT parameters
+	if !rows.eof && s.conn.status&serverPSOutParams != 0 {
+		if err := s.readOutParams(rows); err != nil {
+			rows.errRead = err
+			s.conn.release()
+			return nil, err
+		}
+		rows.eof = true
+		if rows.moreResults && !rows.NextResultSet() && rows.errRead != nil {
+			return nil, rows.errRead
+		}
+	}
+	// server sends only the column definitions when cursor is opened
+	if s.FetchSize > 0 && !rows.eof && s.conn.status&serverStatusCursorExists != 0 {
+		rows.cursor = &cursor{stmt: s.id, fetchSize: s.FetchSize, fetch: true}
+	}
+	return rows, nil
+}
+
+// Exec executes the statement which doesn't return rows,
+// e.g. INSERT or UPDATE, with the given arguments
+// (see func (Stmt) Query). Rows returned by the statement
+// are discarded.
+func (s *Stmt) Exec(args ...interface{}) (mysqlproto.OKPacket, error) {
+	rows, err := s.Query(args...)
+	if err != nil {
+		return mysqlproto.OKPacket{}, err
+	}
+	if !rows.eof {
+		rows.discard()
+		return mysqlproto.OKPacket{}, rows.LastError()
+	}
+	return s.conn.lastOK, nil
+}
+
+// executePayload encodes COM_STMT_EXECUTE payload
+// (see https://dev.mysql.com/doc/internals/en/com-stmt-execute.html)
+func (s *Stmt) executePayload(args []interface{}) ([]byte, error) {
+	if len(args) != len(s.params) {
+		return nil, &ArgCountError{Placeholders: len(s.params), Args: len(args)}
+	}
+
+	// statement_id(4), flags(1), iteration_count(4)
+	payload := make([]byte, 9, 64)
+	binary.LittleEndian.PutUint32(payload, s.id)
+	payload[4] = 0x00 // CURSOR_TYPE_NO_CURSOR
+	if s.FetchSize > 0 {
+		payload[4] = cursorTypeReadOnly
+	}
+	binary.LittleEndian.PutUint32(payload[5:], 1)
+	if len(args) == 0 {
+		return payload, nil
+	}
+
+	// NULL bitmap, new_params_bound_flag(1), types(2 per parameter), values
+	nullBitmap := len(payload)
+	payload = append(payload, make([]byte, (len(args)+7)/8)...)
+	payload = append(payload, 0x01)
+	types := len(payload)
+	payload = append(payload, make([]byte, 2*len(args))...)
+
+	for i, arg := range args {
+		var fieldType byte
+		var unsigned bool
+		var err error
+		if payload, fieldType, unsigned, err = s.conn.appendParam(payload, arg); err != nil {
+			return nil, fmt.Errorf("mysqldriver: argument %d: %v", i, err)
+		}
+		if fieldType == fieldTypeNULL {
+			payload[nullBitmap+i/8] |= 1 << uint(i%8)
+		}
+		payload[types+2*i] = fieldType
+		if unsigned {
+			payload[types+2*i+1] = 0x80
+		}
+	}
+	return payload, nil
+}
+
+// appendParam appends value of the parameter in the binary protocol
+// and returns its type and unsigned flag
+func (c *Conn) appendParam(payload []byte, arg interface{}) ([]byte, byte, bool, error) {
+	switch v := arg.(type) {
+	case nil:
+		return payload, fieldTypeNULL, false, nil
+	case string:
+		return appendLengthEncodedString(payload, v), fieldTypeVarString, false, nil
+	case []byte:
+		if v == nil {
+			return payload, fieldTypeNULL, false, nil
+		}
+		payload = appendLengthEncodedInteger(payload, uint64(len(v)))
+		return append(payload, v...), fieldTypeBLOB, false, nil
+	case bool:
+		if v {
+			return append(payload, 1), fieldTypeTiny, false, nil
+		}
+		return append(payload, 0), fieldTypeTiny, false, nil
+	case int:
+		return appendUint64(payload, uint64(v)), fieldTypeLongLong, false, nil
+	case int8:
+		return append(payload, byte(v)), fieldTypeTiny, false, nil
+	case int16:
+		return append(payload, byte(v), byte(v>>8)), fieldTypeShort, false, nil
+	case int32:
+		return appendUint32(payload, uint32(v)), fieldTypeLong, false, nil
+	case int64:
+		return appendUint64(payload, uint64(v)), fieldTypeLongLong, false, nil
+	case uint:
+		return appendUint64(payload, uint64(v)), fieldTypeLongLong, true, nil
+	case uint8:
+		return append(payload, v), fieldTypeTiny, true, nil
+	case uint16:
+		return append(payload, byte(v), byte(v>>8)), fieldTypeShort, true, nil
+	case uint32:
+		return appendUint32(payload, v), fieldTypeLong, true, nil
+	case uint64:
+		return appendUint64(payload, v), fieldTypeLongLong, true, nil
+	case float32:
+		return appendUint32(payload, math.Float32bits(v)), fieldTypeFloat, false, nil
+	case float64:
+		return appendUint64(payload, math.Float64bits(v)), fieldTypeDouble, false, nil
+	case time.Time:
+		if v.IsZero() {
+			return append(payload, 0), fieldTypeDateTime, false, nil
+		}
+		v = v.In(c.location())
+		payload = append(payload, 11, byte(v.Year()), byte(v.Year()>>8), byte(v.Month()), byte(v.Day()),
+			byte(v.Hour()), byte(v.Minute()), byte(v.Second()))
+		return appendUint32(payload, uint32(v.Nanosecond()/1000)), fieldTypeDateTime, false, nil
+	}
+	return nil, 0, false, fmt.Errorf("unsupported type %T", arg)
+}
+
+func appendUint32(payload []byte, num uint32) []byte {
+	return append(payload, byte(num), byte(num>>8), byte(num>>16), byte(num>>24))
+}
+
+func appendUint64(payload []byte, num uint64) []byte {
+	return appendUint32(appendUint32(payload, uint32(num)), uint32(num>>32))
+}
+
+// Close deallocates prepared statement on the server
+// by sending COM_STMT_CLOSE command
+func (s *Stmt) Close() error {
+	if s.closed {
+		return nil
+	}
+
+	if err := s.conn.acquire(); err != nil {
+		return err
+	}
+	defer s.conn.release()
+
+	s.closed = true
+	id := make([]byte, 4)
+	binary.LittleEndian.PutUint32(id, s.id)
+	// server doesn't send response to COM_STMT_CLOSE
+	if _, err := s.conn.conn.Write(commandPacket(comStmtClose, id)); err != nil {
+		s.conn.valid = false
+		return err
+	}
+	return nil
+}
+
+// readColumnDefinitions reads column definition packets
+// followed by EOF_PACKET when EOF isn't deprecated
+func (c *Conn) readColumnDefinitions(count int) ([]ColumnInfo, error) {
+	if count == 0 {
+		return nil, nil
+	}
+
+	columns := make([]ColumnInfo, count)
+	for i := range columns {
+		packet, err := c.conn.NextPacket()
+		if err != nil {
+			return nil, err
+		}
+		// definitions can't start with 0xfe or 0xff, so it's EOF, OK or ERR packet
+		if isEOFPacket(packet.Payload) || len(packet.Payload) > 0 && packet.Payload[0] == mysqlproto.ERR_PACKET {
+			return nil, &ColumnCountError{Declared: count, Read: i}
+		}
+		if columns[i], err = parseColumnDefinition(packet.Payload, c.extendedMetadata); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.conn.CapabilityFlags&mysqlproto.CLIENT_DEPRECATE_EOF == 0 {
+		packet, err := c.conn.NextPacket()
+		if err != nil {
+			return nil, err
+		}
+		if !isEOFPacket(packet.Payload) {
+			return nil, errMalformedPacket
+		}
+		// header(1), warnings(2), status_flags(2)
+		if len(packet.Payload) >= 5 {
+			c.status = binary.LittleEndian.Uint16(packet.Payload[3:])
+		}
+	}
+
+	return columns, nil
+}
@@ -0,0 +1,144 @@
+package mysqldriver
+
+import (
+	"testing"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnPrepareReturnsMetadata(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		stmt, err := conn.Prepare("SELECT id, firstname FROM people WHERE age > ? AND firstname = ?")
+		assert.NoError(t, err)
+
+		assert.Len(t, stmt.Params(), 2)
+		columns := stmt.ResultColumns()
+		assert.Len(t, columns, 2)
+		assert.Equal(t, columns[0].Name, "id")
+		assert.Equal(t, columns[0].OrgTable, "people")
+		assert.Equal(t, columns[0].Type, fieldTypeLong)
+		assert.False(t, columns[0].Nullable())
+		assert.Equal(t, columns[1].Name, "firstname")
+		assert.Equal(t, columns[1].Type, fieldTypeVarString)
+		assert.True(t, columns[1].Nullable())
+
+		assert.NoError(t, stmt.Close())
+		assert.NoError(t, stmt.Close())
+
+		// connection is usable after the statement
+		rows, err := conn.Query("SELECT 1")
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.Int(), 1)
+		assert.False(t, rows.Next())
+	})
+}
+
+func TestConnPrepareWithoutResultColumns(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		stmt, err := conn.Prepare("INSERT INTO people(firstname) VALUES (?)")
+		assert.NoError(t, err)
+		assert.Len(t, stmt.Params(), 1)
+		assert.Len(t, stmt.ResultColumns(), 0)
+		assert.NoError(t, stmt.Close())
+	})
+}
+
+func TestConnPrepareReturnsServerError(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Prepare("SELECT * FROM unknown_table WHERE id = ?")
+		_, ok := err.(mysqlproto.ERRPacket)
+		assert.True(t, ok)
+		assert.True(t, conn.valid)
+	})
+}
+
+func TestStmtExecutePayload(t *testing.T) {
+	stmt := &Stmt{conn: &Conn{}, id: 7, params: make([]ColumnInfo, 3)}
+	payload, err := stmt.executePayload([]interface{}{nil, "ab", uint16(300)})
+	assert.NoError(t, err)
+	assert.Equal(t, payload, []byte{
+		0x07, 0x00, 0x00, 0x00, // statement id
+		0x00,                   // flags
+		0x01, 0x00, 0x00, 0x00, // iteration count
+		0x01, // NULL bitmap
+		0x01, // new params bound
+		fieldTypeNULL, 0x00, fieldTypeVarString, 0x00, fieldTypeShort, 0x80,
+		0x02, 'a', 'b',
+		0x2c, 0x01,
+	})
+
+	_, err = stmt.executePayload([]interface{}{1})
+	assert.Equal(t, err, &ArgCountError{Placeholders: 3, Args: 1})
+
+	_, err = stmt.executePayload([]interface{}{1, 2, struct{}{}})
+	assert.EqualError(t, err, "mysqldriver: argument 2: unsupported type struct {}")
+}
+
+func TestStmtQueryReadsBinaryRows(t *testing.T) {
+	id := columnDefinition("id")
+	id[len(id)-6] = fieldTypeLong
+	conn := newPacketConn(
+		[]byte{0x02},
+		id,
+		columnDefinition("name"),
+		eofPacket,
+		[]byte{0x00, 0x00, 0x2a, 0x00, 0x00, 0x00, 0x03, 'b', 'o', 'b'},
+		[]byte{0x00, 0x08, 0x2b, 0x00, 0x00, 0x00}, // name is NULL
+		eofPacket,
+	)
+	stmt := &Stmt{conn: conn, id: 1}
+
+	rows, err := stmt.Query()
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Int(), 42)
+	assert.Equal(t, rows.String(), "bob")
+	assert.True(t, rows.Next())
+	row := rows.Row()
+	assert.Equal(t, row.Int("id"), 43)
+	_, null := row.NullString("name")
+	assert.True(t, null)
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.LastError())
+	assert.Equal(t, conn.acquire(), nil)
+}
+
+func TestStmtQueryAndExec(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		insert, err := conn.Prepare("INSERT INTO people(firstname, age, married) VALUES (?, ?, ?)")
+		assert.NoError(t, err)
+		for i, name := range []string{"bob", "ben"} {
+			pkt, err := insert.Exec(name, 20+i, i == 0)
+			assert.NoError(t, err)
+			assert.Equal(t, pkt.AffectedRows, uint64(1))
+		}
+		_, err = insert.Exec(nil, nil, nil)
+		assert.NoError(t, err)
+		assert.NoError(t, insert.Close())
+
+		stmt, err := conn.Prepare("SELECT firstname, age, married FROM people WHERE age >= ? ORDER BY id")
+		assert.NoError(t, err)
+		defer stmt.Close()
+
+		rows, err := stmt.Query(int64(20))
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.String(), "bob")
+		assert.Equal(t, rows.Int(), 20)
+		assert.True(t, rows.Bool())
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.String(), "ben")
+		assert.Equal(t, rows.Int(), 21)
+		assert.False(t, rows.Bool())
+		assert.False(t, rows.Next())
+		assert.NoError(t, rows.LastError())
+
+		_, err = stmt.Query()
+		assert.Equal(t, err, &ArgCountError{Placeholders: 1, Args: 0})
+		assert.NoError(t, stmt.Close())
+		_, err = stmt.Query(1)
+		assert.Equal(t, err, ErrStmtClosed)
+	})
+}
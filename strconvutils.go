@@ -29,11 +29,13 @@ OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
 */
 
 // Taken from https://github.com/golang/go/blob/master/src/strconv/atoi.go
-// and adapted to parse from []byte instead of string.
+// and adapted to parse from []byte instead of string, so values
+// aren't copied into strings unless they can't be parsed.
 
 package mysqldriver
 import (
 	"strconv"
+	"unsafe"
 )
 
 func atoi(s []byte) (int, error) {
@@ -66,13 +68,106 @@ func atoi(s []byte) (int, error) {
 	}
 
 	// Slow path for invalid, big, or underscored integers.
-	i64, err := strconv.ParseInt(string(s), 10, 0)
+	i64, err := parseInt(s, 0)
 	if nerr, ok := err.(*strconv.NumError); ok {
 		nerr.Func = fnAtoi
 	}
 	return int(i64), err
 }
 
+// parseUint is strconv.ParseUint(string(s), 10, bitSize)
+func parseUint(s []byte, bitSize int) (uint64, error) {
+	const fnParseUint = "ParseUint"
+
+	if len(s) == 0 {
+		return 0, syntaxError(fnParseUint, "")
+	}
+	if bitSize == 0 {
+		bitSize = strconv.IntSize
+	}
+
+	// Cutoff is the smallest number such that cutoff*10 > maxUint64.
+	const cutoff = (1<<64-1)/10 + 1
+	maxVal := uint64(1)<<uint(bitSize) - 1
+
+	var n uint64
+	for _, c := range s {
+		d := c - '0'
+		if d > 9 {
+			return 0, syntaxError(fnParseUint, string(s))
+		}
+
+		if n >= cutoff {
+			// n*10 overflows
+			return maxVal, rangeError(fnParseUint, string(s))
+		}
+		n *= 10
+
+		n1 := n + uint64(d)
+		if n1 < n || n1 > maxVal {
+			// n+d overflows
+			return maxVal, rangeError(fnParseUint, string(s))
+		}
+		n = n1
+	}
+	return n, nil
+}
+
+// parseInt is strconv.ParseInt(string(s), 10, bitSize)
+func parseInt(s []byte, bitSize int) (int64, error) {
+	const fnParseInt = "ParseInt"
+
+	if len(s) == 0 {
+		return 0, syntaxError(fnParseInt, "")
+	}
+
+	// Pick off leading sign.
+	s0 := s
+	neg := false
+	if s[0] == '+' {
+		s = s[1:]
+	} else if s[0] == '-' {
+		neg = true
+		s = s[1:]
+	}
+
+	// Convert unsigned and check range.
+	un, err := parseUint(s, 64)
+	if err != nil && err.(*strconv.NumError).Err != strconv.ErrRange {
+		err.(*strconv.NumError).Func = fnParseInt
+		err.(*strconv.NumError).Num = string(s0)
+		return 0, err
+	}
+
+	if bitSize == 0 {
+		bitSize = strconv.IntSize
+	}
+
+	cutoff := uint64(1 << uint(bitSize-1))
+	if !neg && un >= cutoff {
+		return int64(cutoff - 1), rangeError(fnParseInt, string(s0))
+	}
+	if neg && un > cutoff {
+		return -int64(cutoff), rangeError(fnParseInt, string(s0))
+	}
+	n := int64(un)
+	if neg {
+		n = -n
+	}
+	return n, nil
+}
+
+// parseFloat is strconv.ParseFloat(string(s), bitSize).
+// The string shares memory with s, so it's copied
+// only when the error keeps it.
+func parseFloat(s []byte, bitSize int) (float64, error) {
+	num, err := strconv.ParseFloat(*(*string)(unsafe.Pointer(&s)), bitSize)
+	if nerr, ok := err.(*strconv.NumError); ok {
+		nerr.Num = string(s)
+	}
+	return num, err
+}
+
 func parseBool(str []byte) (bool, error) {
 	switch string(str) {
 	case "1", "t", "T", "true", "TRUE", "True":
@@ -86,3 +181,7 @@ func parseBool(str []byte) (bool, error) {
 func syntaxError(fn, str string) *strconv.NumError {
 	return &strconv.NumError{fn, str, strconv.ErrSyntax}
 }
+
+func rangeError(fn, str string) *strconv.NumError {
+	return &strconv.NumError{Func: fn, Num: str, Err: strconv.ErrRange}
+}
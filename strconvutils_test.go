@@ -0,0 +1,77 @@
+package mysqldriver
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var numbers = []string{
+	"", "0", "1", "-1", "+1", "-", "+", "42", "007", "1.5", "1e3", "12a", " 1", "1_000",
+	"127", "128", "-128", "-129", "255", "256", "32767", "-32769", "65535", "65536",
+	"2147483647", "-2147483649", "4294967295", "4294967296",
+	"9223372036854775807", "9223372036854775808", "-9223372036854775808", "-9223372036854775809",
+	"18446744073709551615", "18446744073709551616", "99999999999999999999999",
+}
+
+func TestParseIntIsStrconvParseInt(t *testing.T) {
+	for _, bitSize := range []int{0, 8, 16, 32, 64} {
+		for _, str := range numbers {
+			expected, expectedErr := strconv.ParseInt(str, 10, bitSize)
+			num, err := parseInt([]byte(str), bitSize)
+			assert.Equal(t, num, expected, str)
+			assert.Equal(t, err, expectedErr, str)
+		}
+	}
+}
+
+func TestParseUintIsStrconvParseUint(t *testing.T) {
+	for _, bitSize := range []int{0, 8, 16, 32, 64} {
+		for _, str := range numbers {
+			expected, expectedErr := strconv.ParseUint(str, 10, bitSize)
+			num, err := parseUint([]byte(str), bitSize)
+			assert.Equal(t, num, expected, str)
+			assert.Equal(t, err, expectedErr, str)
+		}
+	}
+}
+
+func TestParseFloatCopiesValueOfError(t *testing.T) {
+	value := []byte("1.5x")
+	_, err := parseFloat(value, 64)
+	copy(value, "0000")
+	assert.EqualError(t, err, `strconv.ParseFloat: parsing "1.5x": invalid syntax`)
+
+	num, err := parseFloat([]byte("-1.25e2"), 64)
+	assert.NoError(t, err)
+	assert.Equal(t, num, -125.0)
+}
+
+func TestRowsNumbersDontAllocate(t *testing.T) {
+	conn := newPacketConn([]byte{0x03}, columnDefinition("id"), columnDefinition("age"), columnDefinition("score"), eofPacket)
+	rows, err := conn.Query("SELECT id, age, score FROM people")
+	assert.NoError(t, err)
+	packet := []byte{0x0a, '9', '2', '2', '3', '3', '7', '2', '0', '3', '6', 0x02, '4', '2', 0x04, '1', '.', '2', '5'}
+
+	allocs := testing.AllocsPerRun(1000, func() {
+		rows.setRow(packet)
+		rows.Int64()
+		rows.Uint8()
+		rows.Float64()
+	})
+	assert.Equal(t, allocs, 0.0)
+	assert.NoError(t, rows.LastError())
+}
+
+func BenchmarkRowsNullInt64(b *testing.B) {
+	conn := newPacketConn([]byte{0x01}, columnDefinition("id"), eofPacket)
+	rows, _ := conn.Query("SELECT id FROM people")
+	packet := []byte{0x07, '1', '2', '3', '4', '5', '6', '7'}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rows.setRow(packet)
+		rows.NullInt64()
+	}
+}
@@ -0,0 +1,84 @@
+package mysqldriver
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+)
+
+// ErrTLSNotSupported is returned by NewConnOptions when TLS
+// is requested, but the server doesn't have CLIENT_SSL capability
+var ErrTLSNotSupported = errors.New("mysqldriver: server doesn't support TLS")
+
+// TLSMode defines whether connection is encrypted
+// and how the certificate of the server is verified
+type TLSMode int
+
+const (
+	// TLSDisabled is a plaintext connection
+	TLSDisabled TLSMode = iota
+	// TLSRequired encrypts the connection,
+	// but doesn't verify the certificate of the server
+	TLSRequired
+	// TLSVerifyFull encrypts the connection and verifies
+	// the certificate chain and the host name of the server
+	TLSVerifyFull
+)
+
+// sslRequestLen is the length of SSLRequest payload
+// which is the same as the beginning of the handshake response
+const sslRequestLen = 32
+
+// tlsConfig returns configuration of the TLS client for the address
+// or nil when connection isn't encrypted
+func (opts Options) tlsConfig(protocol, address string) *tls.Config {
+	mode := opts.TLSMode
+	if mode == TLSDisabled {
+		if opts.TLSConfig == nil {
+			return nil
+		}
+		mode = TLSVerifyFull
+	}
+
+	config := &tls.Config{}
+	if opts.TLSConfig != nil {
+		config = opts.TLSConfig.Clone()
+	}
+	if config.ServerName == "" && protocol != "unix" {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			host = address
+		}
+		config.ServerName = host
+	}
+	if mode == TLSRequired {
+		config.InsecureSkipVerify = true
+	}
+	return config
+}
+
+// startTLS upgrades the connection with TLS after SSLRequest
+// is sent, so credentials and auth exchange aren't sent in plaintext
+func (c *netConn) startTLS(config *tls.Config) error {
+	encrypted := tls.Client(c.Conn, config)
+	if err := encrypted.Handshake(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.Conn = encrypted
+	c.mu.Unlock()
+	return nil
+}
+
+// TLSConnectionState returns the state of TLS connection
+// and false when connection isn't encrypted
+func (c *Conn) TLSConnectionState() (tls.ConnectionState, bool) {
+	if c.netConn == nil {
+		return tls.ConnectionState{}, false
+	}
+	encrypted, ok := c.netConn.Conn.(*tls.Conn)
+	if !ok {
+		return tls.ConnectionState{}, false
+	}
+	return encrypted.ConnectionState(), true
+}
@@ -0,0 +1,104 @@
+package mysqldriver
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// testCertificate generates self-signed certificate of localhost
+func testCertificate(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestConnectHandshakeTLS(t *testing.T) {
+	certificate := testCertificate(t)
+	conn, done := startAuthServer(t, func(s *authServer) {
+		seq, request := s.read()
+		assert.Equal(t, seq, byte(1))
+		assert.Equal(t, len(request), sslRequestLen)
+		flags := binary.LittleEndian.Uint32(request)
+		assert.True(t, flags&mysqlproto.CLIENT_SSL != 0)
+
+		encrypted := tls.Server(s.conn, &tls.Config{Certificates: []tls.Certificate{certificate}})
+		s.conn = encrypted
+		seq, response := s.read()
+		assert.Equal(t, seq, byte(2))
+		assert.Equal(t, response[:sslRequestLen], request)
+
+		// password is sent in plaintext over TLS
+		s.write(3, []byte{authMoreData, cachingSHA2PerformFullAuth})
+		_, password := s.read()
+		assert.Equal(t, password, []byte("secret\x00"))
+		s.write(5, okPayload)
+	})
+	defer conn.Close()
+
+	stream, err := connectHandshake(conn, capabilityFlags, charsetUTF8Code, nil, "root", "secret", "test", &tls.Config{InsecureSkipVerify: true}, false, 0)
+	assert.NoError(t, err)
+	assert.True(t, stream.CapabilityFlags&mysqlproto.CLIENT_SSL != 0)
+
+	c := &Conn{netConn: conn}
+	state, ok := c.TLSConnectionState()
+	assert.True(t, ok)
+	assert.True(t, state.HandshakeComplete)
+	<-done
+}
+
+func TestConnectHandshakeRequiresServerTLSCapability(t *testing.T) {
+	payload := append([]byte(nil), handshakePayload...)
+	payload[22] &^= byte(CapabilitySSL >> 8)
+	data := append([]byte{byte(len(payload)), 0x00, 0x00, 0x00}, payload...)
+	recorder := &sessionConn{readRecorder: readRecorder{data: bytes.NewReader(data)}}
+
+	_, err := connectHandshake(&netConn{Conn: recorder}, capabilityFlags, charsetUTF8Code, nil, "root", "secret", "test", &tls.Config{}, false, 0)
+	assert.Equal(t, err, ErrTLSNotSupported)
+	assert.Equal(t, len(recorder.written), 0)
+}
+
+func TestConnTLSConnectionStateOfPlaintextConnection(t *testing.T) {
+	_, ok := (&Conn{netConn: &netConn{Conn: &stream{}}}).TLSConnectionState()
+	assert.False(t, ok)
+}
+
+func TestOptionsTLSConfig(t *testing.T) {
+	assert.Nil(t, Options{}.tlsConfig("tcp", "db.example.com:3306"))
+
+	config := Options{TLSMode: TLSVerifyFull}.tlsConfig("tcp", "db.example.com:3306")
+	assert.Equal(t, config.ServerName, "db.example.com")
+	assert.False(t, config.InsecureSkipVerify)
+
+	config = Options{TLSMode: TLSRequired}.tlsConfig("tcp", "db.example.com:3306")
+	assert.True(t, config.InsecureSkipVerify)
+
+	custom := &tls.Config{ServerName: "mysql.internal"}
+	config = Options{TLSConfig: custom}.tlsConfig("tcp", "10.0.0.1:3306")
+	assert.Equal(t, config.ServerName, "mysql.internal")
+	assert.False(t, config.InsecureSkipVerify)
+	assert.True(t, config != custom)
+
+	config = Options{TLSMode: TLSRequired}.tlsConfig("unix", "/var/run/mysqld/mysqld.sock")
+	assert.Equal(t, config.ServerName, "")
+}
@@ -0,0 +1,252 @@
+package mysqldriver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pubnative/mysqlproto-go"
+)
+
+// ErrTxDone is returned when transaction is used
+// after it has been committed or rolled back
+var ErrTxDone = errors.New("mysqldriver: transaction has already been committed or rolled back")
+
+// ErrReadOnlyTx is returned by Exec of read-only transaction
+// when statement obviously modifies data
+var ErrReadOnlyTx = errors.New("mysqldriver: can't execute write statement in read-only transaction")
+
+// ErrTxInProgress is returned when transaction is started
+// while the previous transaction of the connection isn't finished
+var ErrTxInProgress = errors.New("mysqldriver: connection already has a transaction in progress")
+
+// Tx represents transaction started on the connection.
+// Connection mustn't be used for other queries until
+// transaction is committed or rolled back.
+type Tx struct {
+	conn     *Conn
+	readOnly bool
+	done     bool
+}
+
+// Begin starts transaction by sending "START TRANSACTION" command.
+// Transaction must be finished by Commit or Rollback, until then
+// Begin of the connection returns ErrTxInProgress.
+//
+//	tx, err := conn.Begin()
+//	if err != nil {
+//		// handle error
+//	}
+//	if _, err := tx.Exec("DELETE FROM dogs WHERE id = 1"); err != nil {
+//		tx.Rollback()
+//		// handle error
+//	}
+//	err = tx.Commit()
+func (c *Conn) Begin() (*Tx, error) {
+	return c.BeginTx(TxOptions{})
+}
+
+// BeginTx starts transaction with the given options (see func (Conn) Begin)
+//
+//	tx, err := conn.BeginTx(mysqldriver.TxOptions{Isolation: mysqldriver.LevelReadCommitted})
+func (c *Conn) BeginTx(opts TxOptions) (*Tx, error) {
+	return c.BeginTxContext(context.Background(), opts)
+}
+
+// BeginTxContext starts transaction with the given options.
+// Statements starting the transaction are aborted
+// when ctx is done (see func (Conn) ExecContext).
+func (c *Conn) BeginTxContext(ctx context.Context, opts TxOptions) (*Tx, error) {
+	if c.tx != nil {
+		return nil, ErrTxInProgress
+	}
+
+	if opts.Isolation != LevelDefault {
+		// applies only to the next transaction of the session
+		if _, err := c.ExecContext(ctx, "SET TRANSACTION ISOLATION LEVEL "+string(opts.Isolation)); err != nil {
+			return nil, err
+		}
+	}
+
+	sql := "START TRANSACTION"
+	if opts.ReadOnly {
+		sql = "START TRANSACTION READ ONLY"
+	}
+	return c.begin(ctx, sql, opts.ReadOnly)
+}
+
+// BeginReadOnly starts read-only transaction by sending
+// "START TRANSACTION READ ONLY" command. Server is able to optimize
+// such transactions and proxies may route them to the replicas.
+//
+// Exec of the transaction rejects the obvious write statements like
+// INSERT, UPDATE or DELETE with ErrReadOnlyTx without sending them
+// to the server. The check is best-effort, all other writes
+// fail on the server side with ERRPacket.
+func (c *Conn) BeginReadOnly() (*Tx, error) {
+	return c.begin(context.Background(), "START TRANSACTION READ ONLY", true)
+}
+
+// IsolationLevel is transaction isolation level
+// (see https://dev.mysql.com/doc/refman/8.0/en/innodb-transaction-isolation-levels.html)
+type IsolationLevel string
+
+const (
+	// LevelDefault uses isolation level of the session
+	LevelDefault         IsolationLevel = ""
+	LevelReadUncommitted IsolationLevel = "READ UNCOMMITTED"
+	LevelReadCommitted   IsolationLevel = "READ COMMITTED"
+	LevelRepeatableRead  IsolationLevel = "REPEATABLE READ"
+	LevelSerializable    IsolationLevel = "SERIALIZABLE"
+)
+
+// TxOptions configures transaction started by BeginTx or WithTransactionOptions
+type TxOptions struct {
+	Isolation IsolationLevel
+	ReadOnly  bool // see func (Conn) BeginReadOnly
+}
+
+// WithTransaction runs fn within the transaction. Transaction is
+// committed when fn returns nil and it's rolled back when fn returns
+// an error or panics. Error of fn is returned as it is and panic
+// is propagated after rollback. When rollback fails, connection
+// becomes invalid and the rollback error is returned together
+// with the error of fn.
+//
+// fn must read all rows of its queries, otherwise the transaction
+// can't be finished because connection is busy.
+//
+//	err := conn.WithTransaction(func(tx *mysqldriver.Tx) error {
+//		if _, err := tx.Exec("UPDATE accounts SET balance = balance - 10 WHERE id = 1"); err != nil {
+//			return err
+//		}
+//		_, err := tx.Exec("UPDATE accounts SET balance = balance + 10 WHERE id = 2")
+//		return err
+//	})
+func (c *Conn) WithTransaction(fn func(*Tx) error) error {
+	return c.WithTransactionOptions(TxOptions{}, fn)
+}
+
+// WithTransactionOptions runs fn within the transaction
+// started with the given options (see func (Conn) WithTransaction)
+func (c *Conn) WithTransactionOptions(opts TxOptions, fn func(*Tx) error) (err error) {
+	tx, err := c.BeginTx(opts)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			c.rollback(tx)
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rollbackErr := c.rollback(tx); rollbackErr != nil {
+			return fmt.Errorf("%w (mysqldriver: rollback failed: %v)", err, rollbackErr)
+		}
+		return err
+	}
+
+	// fn has already finished the transaction by itself
+	if tx.done {
+		return nil
+	}
+	return tx.Commit()
+}
+
+// rollback rolls back the transaction unless it's finished already.
+// State of the transaction on the server is unknown when rollback
+// fails, so connection is marked as invalid.
+func (c *Conn) rollback(tx *Tx) error {
+	if tx.done {
+		return nil
+	}
+	if err := tx.Rollback(); err != nil {
+		c.valid = false
+		return err
+	}
+	return nil
+}
+
+func (c *Conn) begin(ctx context.Context, sql string, readOnly bool) (*Tx, error) {
+	if c.tx != nil {
+		return nil, ErrTxInProgress
+	}
+	if _, err := c.ExecContext(ctx, sql); err != nil {
+		return nil, err
+	}
+	c.tx = &Tx{conn: c, readOnly: readOnly}
+	return c.tx, nil
+}
+
+// Query performs query within the transaction (see func (Conn) Query)
+func (tx *Tx) Query(sql string) (*Rows, error) {
+	if tx.done {
+		return nil, ErrTxDone
+	}
+	return tx.conn.Query(sql)
+}
+
+// Exec executes statement within the transaction (see func (Conn) Exec)
+func (tx *Tx) Exec(sql string) (mysqlproto.OKPacket, error) {
+	if tx.done {
+		return mysqlproto.OKPacket{}, ErrTxDone
+	}
+	if tx.readOnly && isWriteStatement(sql) {
+		return mysqlproto.OKPacket{}, ErrReadOnlyTx
+	}
+	return tx.conn.Exec(sql)
+}
+
+// Commit commits the transaction
+func (tx *Tx) Commit() error {
+	return tx.finish("COMMIT")
+}
+
+// Rollback aborts the transaction
+func (tx *Tx) Rollback() error {
+	return tx.finish("ROLLBACK")
+}
+
+// finish sends COMMIT or ROLLBACK. Transaction is finished when the
+// server has answered or the connection became invalid, otherwise
+// (e.g. ErrConnectionBusy) it's still in progress on the server
+// and it can be finished again.
+func (tx *Tx) finish(sql string) error {
+	if tx.done {
+		return ErrTxDone
+	}
+	_, err := tx.conn.Exec(sql)
+	if _, answered := ErrorCode(err); err == nil || answered || !tx.conn.valid {
+		tx.done = true
+		tx.conn.tx = nil
+	}
+	return err
+}
+
+var writeStatements = []string{
+	"INSERT", "UPDATE", "DELETE", "REPLACE", "LOAD",
+	"CREATE", "ALTER", "DROP", "RENAME", "TRUNCATE",
+}
+
+// isWriteStatement reports whether statement starts with
+// the keyword of the data modification statement
+func isWriteStatement(sql string) bool {
+	sql = strings.TrimLeft(sql, " \t\r\n(")
+	for _, keyword := range writeStatements {
+		if len(sql) < len(keyword) || !strings.EqualFold(sql[:len(keyword)], keyword) {
+			continue
+		}
+		if len(sql) == len(keyword) {
+			return true
+		}
+		switch sql[len(keyword)] {
+		case ' ', '\t', '\r', '\n', '(':
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+package mysqldriver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsWriteStatement(t *testing.T) {
+	assert.True(t, isWriteStatement("INSERT INTO people VALUES (1)"))
+	assert.True(t, isWriteStatement("  update people SET age = 1"))
+	assert.True(t, isWriteStatement("\nDelete FROM people"))
+	assert.True(t, isWriteStatement("TRUNCATE"))
+	assert.True(t, isWriteStatement("REPLACE(people)"))
+	assert.False(t, isWriteStatement("SELECT * FROM people"))
+	assert.False(t, isWriteStatement("SET @updated = 1"))
+	assert.False(t, isWriteStatement("INSERTED"))
+	assert.False(t, isWriteStatement(""))
+}
+
+func TestTxBeginReadOnly(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec(`INSERT INTO people(firstname) VALUES ("Bob")`)
+		assert.NoError(t, err)
+
+		tx, err := conn.BeginReadOnly()
+		assert.NoError(t, err)
+
+		rows, err := tx.Query("SELECT firstname FROM people")
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		assert.Equal(t, rows.String(), "Bob")
+		assert.False(t, rows.Next())
+
+		_, err = tx.Exec(`INSERT INTO people(firstname) VALUES ("Alice")`)
+		assert.Equal(t, err, ErrReadOnlyTx)
+
+		assert.NoError(t, tx.Commit())
+		assert.Equal(t, tx.Commit(), ErrTxDone)
+		assert.Equal(t, tx.Rollback(), ErrTxDone)
+		_, err = tx.Query("SELECT 1")
+		assert.Equal(t, err, ErrTxDone)
+		_, err = tx.Exec("SELECT 1")
+		assert.Equal(t, err, ErrTxDone)
+	})
+}
+
+func TestTxRollbackOfBusyConnection(t *testing.T) {
+	conn, _ := newSessionConn("8.0.22", okPayload, okPayload)
+	tx, err := conn.BeginReadOnly()
+	assert.NoError(t, err)
+
+	// rows of the previous query aren't read
+	assert.NoError(t, conn.acquire())
+	assert.Equal(t, tx.Rollback(), ErrConnectionBusy)
+	assert.False(t, tx.done)
+	assert.True(t, conn.tx == tx)
+
+	conn.release()
+	assert.NoError(t, tx.Rollback())
+	assert.True(t, tx.done)
+	assert.Nil(t, conn.tx)
+}
+
+func TestConnBeginWhileRollbackOfBusyConnectionFailed(t *testing.T) {
+	conn, _ := newSessionConn("8.0.22", okPayload, okPayload, okPayload)
+	tx, err := conn.Begin()
+	assert.NoError(t, err)
+
+	assert.NoError(t, conn.acquire())
+	assert.Equal(t, tx.Rollback(), ErrConnectionBusy)
+	conn.release()
+
+	_, err = conn.Begin()
+	assert.Equal(t, err, ErrTxInProgress)
+	_, err = conn.BeginTx(TxOptions{})
+	assert.Equal(t, err, ErrTxInProgress)
+
+	assert.NoError(t, tx.Rollback())
+	_, err = conn.Begin()
+	assert.NoError(t, err)
+}
+
+func TestConnWithTransactionReturnsRollbackError(t *testing.T) {
+	conn, _ := newSessionConn("8.0.22", okPayload)
+	fnErr := errors.New("fn failed")
+	err := conn.WithTransaction(func(tx *Tx) error {
+		// rows of the query aren't read
+		assert.NoError(t, conn.acquire())
+		return fnErr
+	})
+	assert.True(t, errors.Is(err, fnErr))
+	assert.Contains(t, err.Error(), ErrConnectionBusy.Error())
+	assert.False(t, conn.valid)
+}
+
+func TestTxReadOnlyRejectsWritesOnServer(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		tx, err := conn.BeginReadOnly()
+		assert.NoError(t, err)
+
+		// bypass the check of the driver
+		_, err = tx.conn.Exec(`INSERT INTO people(firstname) VALUES ("Alice")`)
+		errPacket, ok := err.(mysqlproto.ERRPacket)
+		assert.True(t, ok)
+		assert.Equal(t, errPacket.ErrorCode, uint16(1792)) // ER_CANT_EXECUTE_IN_READ_ONLY_TRANSACTION
+
+		assert.NoError(t, tx.Rollback())
+	})
+}
+
+func countPeople(t *testing.T, conn *Conn) int {
+	rows, err := conn.Query("SELECT COUNT(*) FROM people")
+	assert.NoError(t, err)
+	var count int
+	for rows.Next() {
+		count = rows.Int()
+	}
+	assert.NoError(t, rows.LastError())
+	return count
+}
+
+func TestConnWithTransactionCommits(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		err := conn.WithTransactionOptions(TxOptions{Isolation: LevelSerializable}, func(tx *Tx) error {
+			_, err := tx.Exec(`INSERT INTO people(firstname) VALUES ("Bob")`)
+			return err
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, countPeople(t, conn), 1)
+	})
+}
+
+func TestConnWithTransactionRollbacksOnError(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		failure := errors.New("failure")
+		err := conn.WithTransaction(func(tx *Tx) error {
+			_, err := tx.Exec(`INSERT INTO people(firstname) VALUES ("Bob")`)
+			assert.NoError(t, err)
+			return failure
+		})
+		assert.Equal(t, err, failure)
+		assert.Equal(t, countPeople(t, conn), 0)
+	})
+}
+
+func TestConnWithTransactionRollbacksOnPanic(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		func() {
+			defer func() {
+				assert.Equal(t, recover(), "failure")
+			}()
+			conn.WithTransaction(func(tx *Tx) error {
+				_, err := tx.Exec(`INSERT INTO people(firstname) VALUES ("Bob")`)
+				assert.NoError(t, err)
+				panic("failure")
+			})
+		}()
+		assert.Equal(t, countPeople(t, conn), 0)
+	})
+}
+
+func TestConnWithTransactionReadOnly(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		err := conn.WithTransactionOptions(TxOptions{ReadOnly: true}, func(tx *Tx) error {
+			_, err := tx.Exec(`INSERT INTO people(firstname) VALUES ("Bob")`)
+			return err
+		})
+		assert.Equal(t, err, ErrReadOnlyTx)
+		assert.Equal(t, countPeople(t, conn), 0)
+	})
+}
+
+func TestConnBeginCommitAndRollback(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		tx, err := conn.Begin()
+		assert.NoError(t, err)
+		_, err = tx.Exec(`INSERT INTO people(firstname) VALUES ("Bob")`)
+		assert.NoError(t, err)
+		assert.NoError(t, tx.Commit())
+		assert.Equal(t, countPeople(t, conn), 1)
+
+		_, err = tx.Exec(`INSERT INTO people(firstname) VALUES ("Ben")`)
+		assert.Equal(t, err, ErrTxDone)
+		assert.Equal(t, tx.Rollback(), ErrTxDone)
+
+		tx, err = conn.BeginTx(TxOptions{Isolation: LevelReadCommitted})
+		assert.NoError(t, err)
+		_, err = tx.Exec(`INSERT INTO people(firstname) VALUES ("Ben")`)
+		assert.NoError(t, err)
+		assert.NoError(t, tx.Rollback())
+		assert.Equal(t, countPeople(t, conn), 1)
+	})
+}
+
+func TestConnBeginRefusesSecondTransaction(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		tx, err := conn.Begin()
+		assert.NoError(t, err)
+
+		_, err = conn.Begin()
+		assert.Equal(t, err, ErrTxInProgress)
+		_, err = conn.BeginReadOnly()
+		assert.Equal(t, err, ErrTxInProgress)
+		err = conn.WithTransaction(func(*Tx) error { return nil })
+		assert.Equal(t, err, ErrTxInProgress)
+
+		assert.NoError(t, tx.Rollback())
+		tx, err = conn.Begin()
+		assert.NoError(t, err)
+		assert.NoError(t, tx.Commit())
+	})
+}
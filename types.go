@@ -0,0 +1,53 @@
+package mysqldriver
+
+// MySQL column types
+// (see https://dev.mysql.com/doc/internals/en/com-query-response.html#column-type)
+const (
+	fieldTypeDecimal    byte = 0x00
+	fieldTypeTiny       byte = 0x01
+	fieldTypeShort      byte = 0x02
+	fieldTypeLong       byte = 0x03
+	fieldTypeFloat      byte = 0x04
+	fieldTypeDouble     byte = 0x05
+	fieldTypeNULL       byte = 0x06
+	fieldTypeTimestamp  byte = 0x07
+	fieldTypeLongLong   byte = 0x08
+	fieldTypeInt24      byte = 0x09
+	fieldTypeDate       byte = 0x0a
+	fieldTypeTime       byte = 0x0b
+	fieldTypeDateTime   byte = 0x0c
+	fieldTypeYear       byte = 0x0d
+	fieldTypeNewDate    byte = 0x0e
+	fieldTypeVarChar    byte = 0x0f
+	fieldTypeBit        byte = 0x10
+	fieldTypeJSON       byte = 0xf5
+	fieldTypeNewDecimal byte = 0xf6
+	fieldTypeEnum       byte = 0xf7
+	fieldTypeSet        byte = 0xf8
+	fieldTypeTinyBLOB   byte = 0xf9
+	fieldTypeMediumBLOB byte = 0xfa
+	fieldTypeLongBLOB   byte = 0xfb
+	fieldTypeBLOB       byte = 0xfc
+	fieldTypeVarString  byte = 0xfd
+	fieldTypeString     byte = 0xfe
+	fieldTypeGeometry   byte = 0xff
+)
+
+// Column definition flags
+const (
+	flagNotNULL  uint16 = 0x0001
+	flagUnsigned uint16 = 0x0020
+	flagZerofill uint16 = 0x0040
+	flagEnum     uint16 = 0x0100
+	flagSet      uint16 = 0x0800
+)
+
+// Server status flags
+// (see https://dev.mysql.com/doc/internals/en/status-flags.html)
+const (
+	serverMoreResultsExists        uint16 = 0x0008
+	serverStatusCursorExists       uint16 = 0x0040
+	serverStatusLastRowSent        uint16 = 0x0080
+	serverStatusNoBackslashEscapes uint16 = 0x0200
+	serverPSOutParams              uint16 = 0x1000
+)
@@ -0,0 +1,29 @@
+package mysqldriver
+
+// CurrentUser returns the account which is used by the server
+// to check privileges of the session as it's returned
+// by "SELECT CURRENT_USER()", e.g. "app@%". It can differ from
+// the user name passed to NewConn because of host matching,
+// anonymous users or proxy users. The result is cached,
+// so the query is performed only once per connection.
+func (c *Conn) CurrentUser() (string, error) {
+	if c.currentUser != "" {
+		return c.currentUser, nil
+	}
+
+	rows, err := c.Query("SELECT CURRENT_USER()")
+	if err != nil {
+		return "", err
+	}
+
+	var user string
+	for rows.Next() {
+		user = rows.String()
+	}
+	if err := rows.LastError(); err != nil {
+		return "", err
+	}
+
+	c.currentUser = user
+	return user, nil
+}
@@ -0,0 +1,25 @@
+package mysqldriver
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnCurrentUser(t *testing.T) {
+	conn, err := NewConn("root", "", "tcp", "127.0.0.1:3306", "test", time.Duration(0))
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	user, err := conn.CurrentUser()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(user, "root@"))
+
+	// cached value is returned without the query
+	conn.currentUser = "cached@%"
+	user, err = conn.CurrentUser()
+	assert.NoError(t, err)
+	assert.Equal(t, user, "cached@%")
+}
@@ -0,0 +1,55 @@
+package mysqldriver
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrUnknownColumn is returned by Value when the result set
+// doesn't have the column. Returned error is *ColumnError,
+// compare it using errors.Is.
+var ErrUnknownColumn = errors.New("mysqldriver: column doesn't exist")
+
+// ErrColumnNotRead is returned by Value when the column
+// isn't read yet on the current row. Returned error is *ColumnError,
+// compare it using errors.Is.
+var ErrColumnNotRead = errors.New("mysqldriver: column isn't read yet")
+
+// ColumnError contains the name of the column
+// which value can't be returned by Value
+type ColumnError struct {
+	Column string
+	Err    error // ErrUnknownColumn or ErrColumnNotRead
+}
+
+func (e *ColumnError) Error() string {
+	return e.Err.Error() + ": " + strconv.Quote(e.Column)
+}
+
+func (e *ColumnError) Is(target error) bool {
+	return target == e.Err
+}
+
+// Value returns the value of the column already read on the current row
+// and NULL indicator. When value is NULL, second parameter is true.
+// It allows to get back a column after the cursor moved past it,
+// e.g. when the columns are read out of order.
+// Returned slice is valid until the next call of Next.
+//
+//	rows, _ := conn.Query("SELECT id, name FROM dogs")
+//	for rows.Next() {
+//		rows.Scan(&id, &name)
+//		value, null, err := rows.Value("id") // value of id read by Scan
+//	}
+func (r *Rows) Value(name string) ([]byte, bool, error) {
+	if value, ok := r.columns[name]; ok {
+		return value.data, value.null, nil
+	}
+
+	for _, column := range r.definitions {
+		if column.Name == name {
+			return nil, false, &ColumnError{Column: name, Err: ErrColumnNotRead}
+		}
+	}
+	return nil, false, &ColumnError{Column: name, Err: ErrUnknownColumn}
+}
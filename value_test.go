@@ -0,0 +1,55 @@
+package mysqldriver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRowsValue(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x02}, columnDefinition("id"), columnDefinition("name"), eofPacket,
+		[]byte{0x01, '1', 0xfb},
+		[]byte{0x01, '2', 0x03, 'M', 'a', 'x'},
+		eofPacket,
+	)
+
+	rows, err := conn.Query("SELECT id, name FROM dogs")
+	assert.NoError(t, err)
+
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Int(), 1)
+	value, null, err := rows.Value("id")
+	assert.NoError(t, err)
+	assert.Equal(t, value, []byte("1"))
+	assert.False(t, null)
+
+	_, _, err = rows.Value("name")
+	assert.True(t, errors.Is(err, ErrColumnNotRead))
+	assert.Equal(t, err.Error(), `mysqldriver: column isn't read yet: "name"`)
+
+	rows.NullBytes()
+	value, null, err = rows.Value("name")
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+	assert.True(t, null)
+
+	_, _, err = rows.Value("age")
+	assert.True(t, errors.Is(err, ErrUnknownColumn))
+
+	// values of the previous row are cleared
+	assert.True(t, rows.Next())
+	_, _, err = rows.Value("id")
+	assert.True(t, errors.Is(err, ErrColumnNotRead))
+
+	assert.Equal(t, rows.String(), "2")
+	assert.Equal(t, rows.String(), "Max")
+	value, null, err = rows.Value("name")
+	assert.NoError(t, err)
+	assert.Equal(t, value, []byte("Max"))
+	assert.False(t, null)
+
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.LastError())
+}
@@ -0,0 +1,52 @@
+package mysqldriver
+
+// Warning is a row returned by "SHOW WARNINGS" statement
+type Warning struct {
+	Level   string // "Note", "Warning" or "Error"
+	Code    uint16 // error code, e.g. 1265 for data truncation
+	Message string
+}
+
+// WarningCount returns the number of warnings of the last statement
+// performed by Exec or Query. The count of SELECT statement is sent
+// after the last row, so it's available after all rows are read.
+func (c *Conn) WarningCount() int {
+	return int(c.warnings)
+}
+
+// Warnings returns the number of warnings sent after the last row
+// of the result set. It's zero until all rows are read.
+func (r *Rows) Warnings() uint16 {
+	return r.warnings
+}
+
+// ShowWarnings returns warnings of the last statement, e.g.
+// data truncation or implicit conversion of the inserted values,
+// as they're returned by "SHOW WARNINGS" statement.
+// Server keeps the warnings until the next statement which
+// uses tables, so it should be called right after the statement.
+//
+//	conn.Exec("INSERT INTO dogs(name) VALUES ('a name longer than the column')")
+//	if conn.WarningCount() > 0 {
+//		warnings, err := conn.ShowWarnings()
+//	}
+func (c *Conn) ShowWarnings() ([]Warning, error) {
+	rows, err := c.Query("SHOW WARNINGS")
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []Warning
+	for rows.Next() {
+		row := rows.Row()
+		warnings = append(warnings, Warning{
+			Level:   row.String("Level"),
+			Code:    row.Uint16("Code"),
+			Message: row.String("Message"),
+		})
+	}
+	if err := rows.LastError(); err != nil {
+		return nil, err
+	}
+	return warnings, nil
+}
@@ -0,0 +1,81 @@
+package mysqldriver
+
+import (
+	"testing"
+
+	"github.com/pubnative/mysqlproto-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRowsWarnings(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x01}, columnDefinition("id"), eofPacket,
+		[]byte{0x01, '1'},
+		[]byte{mysqlproto.EOF_PACKET, 0x02, 0x00, 0x02, 0x00},
+	)
+	conn.warnings = 5
+
+	rows, err := conn.Query("SELECT CAST('1x' AS SIGNED) AS id")
+	assert.NoError(t, err)
+	// count of the previous statement isn't reported
+	assert.Equal(t, conn.WarningCount(), 0)
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Warnings(), uint16(0))
+	assert.False(t, rows.Next())
+	assert.Equal(t, rows.Warnings(), uint16(2))
+	assert.Equal(t, conn.WarningCount(), 2)
+}
+
+func TestConnWarningCountOfExec(t *testing.T) {
+	conn := &Conn{}
+	conn.handleOKPacket(mysqlproto.OKPacket{Warnings: 3})
+	assert.Equal(t, conn.WarningCount(), 3)
+	assert.Equal(t, conn.Warnings(), uint16(3))
+}
+
+func TestConnWarningsIsWarningCountOfQuery(t *testing.T) {
+	conn := &Conn{}
+	conn.handleOKPacket(mysqlproto.OKPacket{Warnings: 3})
+	conn.warnings = 2 // sent after the last row
+	assert.Equal(t, conn.WarningCount(), 2)
+	assert.Equal(t, conn.Warnings(), uint16(2))
+}
+
+func TestConnShowWarnings(t *testing.T) {
+	conn := newPacketConn(
+		[]byte{0x03}, columnDefinition("Level"), columnDefinition("Code"), columnDefinition("Message"), eofPacket,
+		append([]byte{0x07}, "Warning\x041265\x1bData truncated for column 1"...),
+		append([]byte{0x04}, "Note\x041051\x0fUnknown table 1"...),
+		eofPacket,
+	)
+
+	warnings, err := conn.ShowWarnings()
+	assert.NoError(t, err)
+	assert.Equal(t, warnings, []Warning{
+		{Level: "Warning", Code: 1265, Message: "Data truncated for column 1"},
+		{Level: "Note", Code: 1051, Message: "Unknown table 1"},
+	})
+}
+
+func TestConnShowWarningsOfTruncatedValue(t *testing.T) {
+	setup(t, func(conn *Conn) {
+		_, err := conn.Exec("SET SESSION sql_mode = ''")
+		assert.NoError(t, err)
+		_, err = conn.Exec("INSERT INTO people(age) VALUES ('12 years')")
+		assert.NoError(t, err)
+		assert.Equal(t, conn.WarningCount(), 1)
+
+		warnings, err := conn.ShowWarnings()
+		assert.NoError(t, err)
+		assert.Len(t, warnings, 1)
+		assert.Equal(t, warnings[0].Level, "Warning")
+		assert.Equal(t, warnings[0].Code, uint16(1265))
+
+		rows, err := conn.Query("SELECT CAST('1x' AS SIGNED)")
+		assert.NoError(t, err)
+		for rows.Next() {
+		}
+		assert.Equal(t, rows.Warnings(), uint16(1))
+		assert.Equal(t, conn.WarningCount(), 1)
+	})
+}